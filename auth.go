@@ -0,0 +1,116 @@
+package oto
+
+import (
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// authScheme is a parsed oto:auth value, like "bearer",
+// "apikey header X-API-Key" or "oauth2 scopes:read,write".
+type authScheme struct {
+	// Scheme is bearer, basic, apikey, oauth2 or none.
+	Scheme string
+	// In is where the API key goes: header, query or cookie.
+	In string
+	// Name is the name of the API key parameter.
+	Name string
+	// Scopes are the OAuth2 scopes.
+	Scopes []string
+}
+
+// parseAuthScheme parses the value of an oto:auth comment line.
+// The parts are separated by spaces: the scheme, then (for apikey)
+// where the key goes and its name, or (for oauth2) the scopes.
+func parseAuthScheme(value string) (authScheme, error) {
+	var auth authScheme
+	parts := strings.Fields(value)
+	if len(parts) == 0 {
+		return auth, errors.New("oto:auth: missing scheme")
+	}
+	auth.Scheme = strings.ToLower(parts[0])
+	args := parts[1:]
+	switch auth.Scheme {
+	case "bearer", "basic", "none":
+		if len(args) > 0 {
+			return auth, errors.Errorf("oto:auth: %s takes no arguments", auth.Scheme)
+		}
+	case "apikey":
+		if len(args) != 2 {
+			return auth, errors.New("oto:auth: expected apikey header|query|cookie NAME")
+		}
+		auth.In, auth.Name = strings.ToLower(args[0]), args[1]
+		switch auth.In {
+		case "header", "query", "cookie":
+		default:
+			return auth, errors.Errorf("oto:auth: apikey cannot be in %s (expected header, query or cookie)", args[0])
+		}
+	case "oauth2":
+		for _, arg := range args {
+			scopes := strings.TrimPrefix(arg, "scopes:")
+			if scopes == arg {
+				return auth, errors.Errorf("oto:auth: unexpected %s (expected oauth2 scopes:SCOPE,SCOPE)", arg)
+			}
+			for _, scope := range strings.Split(scopes, ",") {
+				if scope != "" {
+					auth.Scopes = append(auth.Scopes, scope)
+				}
+			}
+		}
+	default:
+		return auth, errors.Errorf("oto:auth: unknown scheme %s (expected bearer, basic, apikey, oauth2 or none)", parts[0])
+	}
+	return auth, nil
+}
+
+// key gets the name of the security scheme in the OpenAPI
+// components.
+func (a authScheme) key() string {
+	if a.Scheme == "apikey" {
+		return "apikey_" + a.In + "_" + a.Name
+	}
+	return a.Scheme
+}
+
+// openAPISecurityScheme gets the OpenAPI security scheme object.
+func (a authScheme) openAPISecurityScheme() map[string]interface{} {
+	switch a.Scheme {
+	case "bearer", "basic":
+		return map[string]interface{}{
+			"type":   "http",
+			"scheme": a.Scheme,
+		}
+	case "apikey":
+		return map[string]interface{}{
+			"type": "apiKey",
+			"in":   a.In,
+			"name": a.Name,
+		}
+	}
+	// the flows (with their URLs) belong in the -openapi-base file
+	return map[string]interface{}{
+		"type":  "oauth2",
+		"flows": map[string]interface{}{},
+	}
+}
+
+// parseScopes parses the value of an oto:scopes comment line,
+// which is a comma separated list of scopes, like
+// "admin,billing:write".
+func parseScopes(value string) ([]string, error) {
+	if value == "" {
+		return nil, errors.New("oto:scopes: missing scopes")
+	}
+	var scopes []string
+	for _, scope := range strings.Split(value, ",") {
+		scope = strings.TrimSpace(scope)
+		if scope == "" {
+			return nil, errors.Errorf("oto:scopes: empty scope in %q", value)
+		}
+		if strings.ContainsAny(scope, " \t") {
+			return nil, errors.Errorf("oto:scopes: %q cannot contain spaces (expected SCOPE,SCOPE)", scope)
+		}
+		scopes = append(scopes, scope)
+	}
+	return scopes, nil
+}
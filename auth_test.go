@@ -0,0 +1,133 @@
+package oto
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/matryer/is"
+)
+
+func TestParseAuthScheme(t *testing.T) {
+	is := is.New(t)
+
+	auth, err := parseAuthScheme("bearer")
+	is.NoErr(err)
+	is.Equal(auth, authScheme{Scheme: "bearer"})
+	auth, err = parseAuthScheme("apikey header X-API-Key")
+	is.NoErr(err)
+	is.Equal(auth, authScheme{Scheme: "apikey", In: "header", Name: "X-API-Key"})
+	is.Equal(auth.key(), "apikey_header_X-API-Key")
+	auth, err = parseAuthScheme("oauth2 scopes:read,write")
+	is.NoErr(err)
+	is.Equal(auth, authScheme{Scheme: "oauth2", Scopes: []string{"read", "write"}})
+
+	for _, value := range []string{
+		"",
+		"digest",
+		"bearer token",
+		"apikey header",
+		"apikey body X-API-Key",
+		"oauth2 read,write",
+	} {
+		_, err := parseAuthScheme(value)
+		if err == nil {
+			t.Errorf("%q: expected error", value)
+		}
+	}
+}
+
+func TestParseAuth(t *testing.T) {
+	is := is.New(t)
+
+	def, err := NewParser("./testdata/services/auth").Parse()
+	is.NoErr(err)
+	accounts := def.Services[0]
+	is.Equal(accounts.Name, "AccountService")
+	is.Equal(accounts.AuthScheme, "apikey header X-API-Key")
+	is.Equal(accounts.Comment, "AccountService manages accounts.")
+	is.Equal(accounts.Methods[0].Name, "Delete")
+	is.Equal(accounts.Methods[0].AuthOverride, "oauth2 scopes:accounts.read,accounts.write")
+	is.Equal(accounts.Methods[0].Comment, "Delete deletes an account.")
+	is.Equal(accounts.Methods[1].Name, "Get")
+	is.Equal(accounts.Methods[1].AuthOverride, "")
+	is.Equal(accounts.Methods[2].Name, "Health")
+	is.Equal(accounts.Methods[2].AuthOverride, "none")
+	is.Equal(def.Services[1].AuthScheme, "bearer")
+
+	_, err = NewParser("./testdata/services/errors/auth").Parse()
+	is.True(err != nil)
+	is.True(strings.HasSuffix(err.Error(), "auth.go:5:6: oto:auth: apikey cannot be in body (expected header, query or cookie)"))
+}
+
+func TestGenerateOpenAPIAuth(t *testing.T) {
+	is := is.New(t)
+
+	def, err := NewParser("./testdata/services/auth").Parse()
+	is.NoErr(err)
+	spec, err := GenerateOpenAPI(def, nil)
+	is.NoErr(err)
+	schemes := spec["components"].(map[string]interface{})["securitySchemes"].(map[string]interface{})
+	is.Equal(len(schemes), 3)
+	is.Equal(schemes["bearer"], map[string]interface{}{"type": "http", "scheme": "bearer"})
+	is.Equal(schemes["apikey_header_X-API-Key"], map[string]interface{}{"type": "apiKey", "in": "header", "name": "X-API-Key"})
+	is.Equal(schemes["oauth2"].(map[string]interface{})["type"], "oauth2")
+
+	paths := spec["paths"].(map[string]interface{})
+	security := func(path string) []interface{} {
+		operation := paths[path].(map[string]interface{})["post"].(map[string]interface{})
+		return operation["security"].([]interface{})
+	}
+	is.Equal(security("/AccountService/Get"), []interface{}{
+		map[string]interface{}{"apikey_header_X-API-Key": []interface{}{}},
+	})
+	is.Equal(security("/AccountService/Health"), []interface{}{})
+	is.Equal(security("/AccountService/Delete"), []interface{}{
+		map[string]interface{}{"oauth2": []interface{}{"accounts.read", "accounts.write"}},
+	})
+	is.Equal(security("/ProfileService/Update"), []interface{}{
+		map[string]interface{}{"bearer": []interface{}{}},
+	})
+}
+
+func TestParseScopes(t *testing.T) {
+	is := is.New(t)
+
+	def, err := NewParser("./testdata/services/scopes").Parse()
+	is.NoErr(err)
+	billing := def.Services[0]
+	is.Equal(billing.Name, "BillingService")
+	is.Equal(billing.Scopes, []string{"billing:read"})
+	is.Equal(billing.Comment, "BillingService manages billing.")
+	is.Equal(billing.Methods[0].Name, "Invoices")
+	is.Equal(billing.Methods[0].Scopes, []string{"billing:read"}) // from the service
+	is.Equal(billing.Methods[1].Name, "Refund")
+	is.Equal(billing.Methods[1].Scopes, []string{"admin", "billing:write"}) // replaces the service scopes
+	is.Equal(billing.Methods[1].Comment, "Refund refunds an invoice.")
+	reports := def.Services[1]
+	is.Equal(reports.Scopes, []string(nil))
+	is.Equal(reports.Methods[0].Name, "List")
+	is.Equal(reports.Methods[0].Scopes, []string(nil))
+	is.Equal(reports.Methods[1].Scopes, []string{"reports"})
+
+	spec, err := GenerateOpenAPI(def, nil)
+	is.NoErr(err)
+	paths := spec["paths"].(map[string]interface{})
+	security := func(path string) []interface{} {
+		operation := paths[path].(map[string]interface{})["post"].(map[string]interface{})
+		return operation["security"].([]interface{})
+	}
+	is.Equal(security("/BillingService/Invoices"), []interface{}{
+		map[string]interface{}{"oauth2": []interface{}{"billing:read"}},
+	})
+	is.Equal(security("/BillingService/Refund"), []interface{}{
+		map[string]interface{}{"oauth2": []interface{}{"billing:read", "admin", "billing:write"}},
+	})
+	// scopes are only allowed for oauth2
+	is.Equal(security("/ReportService/Run"), []interface{}{
+		map[string]interface{}{"bearer": []interface{}{}},
+	})
+
+	_, err = NewParser("./testdata/services/errors/scopes").Parse()
+	is.True(err != nil)
+	is.True(strings.HasSuffix(err.Error(), `scopes.go:7:2: oto:scopes: empty scope in "admin,,billing:write"`))
+}
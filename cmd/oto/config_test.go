@@ -0,0 +1,49 @@
+package main
+
+import (
+	"bytes"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/matryer/is"
+	"github.com/pacedotdev/oto"
+)
+
+func TestRunWithConfig(t *testing.T) {
+	is := is.New(t)
+
+	var buf bytes.Buffer
+	err := run(&buf, []string{"oto", "-config=./testdata/oto.yaml"})
+	is.NoErr(err)
+	is.True(strings.Contains(buf.String(), "GreeterService.Greet"))
+	is.True(!strings.Contains(buf.String(), "Ignorer.Ignore"))
+
+	// flags take precedence
+	buf.Reset()
+	err = run(&buf, []string{"oto", "-config=./testdata/oto.yaml", "-output-format=json"})
+	is.NoErr(err)
+	is.True(!strings.Contains(buf.String(), `"name": "Error"`))
+	buf.Reset()
+	err = run(&buf, []string{"oto", "-config=./testdata/oto.yaml", "-output-format=json", "-add-error-field"})
+	is.NoErr(err)
+	is.True(strings.Contains(buf.String(), `"name": "Error"`))
+}
+
+func TestInitConfig(t *testing.T) {
+	is := is.New(t)
+	path := filepath.Join(t.TempDir(), "oto.yaml")
+
+	var buf bytes.Buffer
+	err := run(&buf, []string{"oto", "-init", "-config=" + path})
+	is.NoErr(err)
+	is.Equal(buf.String(), "wrote "+path+"\n")
+	config, err := oto.LoadConfig(path)
+	is.NoErr(err)
+	is.Equal(config.Patterns, []string{"./definitions"})
+	is.Equal(config.Template, "") // commented out
+
+	// won't overwrite
+	err = run(&buf, []string{"oto", "-init", "-config=" + path})
+	is.True(err != nil)
+}
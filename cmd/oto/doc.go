@@ -0,0 +1,12 @@
+// Command oto generates code from Go interfaces that describe services.
+//
+// The interfaces in the packages matching the patterns become Services,
+// and the structs they use become Objects. The resulting Definition is
+// rendered with a plush template, or written in one of the built-in
+// formats (OpenAPI, JSON Schema, GraphQL, TypeScript, Python or
+// Protocol Buffers).
+//
+//	oto -template ./templates/server.go.plush -out ./server.gen.go ./definitions
+//
+// Run oto -help for the list of flags.
+package main
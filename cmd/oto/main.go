@@ -0,0 +1,552 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/dustin/go-humanize"
+	"github.com/pacedotdev/oto"
+	"github.com/pkg/errors"
+	"gopkg.in/yaml.v3"
+)
+
+func main() {
+	if err := run(os.Stdout, os.Args); err != nil {
+		fmt.Fprintf(os.Stderr, "%s\n", err)
+		os.Exit(1)
+	}
+}
+
+func run(stdout io.Writer, args []string) error {
+	flags := flag.NewFlagSet(args[0], flag.ContinueOnError)
+	flags.Usage = func() {
+		fmt.Println(args[0] + ` usage:
+	oto [flags] paths [[path2] [path3]...]`)
+		fmt.Println(`
+flags:`)
+		flags.PrintDefaults()
+	}
+	var (
+		template           = flags.String("template", "", "plush template to render")
+		outfile            = flags.String("out", "", "output file (default: stdout)")
+		pkg                = flags.String("pkg", "", "explicit package name (default: inferred)")
+		v                  = flags.Bool("v", false, "verbose output")
+		paramsStr          = flags.String("params", "", "list of parameters in the format: \"key:value,key:value\"")
+		ignoreList         = flags.String("ignore", "", "comma separated list of interfaces to ignore, which may be glob patterns like \"Internal*\"")
+		includeList        = flags.String("include", "", "comma separated list of the only interfaces to parse (default: all interfaces)")
+		format             = flags.String("output-format", "", "write the definition instead of rendering a template: json or yaml")
+		openapi            = flags.Bool("openapi", false, "write an OpenAPI 3.0 spec instead of rendering a template (see -output-format)")
+		openapiBase        = flags.String("openapi-base", "", "OpenAPI spec file (json or yaml) to merge into the generated spec")
+		jsonSchema         = flags.Bool("jsonschema", false, "write a JSON Schema of the objects instead of rendering a template (see -output-format)")
+		graphQL            = flags.Bool("graphql", false, "write a GraphQL schema instead of rendering a template")
+		typeScript         = flags.Bool("typescript", false, "write TypeScript interfaces instead of rendering a template")
+		python             = flags.Bool("python", false, "write Python dataclasses instead of rendering a template")
+		proto              = flags.Bool("proto", false, "write a proto3 file instead of rendering a template")
+		protoLockFile      = flags.String("proto-lock", "", "file to keep proto field numbers stable in (default: the -out file with .lock appended)")
+		typeMapStr         = flags.String("typemap", "", "comma separated list of types to treat as scalars in the format: \"TypeID=JSType:Format:TypeName\" (Format and TypeName are optional)")
+		sqlNullObjects     = flags.Bool("sql-null-objects", false, "treat database/sql Null* types as objects instead of nullable values")
+		watchMode          = flags.Bool("watch", false, "watch the source files, and re-generate when they change")
+		watchDelay         = flags.Duration("watch-delay", 200*time.Millisecond, "how long to wait after a change before re-generating (see -watch)")
+		configFile         = flags.String("config", "", "config file (default: oto.yaml, oto.yml or oto.json if present)")
+		initConfig         = flags.Bool("init", false, "write a starter oto.yaml config file (or the -config file)")
+		excludePkgs        = flags.String("exclude-packages", "", "comma separated list of package import paths to ignore")
+		matchIfaces        = flags.String("match-interfaces", "", "regular expression that the names of interfaces must match to become services, like \"Service$\" (default: all interfaces)")
+		buildTags          = flags.String("build-tags", "", "comma separated list of build tags to use when loading packages")
+		addErrorField      = flags.Bool("add-error-field", true, "add the Error field to output objects")
+		strict             = flags.Bool("strict", false, "make the strict checks errors (see -strict-checks)")
+		strictChecks       = flags.String("strict-checks", "", "comma separated list of checks for -strict: unknown-jstypes, method-comments, field-examples, missing-objects, unused-objects (default: all)")
+		failOnUnused       = flags.Bool("fail-on-unused", false, "make objects that are not used by any service an error (see oto:used)")
+		int64AsString      = flags.Bool("int64-as-string", false, "represent int64 and uint64 values as strings (JavaScript numbers cannot hold them)")
+		synthesize         = flags.Bool("synthesize-requests", false, "allow methods with more than one parameter, and make a request object for them")
+		sortFields         = flags.Bool("sort-fields", false, "sort the fields of objects by name, instead of keeping the order they are declared in")
+		sortObjects        = flags.Bool("sort-objects", false, "sort the objects by name, instead of keeping the order they are found in")
+		preserveOrder      = flags.Bool("preserve-order", false, "keep services, methods and objects in the order they are declared in, instead of sorting them")
+		skipUnexported     = flags.Bool("skip-unexported-fields", false, "leave unexported struct fields out of objects (like encoding/json does), instead of failing")
+		maxDepth           = flags.Int("max-recursion-depth", 20, "how deeply objects may be nested inside each other (0 means no limit)")
+		noValidateExamples = flags.Bool("no-validate-examples", false, "allow examples that do not match the types of their fields")
+		versionOverride    = flags.String("version-override", "", "version of the definition, instead of the oto:version line or Version constant in the package")
+		lint               = flags.Bool("lint", false, "check the style of the definition (see -lint-checks), and fail if there are any errors (the output is optional)")
+		lintChecks         = flags.String("lint-checks", "", "comma separated list of checks for -lint: method-comments, field-comments, examples, naming (default: all)")
+		lintMaxMethods     = flags.Int("lint-max-methods", 0, "warn about services with more methods than this in -lint (0 means no limit)")
+		lintMaxFields      = flags.Int("lint-max-fields", 0, "warn about objects with more fields than this in -lint (0 means no limit)")
+		recoverOnError     = flags.Bool("recover", false, "keep going after errors in services, objects and fields, leaving them out of the output (the errors are still reported)")
+	)
+	var mergePatterns stringsFlag
+	flags.Var(&mergePatterns, "merge", "additional package pattern to parse and merge into the definition (may be repeated)")
+	var typeOverrides stringsFlag
+	flags.Var(&typeOverrides, "type-override", "type to treat as a scalar in the format: \"TypeID=JSType:Format:TypeName\" (may be repeated)")
+	if err := flags.Parse(args[1:]); err != nil {
+		return err
+	}
+	if *initConfig {
+		path := *configFile
+		if path == "" {
+			path = oto.DefaultConfigFiles[0]
+		}
+		if err := oto.WriteStarterConfig(path); err != nil {
+			return err
+		}
+		fmt.Fprintf(stdout, "wrote %s\n", path)
+		return nil
+	}
+	config, err := oto.LoadConfig(*configFile)
+	if err != nil {
+		return err
+	}
+	// flags take precedence over the config
+	setFlags := make(map[string]bool)
+	flags.Visit(func(f *flag.Flag) {
+		setFlags[f.Name] = true
+	})
+	if !setFlags["template"] {
+		*template = config.Template
+	}
+	if !setFlags["out"] {
+		*outfile = config.Out
+	}
+	if !setFlags["v"] {
+		*v = config.Verbose
+	}
+	if !setFlags["ignore"] {
+		*ignoreList = strings.Join(config.ExcludeInterfaces, ",")
+	}
+	if !setFlags["exclude-packages"] {
+		*excludePkgs = strings.Join(config.ExcludePackages, ",")
+	}
+	if !setFlags["include"] {
+		*includeList = strings.Join(config.IncludeInterfaces, ",")
+	}
+	if !setFlags["match-interfaces"] {
+		*matchIfaces = config.MatchInterfaces
+	}
+	if !setFlags["build-tags"] {
+		*buildTags = strings.Join(config.BuildTags, ",")
+	}
+	if !setFlags["add-error-field"] && config.AddErrorField != nil {
+		*addErrorField = *config.AddErrorField
+	}
+	if !setFlags["strict"] {
+		*strict = config.Strict
+	}
+	if !setFlags["strict-checks"] {
+		*strictChecks = strings.Join(config.StrictChecks, ",")
+	}
+	if !setFlags["sort-fields"] {
+		*sortFields = config.SortFields
+	}
+	if !setFlags["sort-objects"] {
+		*sortObjects = config.SortObjects
+	}
+	if !setFlags["preserve-order"] {
+		*preserveOrder = config.PreserveOrder
+	}
+	if !setFlags["skip-unexported-fields"] {
+		*skipUnexported = config.SkipUnexportedFields
+	}
+	patterns := flags.Args()
+	if len(patterns) == 0 {
+		patterns = config.Patterns
+	}
+	hasOutput := *template != "" || *format != "" || *openapi || *jsonSchema || *graphQL || *typeScript || *python || *proto
+	if !hasOutput && !*lint {
+		flags.PrintDefaults()
+		return errors.New("missing template")
+	}
+	params, err := parseParams(*paramsStr)
+	if err != nil {
+		flags.PrintDefaults()
+		return errors.Wrap(err, "params")
+	}
+	for key, value := range config.Params {
+		if _, ok := params[key]; !ok {
+			params[key] = value
+		}
+	}
+	typeMap, err := parseTypeMap(*typeMapStr)
+	if err != nil {
+		flags.PrintDefaults()
+		return errors.Wrap(err, "typemap")
+	}
+	for _, typeOverride := range typeOverrides {
+		overrides, err := parseTypeMap(typeOverride)
+		if err != nil {
+			flags.PrintDefaults()
+			return errors.Wrap(err, "type-override")
+		}
+		for typeID, override := range overrides {
+			typeMap[typeID] = override
+		}
+	}
+	checks, err := parseStrictChecks(*strictChecks)
+	if err != nil {
+		flags.PrintDefaults()
+		return errors.Wrap(err, "strict-checks")
+	}
+	lintOptions, err := parseLintChecks(*lintChecks)
+	if err != nil {
+		flags.PrintDefaults()
+		return errors.Wrap(err, "lint-checks")
+	}
+	lintOptions.MaxMethodsPerService = *lintMaxMethods
+	lintOptions.MaxFieldsPerObject = *lintMaxFields
+	var matchInterfaces *regexp.Regexp
+	if *matchIfaces != "" {
+		if matchInterfaces, err = regexp.Compile(*matchIfaces); err != nil {
+			flags.PrintDefaults()
+			return errors.Wrap(err, "match-interfaces")
+		}
+	}
+	// newConfiguredParser makes a parser with the settings from
+	// the flags and config.
+	newConfiguredParser := func(patterns ...string) *oto.Parser {
+		parser := oto.NewParser(patterns...)
+		ignoreItems := strings.Split(*ignoreList, ",")
+		if ignoreItems[0] != "" {
+			parser.ExcludeInterfaces = ignoreItems
+		}
+		if *includeList != "" {
+			parser.IncludeInterfaces = strings.Split(*includeList, ",")
+		}
+		parser.MatchInterfaces = matchInterfaces
+		if *excludePkgs != "" {
+			parser.ExcludePackages = strings.Split(*excludePkgs, ",")
+		}
+		if *buildTags != "" {
+			parser.BuildTags = strings.Split(*buildTags, ",")
+		}
+		parser.AddErrorField = *addErrorField
+		parser.Strict = *strict
+		parser.StrictChecks = checks
+		parser.FailOnUnused = *failOnUnused
+		if *sqlNullObjects {
+			for typeID := range oto.SQLNullTypeOverrides() {
+				delete(parser.TypeOverrides, typeID)
+			}
+		}
+		for typeID, scalarType := range typeMap {
+			parser.TypeOverrides[typeID] = scalarType
+		}
+		parser.Int64AsString = *int64AsString
+		parser.SynthesizeRequests = *synthesize
+		parser.MaxRecursionDepth = *maxDepth
+		parser.SkipUnexportedFields = *skipUnexported
+		parser.VersionOverride = *versionOverride
+		parser.ValidateExamples = !*noValidateExamples
+		parser.SortFields = *sortFields
+		parser.SortObjects = *sortObjects
+		parser.PreserveOrder = *preserveOrder
+		parser.RecoverOnError = *recoverOnError
+		parser.Verbose = *v
+		return parser
+	}
+	generate := func() ([]string, error) {
+		parser := newConfiguredParser(patterns...)
+		if parser.Verbose {
+			fmt.Println("oto - github.com/pacedotdev/oto")
+		}
+		// parseErrs are the errors recovered from (see -recover),
+		// which fail the run after the output is written
+		var parseErrs oto.ParseErrorList
+		def, err := parser.Parse()
+		if errs, ok := err.(oto.ParseErrorList); ok {
+			parseErrs = append(parseErrs, errs...)
+		} else if err != nil {
+			return nil, err
+		}
+		dirs := parser.Dirs()
+		parsers := []*oto.Parser{parser}
+		for _, mergePattern := range mergePatterns {
+			mergeParser := newConfiguredParser(mergePattern)
+			parsers = append(parsers, mergeParser)
+			other, err := mergeParser.Parse()
+			if errs, ok := err.(oto.ParseErrorList); ok {
+				parseErrs = append(parseErrs, errs...)
+			} else if err != nil {
+				return nil, errors.Wrapf(err, "merge %s", mergePattern)
+			}
+			if err := def.Merge(other); err != nil {
+				return nil, err
+			}
+			dirs = append(dirs, mergeParser.Dirs()...)
+		}
+		for _, warning := range def.Warnings {
+			fmt.Fprintf(os.Stderr, "warning: %s\n", warning)
+		}
+		if *lint {
+			errorCount := oto.WriteLintIssues(os.Stderr, oto.Lint(def, lintOptions), parsers)
+			if errorCount > 0 {
+				return dirs, errors.Errorf("lint: %d error(s)", errorCount)
+			}
+		}
+		if !hasOutput {
+			// just linting
+			if len(parseErrs) > 0 {
+				return dirs, parseErrs
+			}
+			return dirs, nil
+		}
+		if *pkg != "" {
+			def.PackageName = *pkg
+		}
+		var out string
+		switch {
+		case *openapi:
+			var base map[string]interface{}
+			if *openapiBase != "" {
+				b, err := ioutil.ReadFile(*openapiBase)
+				if err != nil {
+					return nil, err
+				}
+				if err := yaml.Unmarshal(b, &base); err != nil {
+					return nil, errors.Wrap(err, "openapi-base")
+				}
+			}
+			spec, err := oto.GenerateOpenAPI(def, base)
+			if err != nil {
+				return nil, err
+			}
+			out, err = encodeOutput(spec, *format)
+			if err != nil {
+				return nil, err
+			}
+		case *jsonSchema:
+			schema, err := oto.GenerateJSONSchema(def)
+			if err != nil {
+				return nil, err
+			}
+			out, err = encodeOutput(schema, *format)
+			if err != nil {
+				return nil, err
+			}
+		case *graphQL:
+			out, err = oto.GenerateGraphQLSchema(def)
+			if err != nil {
+				return nil, err
+			}
+		case *typeScript:
+			out, err = oto.GenerateTypeScript(def)
+			if err != nil {
+				return nil, err
+			}
+		case *python:
+			out, err = oto.GeneratePython(def)
+			if err != nil {
+				return nil, err
+			}
+		case *proto:
+			lockFile := *protoLockFile
+			if lockFile == "" && *outfile != "" {
+				lockFile = *outfile + ".lock"
+			}
+			var options oto.ProtoOptions
+			if lockFile != "" {
+				options.Lock, err = oto.ReadProtoLock(lockFile)
+				if err != nil {
+					return nil, err
+				}
+			}
+			out, err = oto.GenerateProto(def, options)
+			if err != nil {
+				return nil, err
+			}
+			if lockFile != "" {
+				if err := oto.WriteProtoLock(lockFile, options.Lock); err != nil {
+					return nil, err
+				}
+			}
+		case *format != "":
+			out, err = encodeOutput(def, *format)
+			if err != nil {
+				return nil, err
+			}
+		default:
+			b, err := ioutil.ReadFile(*template)
+			if err != nil {
+				return nil, err
+			}
+			out, err = oto.Render(string(b), def, params)
+			if err != nil {
+				return nil, err
+			}
+		}
+		var w io.Writer = stdout
+		if *outfile != "" {
+			f, err := os.Create(*outfile)
+			if err != nil {
+				return nil, err
+			}
+			defer f.Close()
+			w = f
+		}
+		if _, err := io.WriteString(w, out); err != nil {
+			return nil, err
+		}
+		if parser.Verbose {
+			var methodsCount int
+			for i := range def.Services {
+				methodsCount += len(def.Services[i].Methods)
+			}
+			fmt.Println()
+			fmt.Printf("\tTotal services: %d", len(def.Services))
+			fmt.Printf("\tTotal Methods: %d", methodsCount)
+			fmt.Printf("\tTotal Objects: %d\n", len(def.Objects))
+			fmt.Printf("\tOutput size: %s\n", humanize.Bytes(uint64(len(out))))
+		}
+		if len(parseErrs) > 0 {
+			return dirs, parseErrs
+		}
+		return dirs, nil
+	}
+	dirs, err := generate()
+	if err != nil {
+		return err
+	}
+	if !*watchMode {
+		return nil
+	}
+	return watch(context.Background(), os.Stderr, dirs, []string{*outfile, *protoLockFile}, *watchDelay, generate)
+}
+
+// encodeOutput encodes v in the specified format (json or yaml).
+// An empty format is json.
+func encodeOutput(v interface{}, format string) (string, error) {
+	var b []byte
+	var err error
+	switch format {
+	case "json", "":
+		b, err = json.MarshalIndent(v, "", "\t")
+		b = append(b, '\n')
+	case "yaml":
+		b, err = yaml.Marshal(v)
+	default:
+		return "", errors.Errorf("unknown output format %q (expected json or yaml)", format)
+	}
+	if err != nil {
+		return "", err
+	}
+	return string(b), nil
+}
+
+// parseParams returns a map of data parsed from the params string.
+func parseParams(s string) (map[string]interface{}, error) {
+	params := make(map[string]interface{})
+	if s == "" {
+		// empty map for an empty string
+		return params, nil
+	}
+	pairs := strings.Split(s, ",")
+	for i := range pairs {
+		pair := strings.TrimSpace(pairs[i])
+		segs := strings.Split(pair, ":")
+		if len(segs) != 2 {
+			return nil, errors.New("malformed params")
+		}
+		params[strings.TrimSpace(segs[0])] = strings.TrimSpace(segs[1])
+	}
+	return params, nil
+}
+
+// parseStrictChecks returns the StrictChecks from the comma
+// separated list of check names. An empty string is all checks.
+func parseStrictChecks(s string) (oto.StrictChecks, error) {
+	if s == "" {
+		return oto.AllStrictChecks(), nil
+	}
+	var checks oto.StrictChecks
+	for _, name := range strings.Split(s, ",") {
+		switch strings.TrimSpace(name) {
+		case "unknown-jstypes":
+			checks.UnknownJSTypes = true
+		case "method-comments":
+			checks.MethodComments = true
+		case "field-examples":
+			checks.FieldExamples = true
+		case "missing-objects":
+			checks.MissingObjects = true
+		case "unused-objects":
+			checks.UnusedObjects = true
+		default:
+			return checks, errors.Errorf("unknown check %q", name)
+		}
+	}
+	return checks, nil
+}
+
+// parseLintChecks returns the LintOptions with the checks in the
+// comma separated list of check names turned on. An empty string is
+// all checks.
+func parseLintChecks(s string) (oto.LintOptions, error) {
+	if s == "" {
+		s = "method-comments,field-comments,examples,naming"
+	}
+	var options oto.LintOptions
+	for _, name := range strings.Split(s, ",") {
+		switch strings.TrimSpace(name) {
+		case "method-comments":
+			options.RequireMethodComments = true
+		case "field-comments":
+			options.RequireFieldComments = true
+		case "examples":
+			options.RequireExamples = true
+		case "naming":
+			options.EnforceNamingConvention = true
+		default:
+			return options, errors.Errorf("unknown check %q", name)
+		}
+	}
+	return options, nil
+}
+
+// parseTypeMap returns a map of TypeOverride items parsed from
+// the typemap string.
+// Each item is in the format: "TypeID=JSType:Format:TypeName",
+// where Format and TypeName are optional.
+func parseTypeMap(s string) (map[string]oto.TypeOverride, error) {
+	typeMap := make(map[string]oto.TypeOverride)
+	if s == "" {
+		return typeMap, nil
+	}
+	items := strings.Split(s, ",")
+	for i := range items {
+		item := strings.TrimSpace(items[i])
+		segs := strings.Split(item, "=")
+		if len(segs) != 2 || segs[0] == "" || segs[1] == "" {
+			return nil, errors.Errorf("malformed typemap item: %q", item)
+		}
+		values := strings.Split(segs[1], ":")
+		if len(values) > 3 {
+			return nil, errors.Errorf("malformed typemap item: %q", item)
+		}
+		var scalarType oto.TypeOverride
+		scalarType.JSType = values[0]
+		if len(values) > 1 {
+			scalarType.Format = values[1]
+		}
+		if len(values) > 2 {
+			scalarType.TypeName = values[2]
+		}
+		typeMap[segs[0]] = scalarType
+	}
+	return typeMap, nil
+}
+
+// stringsFlag is a flag.Value that collects the values
+// of a flag that may be repeated.
+type stringsFlag []string
+
+func (s *stringsFlag) String() string {
+	return strings.Join(*s, ",")
+}
+
+func (s *stringsFlag) Set(value string) error {
+	*s = append(*s, value)
+	return nil
+}
@@ -0,0 +1,242 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/matryer/is"
+	"github.com/pacedotdev/oto"
+)
+
+func Test(t *testing.T) {
+	is := is.New(t)
+	var buf bytes.Buffer
+	args := []string{
+		"oto",
+		"-template=./testdata/template.plush",
+		"-pkg=stuff",
+		"../../testdata/services/pleasantries",
+	}
+	err := run(&buf, args)
+	is.NoErr(err)
+	s := buf.String()
+	for _, should := range []string{
+		"GreeterService.GetGreetings",
+		"GreeterService.Greet",
+		"Welcomer.Welcome",
+	} {
+		if !strings.Contains(s, should) {
+			t.Errorf("missing: %s", should)
+			is.Fail()
+		}
+	}
+}
+
+func TestParseParams(t *testing.T) {
+	is := is.New(t)
+
+	params, err := parseParams("key1:value1,key2: value2 , key3:value3")
+	is.NoErr(err)
+	is.Equal(params["key1"], "value1")
+	is.Equal(params["key2"], "value2")
+	is.Equal(params["key3"], "value3")
+
+}
+
+func TestOutputFormat(t *testing.T) {
+	is := is.New(t)
+	for format, should := range map[string]string{
+		"json": `"packageName": "pleasantries"`,
+		"yaml": "packageName: pleasantries",
+	} {
+		var buf bytes.Buffer
+		args := []string{
+			"oto",
+			"-output-format=" + format,
+			"../../testdata/services/pleasantries",
+		}
+		err := run(&buf, args)
+		is.NoErr(err)
+		is.True(strings.Contains(buf.String(), should))
+	}
+	var buf bytes.Buffer
+	err := run(&buf, []string{"oto", "-output-format=xml", "../../testdata/services/pleasantries"})
+	is.True(err != nil)
+}
+
+func TestParseTypeMap(t *testing.T) {
+	is := is.New(t)
+
+	typeMap, err := parseTypeMap("example.com/money.Money=string:decimal, example.com/ulid.ULID=string::string,example.com/n.N=number")
+	is.NoErr(err)
+	is.Equal(len(typeMap), 3)
+	is.Equal(typeMap["example.com/money.Money"], oto.TypeOverride{JSType: "string", Format: "decimal"})
+	is.Equal(typeMap["example.com/ulid.ULID"], oto.TypeOverride{JSType: "string", TypeName: "string"})
+	is.Equal(typeMap["example.com/n.N"], oto.TypeOverride{JSType: "number"})
+
+	_, err = parseTypeMap("example.com/money.Money")
+	is.True(err != nil)
+	_, err = parseTypeMap("example.com/money.Money=a:b:c:d")
+	is.True(err != nil)
+}
+
+func TestTypeMapFlag(t *testing.T) {
+	is := is.New(t)
+	var buf bytes.Buffer
+	args := []string{
+		"oto",
+		"-output-format=json",
+		"-typemap=github.com/pacedotdev/oto/testdata/services/scalars.Money=string:decimal:string",
+		"../../testdata/services/scalars",
+	}
+	err := run(&buf, args)
+	is.NoErr(err)
+	is.True(strings.Contains(buf.String(), `"format": "decimal"`))
+	is.True(!strings.Contains(buf.String(), `"name": "Money"`))
+}
+
+func TestTypeOverrideFlag(t *testing.T) {
+	is := is.New(t)
+	var buf bytes.Buffer
+	args := []string{
+		"oto",
+		"-output-format=json",
+		"-type-override=github.com/pacedotdev/oto/testdata/services/scalars.Money=string:decimal:string",
+		"-type-override=time.Duration=number",
+		"../../testdata/services/scalars",
+	}
+	err := run(&buf, args)
+	is.NoErr(err)
+	is.True(strings.Contains(buf.String(), `"format": "decimal"`))
+	is.True(!strings.Contains(buf.String(), `"format": "duration"`))
+
+	err = run(&buf, []string{"oto", "-type-override=time.Duration", "../../testdata/services/scalars"})
+	is.True(err != nil)
+}
+
+func TestParseStrictChecks(t *testing.T) {
+	is := is.New(t)
+
+	checks, err := parseStrictChecks("")
+	is.NoErr(err)
+	is.Equal(checks, oto.AllStrictChecks())
+
+	checks, err = parseStrictChecks("method-comments, missing-objects")
+	is.NoErr(err)
+	is.Equal(checks, oto.StrictChecks{MethodComments: true, MissingObjects: true})
+
+	_, err = parseStrictChecks("method-comments,nope")
+	is.True(err != nil)
+}
+
+func TestMergeFlag(t *testing.T) {
+	is := is.New(t)
+	var buf bytes.Buffer
+	args := []string{
+		"oto",
+		"-template=./testdata/template.plush",
+		"-merge=../../testdata/services/pointers",
+		"-merge=../../testdata/services/unused",
+		"../../testdata/services/pleasantries",
+	}
+	err := run(&buf, args)
+	is.NoErr(err)
+	s := buf.String()
+	for _, should := range []string{
+		"GreeterService.Greet",
+		"TeamService.",
+		"ItemService.",
+	} {
+		if !strings.Contains(s, should) {
+			t.Errorf("missing: %s", should)
+			is.Fail()
+		}
+	}
+
+	err = run(&buf, []string{
+		"oto",
+		"-template=./testdata/template.plush",
+		"-merge=../../testdata/services/pleasantries",
+		"../../testdata/services/pleasantries",
+	})
+	is.True(err != nil)
+}
+
+func TestEnumsOutputFormat(t *testing.T) {
+	is := is.New(t)
+
+	var buf bytes.Buffer
+	err := run(&buf, []string{"oto", "-output-format", "json", "../../testdata/services/enums"})
+	is.NoErr(err)
+	var def oto.Definition
+	is.NoErr(json.Unmarshal(buf.Bytes(), &def))
+	obj, err := def.Object("UpdateRequest")
+	is.NoErr(err)
+	ftype := obj.Fields[0].Type
+	is.True(ftype.IsEnum)
+	enum, err := def.Enum(ftype.EnumName)
+	is.NoErr(err)
+	is.Equal(enum.Name, "Status")
+	is.Equal(len(enum.Values), 3)
+	is.Equal(enum.Values[0].Value, "active")
+}
+
+func TestRunLint(t *testing.T) {
+	is := is.New(t)
+	var buf bytes.Buffer
+
+	// without a template, it just lints
+	err := run(&buf, []string{"oto", "-lint", "-lint-checks", "method-comments", "../../testdata/services/pleasantries"})
+	is.True(err != nil)
+	is.Equal(err.Error(), "lint: 1 error(s)") // Ignorer.Ignore has no comment
+	is.Equal(buf.String(), "")
+
+	err = run(&buf, []string{"oto", "-lint", "-lint-checks", "method-comments", "-ignore", "Ignorer", "../../testdata/services/pleasantries"})
+	is.NoErr(err)
+
+	err = run(&buf, []string{"oto", "-lint", "-lint-checks", "nope", "../../testdata/services/pleasantries"})
+	is.Equal(err.Error(), `lint-checks: unknown check "nope"`)
+}
+
+func TestParseLintChecks(t *testing.T) {
+	is := is.New(t)
+
+	options, err := parseLintChecks("")
+	is.NoErr(err)
+	is.Equal(options, oto.LintOptions{
+		RequireMethodComments:   true,
+		RequireFieldComments:    true,
+		RequireExamples:         true,
+		EnforceNamingConvention: true,
+	})
+	options, err = parseLintChecks("examples, naming")
+	is.NoErr(err)
+	is.Equal(options, oto.LintOptions{RequireExamples: true, EnforceNamingConvention: true})
+}
+
+func TestOpenAPIFlag(t *testing.T) {
+	is := is.New(t)
+	var buf bytes.Buffer
+	args := []string{
+		"oto",
+		"-openapi",
+		"-openapi-base=./testdata/openapi-base.yaml",
+		"../../testdata/services/pleasantries",
+	}
+	err := run(&buf, args)
+	is.NoErr(err)
+	var spec struct {
+		Info struct {
+			Title string `json:"title"`
+		} `json:"info"`
+		Servers []struct {
+			URL string `json:"url"`
+		} `json:"servers"`
+	}
+	is.NoErr(json.Unmarshal(buf.Bytes(), &spec))
+	is.Equal(spec.Info.Title, "Pleasantries API")
+	is.Equal(len(spec.Servers), 1)
+	is.Equal(spec.Servers[0].URL, "https://api.example.com/oto")
+}
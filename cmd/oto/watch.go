@@ -0,0 +1,79 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"path/filepath"
+	"time"
+
+	"github.com/fsnotify/fsnotify"
+	"github.com/pkg/errors"
+)
+
+// watch watches the .go files in dirs, and calls generate once
+// they have stopped changing for delay.
+// generate returns the directories to watch from then on.
+// Errors from generate are written to stderr, and watching continues.
+// Changes to the ignore files (like the output file) do not
+// trigger generate.
+// watch runs until ctx is cancelled.
+func watch(ctx context.Context, stderr io.Writer, dirs []string, ignore []string, delay time.Duration, generate func() ([]string, error)) error {
+	watcher, err := fsnotify.NewWatcher()
+	if err != nil {
+		return errors.Wrap(err, "watch")
+	}
+	defer watcher.Close()
+	for _, dir := range dirs {
+		if err := watcher.Add(dir); err != nil {
+			return errors.Wrapf(err, "watch %s", dir)
+		}
+	}
+	ignored := make(map[string]struct{}, len(ignore))
+	for _, path := range ignore {
+		if path == "" {
+			continue
+		}
+		if abs, err := filepath.Abs(path); err == nil {
+			ignored[abs] = struct{}{}
+		}
+	}
+	var rebuild <-chan time.Time
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case event, ok := <-watcher.Events:
+			if !ok {
+				return nil
+			}
+			if filepath.Ext(event.Name) != ".go" {
+				continue
+			}
+			if abs, err := filepath.Abs(event.Name); err == nil {
+				if _, ok := ignored[abs]; ok {
+					continue
+				}
+			}
+			rebuild = time.After(delay)
+		case err, ok := <-watcher.Errors:
+			if !ok {
+				return nil
+			}
+			fmt.Fprintf(stderr, "%s error: %s\n", time.Now().Format(time.RFC3339), err)
+		case <-rebuild:
+			rebuild = nil
+			dirs, err := generate()
+			if err != nil {
+				fmt.Fprintf(stderr, "%s error: %s\n", time.Now().Format(time.RFC3339), err)
+				continue
+			}
+			for _, dir := range dirs {
+				if err := watcher.Add(dir); err != nil {
+					fmt.Fprintf(stderr, "%s error: watch %s: %s\n", time.Now().Format(time.RFC3339), dir, err)
+				}
+			}
+			fmt.Fprintf(stderr, "%s rebuilt\n", time.Now().Format(time.RFC3339))
+		}
+	}
+}
@@ -0,0 +1,92 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/matryer/is"
+)
+
+func TestWatch(t *testing.T) {
+	is := is.New(t)
+	dir := t.TempDir()
+	source := filepath.Join(dir, "service.go")
+	out := filepath.Join(dir, "generated.go")
+	is.NoErr(ioutil.WriteFile(source, []byte("package service\n"), 0644))
+
+	var stderr syncBuffer
+	calls := make(chan struct{}, 10)
+	var failNext bool
+	generate := func() ([]string, error) {
+		calls <- struct{}{}
+		if failNext {
+			failNext = false
+			return nil, errors.New("parse failed")
+		}
+		return []string{dir}, nil
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error)
+	go func() {
+		done <- watch(ctx, &stderr, []string{dir}, []string{out}, 10*time.Millisecond, generate)
+	}()
+	time.Sleep(50 * time.Millisecond) // wait for the watcher to start
+
+	// changes to the output file and non .go files are ignored
+	is.NoErr(ioutil.WriteFile(out, []byte("package service\n"), 0644))
+	is.NoErr(ioutil.WriteFile(filepath.Join(dir, "notes.txt"), []byte("notes"), 0644))
+	// many changes are debounced into one rebuild
+	for i := 0; i < 3; i++ {
+		is.NoErr(ioutil.WriteFile(source, []byte("package service\n\n// changed\n"), 0644))
+	}
+	waitForCall(t, calls)
+	time.Sleep(50 * time.Millisecond)
+	is.Equal(len(calls), 0)
+	is.True(strings.Contains(stderr.String(), " rebuilt\n"))
+
+	// errors are reported, and watching continues
+	failNext = true
+	is.NoErr(ioutil.WriteFile(source, []byte("package service\n"), 0644))
+	waitForCall(t, calls)
+	time.Sleep(50 * time.Millisecond)
+	is.True(strings.Contains(stderr.String(), " error: parse failed\n"))
+	is.NoErr(ioutil.WriteFile(source, []byte("package service\n\n// changed again\n"), 0644))
+	waitForCall(t, calls)
+
+	cancel()
+	is.NoErr(<-done)
+}
+
+func waitForCall(t *testing.T, calls <-chan struct{}) {
+	t.Helper()
+	select {
+	case <-calls:
+	case <-time.After(5 * time.Second):
+		t.Fatal("timed out waiting for generate")
+	}
+}
+
+// syncBuffer is a bytes.Buffer that is safe for concurrent use.
+type syncBuffer struct {
+	lock sync.Mutex
+	buf  bytes.Buffer
+}
+
+func (b *syncBuffer) Write(p []byte) (int, error) {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+	return b.buf.Write(p)
+}
+
+func (b *syncBuffer) String() string {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+	return b.buf.String()
+}
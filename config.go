@@ -0,0 +1,175 @@
+package oto
+
+import (
+	"bytes"
+	"io/ioutil"
+	"os"
+
+	"github.com/pkg/errors"
+	"gopkg.in/yaml.v3"
+)
+
+// DefaultConfigFiles are the config files that LoadConfig tries in
+// the current directory if it is not given a path.
+var DefaultConfigFiles = []string{"oto.yaml", "oto.yml", "oto.json"}
+
+// Config holds persistent settings, loaded from an oto.yaml
+// (or oto.json) file. Flags take precedence over these values.
+type Config struct {
+	// Patterns are the packages to parse.
+	Patterns []string `yaml:"patterns" json:"patterns"`
+	// Template is the plush template to render.
+	Template string `yaml:"template" json:"template"`
+	// Out is the output file.
+	Out string `yaml:"out" json:"out"`
+	// Params are additional data for the template.
+	Params map[string]interface{} `yaml:"params" json:"params"`
+	// Verbose turns on verbose output.
+	Verbose bool `yaml:"verbose" json:"verbose"`
+	// ExcludeInterfaces are the names of interfaces to ignore,
+	// which may be glob patterns.
+	ExcludeInterfaces []string `yaml:"exclude-interfaces" json:"exclude-interfaces"`
+	// IncludeInterfaces are the names of the only interfaces
+	// to parse.
+	IncludeInterfaces []string `yaml:"include-interfaces" json:"include-interfaces"`
+	// MatchInterfaces is a regular expression that the names of
+	// interfaces must match to become services.
+	MatchInterfaces string `yaml:"match-interfaces" json:"match-interfaces"`
+	// ExcludePackages are the import paths of packages to ignore.
+	ExcludePackages []string `yaml:"exclude-packages" json:"exclude-packages"`
+	// AddErrorField is whether to add the Error field to output
+	// objects. Nil means true.
+	AddErrorField *bool `yaml:"add-error-field" json:"add-error-field"`
+	// BuildTags are the build tags to use when loading packages.
+	BuildTags []string `yaml:"build-tags" json:"build-tags"`
+	// Strict turns on strict mode.
+	Strict bool `yaml:"strict" json:"strict"`
+	// StrictChecks are the names of the checks to make in
+	// strict mode (default: all of them).
+	StrictChecks []string `yaml:"strict-checks" json:"strict-checks"`
+	// SortFields sorts the fields of objects by name.
+	SortFields bool `yaml:"sort-fields" json:"sort-fields"`
+	// SortObjects sorts the objects by name.
+	SortObjects bool `yaml:"sort-objects" json:"sort-objects"`
+	// PreserveOrder keeps services, methods and objects in the
+	// order they are declared in.
+	PreserveOrder bool `yaml:"preserve-order" json:"preserve-order"`
+	// SkipUnexportedFields leaves unexported fields out of
+	// objects, instead of failing.
+	SkipUnexportedFields bool `yaml:"skip-unexported-fields" json:"skip-unexported-fields"`
+}
+
+// LoadConfig loads the Config from the file at path.
+// If path is empty, the default config files are tried in the
+// current directory, and an empty Config is returned if none
+// of them exist.
+func LoadConfig(path string) (Config, error) {
+	var config Config
+	if path == "" {
+		for _, defaultPath := range DefaultConfigFiles {
+			if _, err := os.Stat(defaultPath); err == nil {
+				path = defaultPath
+				break
+			}
+		}
+		if path == "" {
+			return config, nil
+		}
+	}
+	b, err := ioutil.ReadFile(path)
+	if err != nil {
+		return config, err
+	}
+	// JSON is valid YAML, so this handles oto.json too
+	decoder := yaml.NewDecoder(bytes.NewReader(b))
+	decoder.KnownFields(true)
+	if err := decoder.Decode(&config); err != nil {
+		return config, errors.Wrapf(err, "parse config %s", path)
+	}
+	return config, nil
+}
+
+// starterConfig is the oto.yaml file written by WriteStarterConfig
+// (the -init flag).
+const starterConfig = `# oto config - flags take precedence over these settings.
+# See https://github.com/pacedotdev/oto
+
+# patterns are the packages to parse.
+patterns:
+  - ./definitions
+
+# template is the plush template to render.
+# template: ./templates/server.go.plush
+
+# out is the output file (default: stdout).
+# out: ./server.gen.go
+
+# params are additional data for the template.
+# params:
+#   key: value
+
+# verbose turns on verbose output.
+# verbose: true
+
+# exclude-interfaces are the names of interfaces to ignore, which may be glob
+# patterns.
+# exclude-interfaces:
+#   - Ignorer
+#   - Internal*
+
+# include-interfaces are the names of the only interfaces to parse (default:
+# all interfaces).
+# include-interfaces:
+#   - GreeterService
+
+# match-interfaces is a regular expression that the names of interfaces must
+# match to become services (default: all interfaces).
+# match-interfaces: Service$
+
+# exclude-packages are the import paths of packages to ignore.
+# exclude-packages:
+#   - example.com/project/definitions/internal
+
+# add-error-field adds the Error field to output objects (default: true).
+# add-error-field: false
+
+# build-tags are the build tags to use when loading packages.
+# build-tags:
+#   - integration
+
+# strict makes the strict checks errors.
+# strict: true
+
+# strict-checks are the checks made in strict mode (default: all of them).
+# strict-checks:
+#   - unknown-jstypes
+#   - method-comments
+#   - field-examples
+#   - missing-objects
+#   - unused-objects
+
+# sort-fields sorts the fields of objects by name, instead of keeping the order
+# they are declared in.
+# sort-fields: true
+
+# sort-objects sorts the objects by name, instead of keeping the order they are
+# found in.
+# sort-objects: true
+
+# preserve-order keeps services, methods and objects in the order they are
+# declared in, instead of sorting them.
+# preserve-order: true
+
+# skip-unexported-fields leaves unexported struct fields out of objects (like
+# encoding/json does), instead of failing.
+# skip-unexported-fields: true
+`
+
+// WriteStarterConfig writes an oto.yaml file with the settings
+// commented out to path, unless the file already exists.
+func WriteStarterConfig(path string) error {
+	if _, err := os.Stat(path); err == nil {
+		return errors.Errorf("%s already exists", path)
+	}
+	return ioutil.WriteFile(path, []byte(starterConfig), 0644)
+}
@@ -0,0 +1,37 @@
+package oto
+
+import (
+	"testing"
+
+	"github.com/matryer/is"
+)
+
+func TestLoadConfig(t *testing.T) {
+	is := is.New(t)
+
+	config, err := LoadConfig("./testdata/oto.yaml")
+	is.NoErr(err)
+	is.Equal(config.Patterns, []string{"./testdata/services/pleasantries"})
+	is.Equal(config.Template, "./testdata/template.plush")
+	is.Equal(config.Params["greeting"], "hello")
+	is.Equal(config.ExcludeInterfaces, []string{"Ignorer"})
+	is.True(config.AddErrorField != nil)
+	is.Equal(*config.AddErrorField, false)
+	is.Equal(config.BuildTags, []string{"integration"})
+
+	config, err = LoadConfig("./testdata/oto.json")
+	is.NoErr(err)
+	is.Equal(config.Patterns, []string{"./testdata/services/pleasantries"})
+	is.Equal(config.Out, "./out.txt")
+	is.Equal(config.Verbose, true)
+	is.Equal(config.ExcludePackages, []string{"example.com/skip"})
+	is.Equal(config.AddErrorField, nil)
+
+	// no oto.yaml in the current directory
+	config, err = LoadConfig("")
+	is.NoErr(err)
+	is.Equal(len(config.Patterns), 0)
+
+	_, err = LoadConfig("./testdata/missing.yaml")
+	is.True(err != nil)
+}
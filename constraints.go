@@ -0,0 +1,205 @@
+package oto
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// FieldConstraints describe the values a Field may have, from
+// the validate tag (see github.com/go-playground/validator), like
+// validate:"required,min=1,max=100", and the min:, max: and pattern:
+// lines of its comment.
+type FieldConstraints struct {
+	// Min and Max are the smallest and largest allowed numbers.
+	Min *float64 `json:"min,omitempty"`
+	Max *float64 `json:"max,omitempty"`
+	// MinLength and MaxLength are the shortest and longest allowed
+	// strings, or the fewest and most items in a slice.
+	MinLength *int `json:"minLength,omitempty"`
+	MaxLength *int `json:"maxLength,omitempty"`
+	// Pattern is a regular expression that strings must match.
+	Pattern string `json:"pattern,omitempty"`
+	// Enum are the allowed values, from the oneof validator.
+	Enum []interface{} `json:"enum,omitempty"`
+	// Email and URL are true if the value must be an email
+	// address or a URL.
+	Email bool `json:"email,omitempty"`
+	URL   bool `json:"url,omitempty"`
+	// Other are the validators (with their values) that oto does
+	// not understand, like "uuid4" or "startswith=ab", so
+	// templates can still use them.
+	Other []string `json:"other,omitempty"`
+}
+
+// hasRequiredValidator gets whether the validate or binding (used
+// by gin) tag has the required validator.
+func hasRequiredValidator(tags map[string]FieldTag) bool {
+	for _, key := range []string{"validate", "binding"} {
+		tag, ok := tags[key]
+		if !ok {
+			continue
+		}
+		for _, validator := range append([]string{tag.Value}, tag.Options...) {
+			if validator == "dive" {
+				// the rest are for the elements
+				break
+			}
+			if validator == "required" {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// parseValidateTag parses the constraints in the validate tag of a
+// field of the specified type. Validators that do not describe
+// the value (like required or dive) are ignored, and so are the
+// validators after dive, which are for the elements of a slice.
+// Unknown validators are kept in Other.
+// It returns nil if there are no constraints.
+func parseValidateTag(tag FieldTag, ftype FieldType) (*FieldConstraints, error) {
+	var constraints FieldConstraints
+	var found bool
+	// min, max and len are the length of strings and slices, but
+	// the value of numbers
+	isLength := ftype.JSType == "string" || ftype.Multiple || ftype.IsMap
+	for _, validator := range append([]string{tag.Value}, tag.Options...) {
+		name, value, _ := strings.Cut(validator, "=")
+		switch name {
+		case "dive":
+			if !found {
+				return nil, nil
+			}
+			return &constraints, nil
+		case "min", "max", "len", "gte", "lte":
+			n, err := strconv.ParseFloat(value, 64)
+			if err != nil {
+				return nil, errors.Errorf("validate: %s: invalid number %q", name, value)
+			}
+			if isLength {
+				length := int(n)
+				if float64(length) != n || length < 0 {
+					return nil, errors.Errorf("validate: %s: invalid length %q", name, value)
+				}
+				if name != "max" && name != "lte" {
+					constraints.MinLength = &length
+				}
+				if name != "min" && name != "gte" {
+					constraints.MaxLength = &length
+				}
+			} else {
+				if name != "max" && name != "lte" {
+					constraints.Min = &n
+				}
+				if name != "min" && name != "gte" {
+					max := n
+					constraints.Max = &max
+				}
+			}
+		case "oneof":
+			for _, option := range strings.Fields(value) {
+				if ftype.JSType != "number" {
+					constraints.Enum = append(constraints.Enum, option)
+					continue
+				}
+				n, err := strconv.ParseFloat(option, 64)
+				if err != nil {
+					return nil, errors.Errorf("validate: oneof: invalid number %q", option)
+				}
+				constraints.Enum = append(constraints.Enum, n)
+			}
+		case "email":
+			constraints.Email = true
+		case "url":
+			constraints.URL = true
+		case "required", "omitempty", "":
+			// see hasRequiredValidator and Field.OmitEmpty
+			continue
+		default:
+			constraints.Other = append(constraints.Other, validator)
+		}
+		found = true
+	}
+	if !found {
+		return nil, nil
+	}
+	return &constraints, nil
+}
+
+// parseConstraintLines parses the min:, max: and pattern: lines
+// of the comment of a field of the specified type into the
+// constraints (which may be nil), and returns the remaining comment.
+// Like in validate tags, min and max are the length of strings and
+// slices, but the value of numbers. The lines take precedence over
+// the validate tag.
+func parseConstraintLines(comment string, ftype FieldType, constraints *FieldConstraints) (*FieldConstraints, string, error) {
+	isLength := ftype.JSType == "string" || ftype.Multiple || ftype.IsMap
+	for _, name := range []string{"min", "max"} {
+		value, found, rest := extractDirective(comment, name+":")
+		if !found {
+			continue
+		}
+		comment = rest
+		if !isLength && ftype.JSType != "number" {
+			return nil, "", errors.Errorf("%s: only numbers, strings, slices and maps can have a %s", name, name)
+		}
+		n, err := strconv.ParseFloat(value, 64)
+		if err != nil {
+			return nil, "", errors.Errorf("%s: invalid number %q", name, value)
+		}
+		if constraints == nil {
+			constraints = &FieldConstraints{}
+		}
+		if !isLength {
+			if name == "min" {
+				constraints.Min = &n
+			} else {
+				constraints.Max = &n
+			}
+			continue
+		}
+		length := int(n)
+		if float64(length) != n || length < 0 {
+			return nil, "", errors.Errorf("%s: invalid length %q", name, value)
+		}
+		if name == "min" {
+			constraints.MinLength = &length
+		} else {
+			constraints.MaxLength = &length
+		}
+	}
+	pattern, found, rest := extractDirective(comment, "pattern:")
+	if found {
+		comment = rest
+		if ftype.JSType != "string" {
+			return nil, "", errors.New("pattern: only strings can have a pattern")
+		}
+		if _, err := regexp.Compile(pattern); err != nil {
+			return nil, "", errors.Errorf("pattern: invalid regular expression %q", pattern)
+		}
+		if constraints == nil {
+			constraints = &FieldConstraints{}
+		}
+		constraints.Pattern = pattern
+	}
+	return constraints, comment, nil
+}
+
+// checkConstraints returns an error if the constraints (which may
+// be nil) conflict, like a Min that is greater than the Max.
+func checkConstraints(constraints *FieldConstraints) error {
+	if constraints == nil {
+		return nil
+	}
+	if constraints.Min != nil && constraints.Max != nil && *constraints.Min > *constraints.Max {
+		return errors.Errorf("min %v is greater than max %v", *constraints.Min, *constraints.Max)
+	}
+	if constraints.MinLength != nil && constraints.MaxLength != nil && *constraints.MinLength > *constraints.MaxLength {
+		return errors.Errorf("min length %d is greater than max length %d", *constraints.MinLength, *constraints.MaxLength)
+	}
+	return nil
+}
@@ -0,0 +1,86 @@
+package oto
+
+import (
+	"testing"
+
+	"github.com/matryer/is"
+)
+
+func TestParseValidateTag(t *testing.T) {
+	is := is.New(t)
+	str := FieldType{JSType: "string"}
+	num := FieldType{JSType: "number"}
+	float := func(n float64) *float64 { return &n }
+	length := func(n int) *int { return &n }
+
+	constraints, err := parseValidateTag(FieldTag{Value: "required", Options: []string{"min=1", "max=140"}}, str)
+	is.NoErr(err)
+	is.Equal(*constraints, FieldConstraints{MinLength: length(1), MaxLength: length(140)})
+	constraints, err = parseValidateTag(FieldTag{Value: "min=1", Options: []string{"max=9.5"}}, num)
+	is.NoErr(err)
+	is.Equal(*constraints, FieldConstraints{Min: float(1), Max: float(9.5)})
+	constraints, err = parseValidateTag(FieldTag{Value: "len=3"}, str)
+	is.NoErr(err)
+	is.Equal(*constraints, FieldConstraints{MinLength: length(3), MaxLength: length(3)})
+	constraints, err = parseValidateTag(FieldTag{Value: "oneof=1 2 3"}, num)
+	is.NoErr(err)
+	is.Equal(constraints.Enum, []interface{}{float64(1), float64(2), float64(3)})
+	constraints, err = parseValidateTag(FieldTag{Value: "url"}, str)
+	is.NoErr(err)
+	is.Equal(*constraints, FieldConstraints{URL: true})
+	constraints, err = parseValidateTag(FieldTag{Value: "required"}, str)
+	is.NoErr(err)
+	is.Equal(constraints, (*FieldConstraints)(nil)) // nothing about the value
+	constraints, err = parseValidateTag(FieldTag{Value: "required", Options: []string{"uuid4", "startswith=ab", "max=36"}}, str)
+	is.NoErr(err)
+	is.Equal(*constraints, FieldConstraints{MaxLength: length(36), Other: []string{"uuid4", "startswith=ab"}}) // unknown validators are kept
+	constraints, err = parseValidateTag(FieldTag{Value: "dive", Options: []string{"min=1"}}, FieldType{JSType: "string", Multiple: true})
+	is.NoErr(err)
+	is.Equal(constraints, (*FieldConstraints)(nil)) // for the elements
+
+	for _, tag := range []FieldTag{
+		{Value: "min=one"},
+		{Value: "max=1.5"}, // lengths are whole numbers
+		{Value: "min=-1"},
+	} {
+		_, err := parseValidateTag(tag, str)
+		if err == nil {
+			t.Errorf("%v: expected error", tag)
+		}
+	}
+	_, err = parseValidateTag(FieldTag{Value: "oneof=1 two"}, num)
+	is.True(err != nil)
+}
+
+func TestParseConstraintLines(t *testing.T) {
+	is := is.New(t)
+	str := FieldType{JSType: "string"}
+	num := FieldType{JSType: "number"}
+	float := func(n float64) *float64 { return &n }
+	length := func(n int) *int { return &n }
+
+	constraints, comment, err := parseConstraintLines("Name is the name.\nmin: 1\nmax: 10\npattern: ^[a-z]+$", str, nil)
+	is.NoErr(err)
+	is.Equal(comment, "Name is the name.")
+	is.Equal(*constraints, FieldConstraints{MinLength: length(1), MaxLength: length(10), Pattern: "^[a-z]+$"})
+	constraints, _, err = parseConstraintLines("max: 9.5", num, &FieldConstraints{Min: float(1), Max: float(100)})
+	is.NoErr(err)
+	is.Equal(*constraints, FieldConstraints{Min: float(1), Max: float(9.5)})
+	constraints, comment, err = parseConstraintLines("Name is the name.", str, nil)
+	is.NoErr(err)
+	is.Equal(comment, "Name is the name.")
+	is.True(constraints == nil)
+
+	_, _, err = parseConstraintLines("min: lots", num, nil)
+	is.Equal(err.Error(), `min: invalid number "lots"`)
+	_, _, err = parseConstraintLines("max: 1.5", str, nil)
+	is.Equal(err.Error(), `max: invalid length "1.5"`)
+	_, _, err = parseConstraintLines("min: 1", FieldType{JSType: "boolean"}, nil)
+	is.Equal(err.Error(), "min: only numbers, strings, slices and maps can have a min")
+	_, _, err = parseConstraintLines("pattern: [a-z", str, nil)
+	is.Equal(err.Error(), `pattern: invalid regular expression "[a-z"`)
+
+	is.NoErr(checkConstraints(nil))
+	is.Equal(checkConstraints(&FieldConstraints{Min: float(2), Max: float(1)}).Error(), "min 2 is greater than max 1")
+	is.Equal(checkConstraints(&FieldConstraints{MinLength: length(2), MaxLength: length(1)}).Error(), "min length 2 is greater than max length 1")
+}
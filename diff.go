@@ -0,0 +1,244 @@
+package oto
+
+// DefinitionDiff describes the changes between two Definitions.
+type DefinitionDiff struct {
+	AddedServices   []Service     `json:"addedServices"`
+	RemovedServices []Service     `json:"removedServices"`
+	ChangedServices []ServiceDiff `json:"changedServices"`
+	AddedObjects    []Object      `json:"addedObjects"`
+	RemovedObjects  []Object      `json:"removedObjects"`
+	ChangedObjects  []ObjectDiff  `json:"changedObjects"`
+}
+
+// ServiceDiff describes the changes to the methods of a Service.
+type ServiceDiff struct {
+	Name           string       `json:"name"`
+	AddedMethods   []Method     `json:"addedMethods"`
+	RemovedMethods []Method     `json:"removedMethods"`
+	ChangedMethods []MethodDiff `json:"changedMethods"`
+}
+
+// MethodDiff describes a Method whose input or output type changed.
+type MethodDiff struct {
+	Name   string `json:"name"`
+	Before Method `json:"before"`
+	After  Method `json:"after"`
+}
+
+// ObjectDiff describes the changes to the fields of an Object.
+type ObjectDiff struct {
+	Name          string      `json:"name"`
+	TypeID        string      `json:"typeID"`
+	AddedFields   []Field     `json:"addedFields"`
+	RemovedFields []Field     `json:"removedFields"`
+	ChangedFields []FieldDiff `json:"changedFields"`
+	// Input is true if the Object was used (directly or
+	// indirectly) as the input to a method.
+	Input bool `json:"input"`
+}
+
+// FieldDiff describes a Field whose type changed.
+type FieldDiff struct {
+	Name   string `json:"name"`
+	Before Field  `json:"before"`
+	After  Field  `json:"after"`
+}
+
+// Diff gets the changes from before to after.
+// Services and methods are matched by name, Objects by TypeID
+// and fields by name.
+func Diff(before, after Definition) DefinitionDiff {
+	var diff DefinitionDiff
+	afterServices := make(map[string]Service, len(after.Services))
+	for _, service := range after.Services {
+		afterServices[service.Name] = service
+	}
+	beforeServices := make(map[string]Service, len(before.Services))
+	for _, service := range before.Services {
+		beforeServices[service.Name] = service
+		afterService, ok := afterServices[service.Name]
+		if !ok {
+			diff.RemovedServices = append(diff.RemovedServices, service)
+			continue
+		}
+		if serviceDiff, changed := diffService(service, afterService); changed {
+			diff.ChangedServices = append(diff.ChangedServices, serviceDiff)
+		}
+	}
+	for _, service := range after.Services {
+		if _, ok := beforeServices[service.Name]; !ok {
+			diff.AddedServices = append(diff.AddedServices, service)
+		}
+	}
+	inputs := inputObjectTypeIDs(before)
+	afterObjects := make(map[string]Object, len(after.Objects))
+	for _, object := range after.Objects {
+		afterObjects[object.TypeID] = object
+	}
+	beforeObjects := make(map[string]Object, len(before.Objects))
+	for _, object := range before.Objects {
+		beforeObjects[object.TypeID] = object
+		afterObject, ok := afterObjects[object.TypeID]
+		if !ok {
+			diff.RemovedObjects = append(diff.RemovedObjects, object)
+			continue
+		}
+		_, isInput := inputs[object.TypeID]
+		if objectDiff, changed := diffObject(object, afterObject, isInput); changed {
+			diff.ChangedObjects = append(diff.ChangedObjects, objectDiff)
+		}
+	}
+	for _, object := range after.Objects {
+		if _, ok := beforeObjects[object.TypeID]; !ok {
+			diff.AddedObjects = append(diff.AddedObjects, object)
+		}
+	}
+	return diff
+}
+
+// IsBreaking gets whether the changes could break existing clients.
+// That is when a service or method was removed, a required field
+// was added to or removed from an input object, or the input or
+// output type of a method changed.
+// Whether a field is required comes from Field.Required, so an
+// omitempty field with a required validate tag is required.
+func (d DefinitionDiff) IsBreaking() bool {
+	if len(d.RemovedServices) > 0 {
+		return true
+	}
+	for _, service := range d.ChangedServices {
+		if len(service.RemovedMethods) > 0 || len(service.ChangedMethods) > 0 {
+			return true
+		}
+	}
+	for _, object := range d.ChangedObjects {
+		if !object.Input {
+			continue
+		}
+		for _, field := range object.RemovedFields {
+			if field.Required {
+				return true
+			}
+		}
+		for _, field := range object.AddedFields {
+			if field.Required {
+				// existing clients do not send it
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// diffService gets the changes to the methods of a Service, and
+// whether there are any.
+func diffService(before, after Service) (ServiceDiff, bool) {
+	diff := ServiceDiff{Name: before.Name}
+	afterMethods := make(map[string]Method, len(after.Methods))
+	for _, method := range after.Methods {
+		afterMethods[method.Name] = method
+	}
+	beforeMethods := make(map[string]Method, len(before.Methods))
+	for _, method := range before.Methods {
+		beforeMethods[method.Name] = method
+		afterMethod, ok := afterMethods[method.Name]
+		if !ok {
+			diff.RemovedMethods = append(diff.RemovedMethods, method)
+			continue
+		}
+		if !sameFieldType(method.InputObject, afterMethod.InputObject) || !sameFieldType(method.OutputObject, afterMethod.OutputObject) {
+			diff.ChangedMethods = append(diff.ChangedMethods, MethodDiff{
+				Name:   method.Name,
+				Before: method,
+				After:  afterMethod,
+			})
+		}
+	}
+	for _, method := range after.Methods {
+		if _, ok := beforeMethods[method.Name]; !ok {
+			diff.AddedMethods = append(diff.AddedMethods, method)
+		}
+	}
+	changed := len(diff.AddedMethods) > 0 || len(diff.RemovedMethods) > 0 || len(diff.ChangedMethods) > 0
+	return diff, changed
+}
+
+// diffObject gets the changes to the fields of an Object, and
+// whether there are any.
+func diffObject(before, after Object, isInput bool) (ObjectDiff, bool) {
+	diff := ObjectDiff{
+		Name:   before.Name,
+		TypeID: before.TypeID,
+		Input:  isInput,
+	}
+	afterFields := make(map[string]Field, len(after.Fields))
+	for _, field := range after.Fields {
+		afterFields[field.Name] = field
+	}
+	beforeFields := make(map[string]Field, len(before.Fields))
+	for _, field := range before.Fields {
+		beforeFields[field.Name] = field
+		afterField, ok := afterFields[field.Name]
+		if !ok {
+			diff.RemovedFields = append(diff.RemovedFields, field)
+			continue
+		}
+		if !sameFieldType(field.Type, afterField.Type) {
+			diff.ChangedFields = append(diff.ChangedFields, FieldDiff{
+				Name:   field.Name,
+				Before: field,
+				After:  afterField,
+			})
+		}
+	}
+	for _, field := range after.Fields {
+		if _, ok := beforeFields[field.Name]; !ok {
+			diff.AddedFields = append(diff.AddedFields, field)
+		}
+	}
+	changed := len(diff.AddedFields) > 0 || len(diff.RemovedFields) > 0 || len(diff.ChangedFields) > 0
+	return diff, changed
+}
+
+// sameFieldType gets whether two FieldTypes describe the same type.
+func sameFieldType(a, b FieldType) bool {
+	return a.TypeID == b.TypeID &&
+		a.TypeName == b.TypeName &&
+		a.Multiple == b.Multiple &&
+		a.IsMap == b.IsMap
+}
+
+// inputObjectTypeIDs gets the TypeIDs of the Objects used (directly
+// or indirectly) as the input to a method.
+func inputObjectTypeIDs(def Definition) map[string]struct{} {
+	objects := make(map[string]Object, len(def.Objects))
+	for _, object := range def.Objects {
+		objects[object.TypeID] = object
+	}
+	inputs := make(map[string]struct{})
+	var visit func(ftype *FieldType)
+	visit = func(ftype *FieldType) {
+		if ftype == nil {
+			return
+		}
+		visit(ftype.ElementType)
+		visit(ftype.MapKeyType)
+		visit(ftype.MapValueType)
+		if !ftype.IsObject {
+			return
+		}
+		if _, ok := inputs[ftype.TypeID]; ok {
+			return
+		}
+		inputs[ftype.TypeID] = struct{}{}
+		for _, field := range objects[ftype.TypeID].Fields {
+			visit(&field.Type)
+		}
+	}
+	for _, service := range def.Services {
+		for _, method := range service.Methods {
+			visit(&method.InputObject)
+		}
+	}
+	return inputs
+}
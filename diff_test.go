@@ -0,0 +1,170 @@
+package oto
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/matryer/is"
+)
+
+func TestDiff(t *testing.T) {
+	is := is.New(t)
+	stringType := FieldType{TypeID: "string", TypeName: "string", JSType: "string"}
+	intType := FieldType{TypeID: "int", TypeName: "int", JSType: "number"}
+	request := FieldType{TypeID: "example.com/api.GreetRequest", TypeName: "GreetRequest", ObjectName: "GreetRequest", IsObject: true}
+	response := FieldType{TypeID: "example.com/api.GreetResponse", TypeName: "GreetResponse", ObjectName: "GreetResponse", IsObject: true}
+	before := Definition{
+		Services: []Service{
+			{Name: "GreeterService", Methods: []Method{
+				{Name: "Greet", InputObject: request, OutputObject: response},
+				{Name: "Wave", InputObject: request, OutputObject: response},
+			}},
+			{Name: "LegacyService"},
+		},
+		Objects: []Object{
+			{TypeID: request.TypeID, Name: "GreetRequest", Fields: []Field{
+				{Name: "Name", Type: stringType},
+				{Name: "Age", Type: intType},
+			}},
+			{TypeID: response.TypeID, Name: "GreetResponse", Fields: []Field{
+				{Name: "Greeting", Type: stringType},
+			}},
+		},
+	}
+	after := Definition{
+		Services: []Service{
+			{Name: "GreeterService", Methods: []Method{
+				{Name: "Greet", InputObject: request, OutputObject: response},
+				{Name: "Hug", InputObject: request, OutputObject: response},
+			}},
+			{Name: "NewService"},
+		},
+		Objects: []Object{
+			{TypeID: request.TypeID, Name: "GreetRequest", Fields: []Field{
+				{Name: "Name", Type: stringType},
+				{Name: "Age", Type: stringType},
+				{Name: "Nickname", Type: stringType},
+			}},
+			{TypeID: "example.com/api.HugRequest", Name: "HugRequest"},
+		},
+	}
+
+	diff := Diff(before, after)
+	is.Equal(len(diff.AddedServices), 1)
+	is.Equal(diff.AddedServices[0].Name, "NewService")
+	is.Equal(len(diff.RemovedServices), 1)
+	is.Equal(diff.RemovedServices[0].Name, "LegacyService")
+	is.Equal(len(diff.ChangedServices), 1)
+	is.Equal(diff.ChangedServices[0].Name, "GreeterService")
+	is.Equal(len(diff.ChangedServices[0].AddedMethods), 1)
+	is.Equal(diff.ChangedServices[0].AddedMethods[0].Name, "Hug")
+	is.Equal(len(diff.ChangedServices[0].RemovedMethods), 1)
+	is.Equal(diff.ChangedServices[0].RemovedMethods[0].Name, "Wave")
+	is.Equal(len(diff.ChangedServices[0].ChangedMethods), 0)
+
+	is.Equal(len(diff.AddedObjects), 1)
+	is.Equal(diff.AddedObjects[0].Name, "HugRequest")
+	is.Equal(len(diff.RemovedObjects), 1)
+	is.Equal(diff.RemovedObjects[0].Name, "GreetResponse")
+	is.Equal(len(diff.ChangedObjects), 1)
+	changed := diff.ChangedObjects[0]
+	is.Equal(changed.Name, "GreetRequest")
+	is.Equal(changed.Input, true)
+	is.Equal(len(changed.AddedFields), 1)
+	is.Equal(changed.AddedFields[0].Name, "Nickname")
+	is.Equal(len(changed.RemovedFields), 0)
+	is.Equal(len(changed.ChangedFields), 1)
+	is.Equal(changed.ChangedFields[0].Name, "Age")
+	is.Equal(changed.ChangedFields[0].Before.Type.TypeName, "int")
+	is.Equal(changed.ChangedFields[0].After.Type.TypeName, "string")
+
+	is.Equal(diff.IsBreaking(), true)                  // services and methods were removed
+	is.Equal(Diff(before, before).IsBreaking(), false) // no changes
+	is.Equal(Diff(before, before), DefinitionDiff{})
+}
+
+func TestDiffIsBreaking(t *testing.T) {
+	is := is.New(t)
+	request := FieldType{TypeID: "example.com/api.Request", TypeName: "Request", ObjectName: "Request", IsObject: true}
+	otherRequest := FieldType{TypeID: "example.com/api.OtherRequest", TypeName: "OtherRequest", ObjectName: "OtherRequest", IsObject: true}
+	response := FieldType{TypeID: "example.com/api.Response", TypeName: "Response", ObjectName: "Response", IsObject: true}
+	def := func(input FieldType, inputFields, outputFields []Field) Definition {
+		return Definition{
+			Services: []Service{
+				{Name: "Service", Methods: []Method{
+					{Name: "Method", InputObject: input, OutputObject: response},
+				}},
+			},
+			Objects: []Object{
+				{TypeID: input.TypeID, Name: input.ObjectName, Fields: inputFields},
+				{TypeID: response.TypeID, Name: "Response", Fields: outputFields},
+			},
+		}
+	}
+	name := Field{Name: "Name", Required: true, Type: FieldType{TypeID: "string", TypeName: "string"}}
+	optional := Field{Name: "Optional", OmitEmpty: true, Type: FieldType{TypeID: "string", TypeName: "string"}}
+	nullable := Field{Name: "Nullable", Type: FieldType{TypeID: "string", TypeName: "string", Nullable: true}}
+
+	// removing a required input field is breaking
+	diff := Diff(def(request, []Field{name}, nil), def(request, nil, nil))
+	is.Equal(diff.IsBreaking(), true)
+
+	// removing optional input fields is not
+	diff = Diff(def(request, []Field{name, optional, nullable}, nil), def(request, []Field{name}, nil))
+	is.Equal(diff.IsBreaking(), false)
+
+	// removing an output field is not
+	diff = Diff(def(request, nil, []Field{name}), def(request, nil, nil))
+	is.Equal(diff.IsBreaking(), false)
+
+	// adding optional input fields and output fields is not
+	diff = Diff(def(request, nil, nil), def(request, []Field{optional, nullable}, []Field{name}))
+	is.Equal(diff.IsBreaking(), false)
+
+	// adding a required input field is
+	diff = Diff(def(request, nil, nil), def(request, []Field{name}, nil))
+	is.Equal(diff.IsBreaking(), true)
+
+	// changing the input type of a method is
+	diff = Diff(def(request, nil, nil), def(otherRequest, nil, nil))
+	is.Equal(diff.IsBreaking(), true)
+	is.Equal(len(diff.ChangedServices), 1)
+	is.Equal(len(diff.ChangedServices[0].ChangedMethods), 1)
+	is.Equal(diff.ChangedServices[0].ChangedMethods[0].After.InputObject.TypeName, "OtherRequest")
+}
+
+func TestDiffIsBreakingRequiredTags(t *testing.T) {
+	is := is.New(t)
+	after, err := NewParser("./testdata/services/required").Parse()
+	is.NoErr(err)
+	// without removes the named fields from UpdateRequest
+	without := func(names ...string) Definition {
+		var def Definition
+		b, err := json.Marshal(after)
+		is.NoErr(err)
+		is.NoErr(json.Unmarshal(b, &def))
+		for i := range def.Objects {
+			if def.Objects[i].Name != "UpdateRequest" {
+				continue
+			}
+			var fields []Field
+			for _, field := range def.Objects[i].Fields {
+				if !isInSlice(names, field.Name) {
+					fields = append(fields, field)
+				}
+			}
+			def.Objects[i].Fields = fields
+		}
+		return def
+	}
+
+	// Email is omitempty, but has validate:"required"
+	diff := Diff(without("Email"), after)
+	is.Equal(diff.ChangedObjects[0].AddedFields[0].Name, "Email")
+	is.Equal(diff.IsBreaking(), true)
+
+	// Nickname is just omitempty
+	diff = Diff(without("Nickname"), after)
+	is.Equal(diff.ChangedObjects[0].AddedFields[0].Name, "Nickname")
+	is.Equal(diff.IsBreaking(), false)
+}
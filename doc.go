@@ -0,0 +1,33 @@
+// Package oto parses Go interfaces that describe services into a
+// Definition, which templates and generators turn into code.
+//
+// The interfaces in the packages matching the patterns become Services,
+// and the structs they use become Objects:
+//
+//	parser := oto.NewParser("./definitions")
+//	parser.ExcludeInterfaces = []string{"Internal*"}
+//	def, err := parser.Parse()
+//	if err != nil {
+//		return err
+//	}
+//	out, err := oto.Render(template, def, nil)
+//
+// The Definition can also be written in one of the built-in formats
+// with GenerateOpenAPI, GenerateJSONSchema, GenerateGraphQLSchema,
+// GenerateTypeScript, GeneratePython or GenerateProto.
+//
+// Errors in the source (like invalid method signatures) are
+// ParseErrors, which have the File, Line and Col of the problem. Use
+// errors.As to get them.
+//
+// The oto command (see cmd/oto) is installed with:
+//
+//	go install github.com/pacedotdev/oto/cmd/oto@latest
+//
+// # Stability
+//
+// The exported API follows semantic versioning: within a major
+// version, exported identifiers are not removed or renamed, and
+// exported fields of Definition (and the types in it) keep their
+// meaning. New fields and functions may be added in minor versions.
+package oto
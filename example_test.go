@@ -0,0 +1,26 @@
+package oto_test
+
+import (
+	"fmt"
+	"log"
+
+	"github.com/pacedotdev/oto"
+)
+
+func Example() {
+	parser := oto.NewParser("./testdata/services/pleasantries")
+	parser.ExcludeInterfaces = []string{"Ignorer"}
+	def, err := parser.Parse()
+	if err != nil {
+		log.Fatalln(err)
+	}
+	for _, service := range def.Services {
+		for _, method := range service.Methods {
+			fmt.Printf("%s.%s(%s) %s\n", service.Name, method.Name, method.InputObject.TypeName, method.OutputObject.TypeName)
+		}
+	}
+	// Output:
+	// GreeterService.GetGreetings(GetGreetingsRequest) GetGreetingsResponse
+	// GreeterService.Greet(GreetRequest) GreetResponse
+	// Welcomer.Welcome(WelcomeRequest) WelcomeResponse
+}
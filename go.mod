@@ -1,22 +1,47 @@
 module github.com/pacedotdev/oto
 
-go 1.13
+go 1.22.0
 
 require (
+	github.com/bufbuild/protocompile v0.14.1
 	github.com/dustin/go-humanize v1.0.0
 	github.com/fatih/structtag v1.2.0
+	github.com/fsnotify/fsnotify v1.7.0
+	github.com/gobuffalo/plush v3.8.3+incompatible
+	github.com/markbates/inflect v1.0.4
+	github.com/matryer/is v1.4.0
+	github.com/pkg/errors v0.9.1
+	github.com/vektah/gqlparser/v2 v2.5.11
+	github.com/xeipuuv/gojsonschema v1.2.0
+	golang.org/x/tools v0.26.0
+	gopkg.in/yaml.v3 v3.0.1
+)
+
+require (
+	github.com/agnivade/levenshtein v1.1.1 // indirect
+	github.com/aymerick/douceur v0.2.0 // indirect
+	github.com/chris-ramon/douceur v0.2.0 // indirect
+	github.com/fatih/structs v1.1.0 // indirect
 	github.com/gobuffalo/envy v1.9.0 // indirect
 	github.com/gobuffalo/flect v0.2.1 // indirect
+	github.com/gobuffalo/github_flavored_markdown v1.1.0 // indirect
 	github.com/gobuffalo/helpers v0.6.1 // indirect
-	github.com/gobuffalo/plush v3.8.3+incompatible
 	github.com/gobuffalo/tags v2.1.7+incompatible // indirect
+	github.com/gobuffalo/tags/v3 v3.1.0 // indirect
 	github.com/gobuffalo/validate/v3 v3.3.0 // indirect
 	github.com/gofrs/uuid v3.3.0+incompatible // indirect
-	github.com/markbates/inflect v1.0.4
-	github.com/matryer/is v1.4.0
+	github.com/gorilla/css v1.0.0 // indirect
+	github.com/joho/godotenv v1.3.0 // indirect
 	github.com/microcosm-cc/bluemonday v1.0.3 // indirect
-	github.com/pkg/errors v0.9.1
 	github.com/rogpeppe/go-internal v1.6.0 // indirect
-	golang.org/x/net v0.0.0-20200707034311-ab3426394381 // indirect
-	golang.org/x/tools v0.0.0-20200714190737-9048b464a08d
+	github.com/sergi/go-diff v1.3.1 // indirect
+	github.com/sourcegraph/annotate v0.0.0-20160123013949-f4cad6c6324d // indirect
+	github.com/sourcegraph/syntaxhighlight v0.0.0-20170531221838-bd320f5d308e // indirect
+	github.com/xeipuuv/gojsonpointer v0.0.0-20180127040702-4e3ac2762d5f // indirect
+	github.com/xeipuuv/gojsonreference v0.0.0-20180127040603-bd5ef7bd5415 // indirect
+	golang.org/x/mod v0.21.0 // indirect
+	golang.org/x/net v0.30.0 // indirect
+	golang.org/x/sync v0.8.0 // indirect
+	golang.org/x/sys v0.26.0 // indirect
+	google.golang.org/protobuf v1.34.2 // indirect
 )
@@ -0,0 +1,204 @@
+package oto
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+)
+
+// GenerateGraphQLSchema generates a GraphQL schema (in SDL) from
+// the Definition.
+// Methods whose names begin with Get, List or Find are added to the
+// Query type, and the rest to the Mutation type, unless they have an
+// explicit GraphQLOperation. Fields are named after the service and
+// method, like greeterServiceGreet.
+// Objects used by method inputs become input types, and objects used
+// by method outputs become types. Objects that are used by both get
+// an Input suffix for their input type.
+func GenerateGraphQLSchema(def Definition) (string, error) {
+	g := &graphQLGenerator{
+		objects:       make(map[string]Object),
+		inputObjects:  make(map[string]struct{}),
+		outputObjects: make(map[string]struct{}),
+	}
+	for _, object := range def.Objects {
+		g.objects[object.Name] = object
+	}
+	var queries, mutations []string
+	for _, service := range def.Services {
+		for _, method := range service.Methods {
+			g.markObjects(g.inputObjects, method.InputObject)
+			g.markObjects(g.outputObjects, method.OutputObject)
+			field, err := g.methodField(service, method)
+			if err != nil {
+				return "", err
+			}
+			if graphQLIsQuery(method) {
+				queries = append(queries, field)
+			} else {
+				mutations = append(mutations, field)
+			}
+		}
+	}
+	var buf bytes.Buffer
+	if len(queries) > 0 {
+		buf.WriteString("type Query {\n")
+		buf.WriteString(strings.Join(queries, ""))
+		buf.WriteString("}\n\n")
+	}
+	if len(mutations) > 0 {
+		buf.WriteString("type Mutation {\n")
+		buf.WriteString(strings.Join(mutations, ""))
+		buf.WriteString("}\n\n")
+	}
+	for _, object := range def.Objects {
+		if len(object.Fields) == 0 {
+			// GraphQL types must have fields
+			continue
+		}
+		if _, ok := g.inputObjects[object.Name]; ok {
+			g.writeObject(&buf, "input", g.inputName(object.Name), object, true)
+		}
+		if _, ok := g.outputObjects[object.Name]; ok {
+			g.writeObject(&buf, "type", object.Name, object, false)
+		}
+	}
+	if g.usesJSON {
+		buf.WriteString(`"""
+JSON is any JSON value.
+"""
+scalar JSON
+`)
+	}
+	return strings.TrimSpace(buf.String()) + "\n", nil
+}
+
+type graphQLGenerator struct {
+	objects       map[string]Object
+	inputObjects  map[string]struct{}
+	outputObjects map[string]struct{}
+	usesJSON      bool
+}
+
+// graphQLIsQuery gets whether the method belongs in the
+// Query type.
+func graphQLIsQuery(method Method) bool {
+	switch method.GraphQLOperation {
+	case "query":
+		return true
+	case "mutation":
+		return false
+	}
+	for _, prefix := range []string{"Get", "List", "Find"} {
+		if strings.HasPrefix(method.Name, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// markObjects adds the object (and all objects it refers to) to
+// the set.
+func (g *graphQLGenerator) markObjects(set map[string]struct{}, ftype FieldType) {
+	if isNestedSlice(ftype) {
+		g.markObjects(set, *ftype.ElementType)
+		return
+	}
+	if !ftype.IsObject {
+		return
+	}
+	if _, ok := set[ftype.ObjectName]; ok {
+		return
+	}
+	set[ftype.ObjectName] = struct{}{}
+	for _, field := range g.objects[ftype.ObjectName].Fields {
+		g.markObjects(set, field.Type)
+	}
+}
+
+func (g *graphQLGenerator) methodField(service Service, method Method) (string, error) {
+	var buf bytes.Buffer
+	writeGraphQLDescription(&buf, "\t", method.Comment)
+	fmt.Fprintf(&buf, "\t%s%s", camelizeDown(service.Name), method.Name)
+	if method.HasInput {
+		input, ok := g.objects[method.InputObject.ObjectName]
+		if !ok {
+			return "", fmt.Errorf("%s.%s: missing input object %s", service.Name, method.Name, method.InputObject.ObjectName)
+		}
+		if len(input.Fields) > 0 {
+			fmt.Fprintf(&buf, "(input: %s!)", g.inputName(input.Name))
+		}
+	}
+	if !method.HasOutput {
+		// GraphQL fields must have a type
+		buf.WriteString(": Boolean\n")
+		return buf.String(), nil
+	}
+	if _, ok := g.objects[method.OutputObject.ObjectName]; !ok {
+		return "", fmt.Errorf("%s.%s: missing output object %s", service.Name, method.Name, method.OutputObject.ObjectName)
+	}
+	fmt.Fprintf(&buf, ": %s!\n", method.OutputObject.ObjectName)
+	return buf.String(), nil
+}
+
+// inputName gets the name of the input type for the object.
+func (g *graphQLGenerator) inputName(objectName string) string {
+	if _, ok := g.outputObjects[objectName]; ok {
+		return objectName + "Input"
+	}
+	return objectName
+}
+
+func (g *graphQLGenerator) writeObject(buf *bytes.Buffer, kind, name string, object Object, input bool) {
+	writeGraphQLDescription(buf, "", object.Comment)
+	fmt.Fprintf(buf, "%s %s {\n", kind, name)
+	for _, field := range object.Fields {
+		writeGraphQLDescription(buf, "\t", field.Comment)
+		typ := g.fieldType(field.Type, input)
+		if field.Required && !field.Type.Nullable {
+			typ += "!"
+		}
+		fmt.Fprintf(buf, "\t%s: %s\n", field.NameLowerCamel, typ)
+	}
+	buf.WriteString("}\n\n")
+}
+
+func (g *graphQLGenerator) fieldType(ftype FieldType, input bool) string {
+	var typ string
+	switch {
+	case isNestedSlice(ftype):
+		typ = g.fieldType(*ftype.ElementType, input)
+	case ftype.IsObject && len(g.objects[ftype.ObjectName].Fields) > 0:
+		typ = ftype.ObjectName
+		if input {
+			typ = g.inputName(ftype.ObjectName)
+		}
+	case ftype.JSType == "string":
+		typ = "String"
+	case ftype.JSType == "boolean":
+		typ = "Boolean"
+	case ftype.JSType == "number" && isIntegerTypeName(ftype.TypeName):
+		typ = "Int"
+	case ftype.JSType == "number":
+		typ = "Float"
+	default:
+		g.usesJSON = true
+		typ = "JSON"
+	}
+	if ftype.Multiple {
+		typ = "[" + typ + "!]"
+	}
+	return typ
+}
+
+func writeGraphQLDescription(buf *bytes.Buffer, indent, comment string) {
+	if comment == "" {
+		return
+	}
+	comment = strings.Replace(comment, `"""`, `\"""`, -1)
+	buf.WriteString(indent + `"""` + "\n")
+	for _, line := range strings.Split(comment, "\n") {
+		buf.WriteString(indent + line + "\n")
+	}
+	buf.WriteString(indent + `"""` + "\n")
+}
@@ -0,0 +1,92 @@
+package oto
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/matryer/is"
+	"github.com/vektah/gqlparser/v2"
+	"github.com/vektah/gqlparser/v2/ast"
+)
+
+func TestGenerateGraphQLSchema(t *testing.T) {
+	is := is.New(t)
+	parser := NewParser("./testdata/services/pleasantries")
+	parser.ExcludeInterfaces = []string{"Ignorer"}
+	def, err := parser.Parse()
+	is.NoErr(err)
+
+	s, err := GenerateGraphQLSchema(def)
+	is.NoErr(err)
+	schema, gqlErr := gqlparser.LoadSchema(&ast.Source{Name: "schema.graphql", Input: s})
+	if gqlErr != nil {
+		t.Fatalf("%s\n%s", gqlErr, s)
+	}
+	is.True(schema.Query != nil)
+	is.True(schema.Query.Fields.ForName("greeterServiceGetGreetings") != nil)
+	is.True(schema.Mutation != nil)
+	is.True(schema.Mutation.Fields.ForName("greeterServiceGreet") != nil)
+	is.True(schema.Mutation.Fields.ForName("welcomerWelcome") != nil)
+	greet := schema.Mutation.Fields.ForName("greeterServiceGreet")
+	is.Equal(greet.Description, "Greet creates a Greeting for one or more people.")
+	is.Equal(greet.Arguments.ForName("input").Type.String(), "GreetRequest!")
+	is.Equal(greet.Type.String(), "GreetResponse!")
+
+	greetRequest := schema.Types["GreetRequest"]
+	is.Equal(greetRequest.Kind, ast.InputObject)
+	is.Equal(greetRequest.Fields.ForName("names").Type.String(), "[String!]!")
+	welcomeResponse := schema.Types["WelcomeResponse"]
+	is.Equal(welcomeResponse.Kind, ast.Object)
+	is.Equal(welcomeResponse.Fields.ForName("message").Type.String(), "String!")
+	is.Equal(welcomeResponse.Fields.ForName("error").Type.String(), "String")
+	welcomeRequest := schema.Types["WelcomeRequest"]
+	is.Equal(welcomeRequest.Fields.ForName("times").Type.String(), "Int!")
+}
+
+func TestGenerateGraphQLSchemaOperations(t *testing.T) {
+	is := is.New(t)
+	parser := NewParser("./testdata/services/graphql")
+	def, err := parser.Parse()
+	is.NoErr(err)
+	is.Equal(def.Services[0].Methods[0].Name, "GetStats")
+	is.Equal(def.Services[0].Methods[0].GraphQLOperation, "mutation")
+	is.Equal(def.Services[0].Methods[0].Comment, "GetStats gets stats, but also resets them.")
+
+	s, err := GenerateGraphQLSchema(def)
+	is.NoErr(err)
+	schema, gqlErr := gqlparser.LoadSchema(&ast.Source{Name: "schema.graphql", Input: s})
+	if gqlErr != nil {
+		t.Fatalf("%s\n%s", gqlErr, s)
+	}
+	is.True(schema.Query.Fields.ForName("searchServiceSearch") != nil)
+	getStats := schema.Mutation.Fields.ForName("searchServiceGetStats")
+	is.True(getStats != nil)
+	is.Equal(len(getStats.Arguments), 0) // no fields in GetStatsRequest
+	// Filter is used in input and output
+	is.Equal(schema.Types["FilterInput"].Kind, ast.InputObject)
+	is.Equal(schema.Types["Filter"].Kind, ast.Object)
+	is.Equal(schema.Types["SearchRequest"].Fields.ForName("filter").Type.String(), "FilterInput!")
+	is.Equal(schema.Types["SearchResponse"].Fields.ForName("facets").Type.String(), "JSON!")
+	is.Equal(schema.Types["Filter"].Fields.ForName("minScore").Type.String(), "Float!")
+	is.True(strings.Contains(s, "scalar JSON"))
+}
+
+func TestGenerateGraphQLSchemaWithoutPayloads(t *testing.T) {
+	is := is.New(t)
+	def, err := NewParser("./testdata/services/payloads").Parse()
+	is.NoErr(err)
+
+	s, err := GenerateGraphQLSchema(def)
+	is.NoErr(err)
+	schema, gqlErr := gqlparser.LoadSchema(&ast.Source{Name: "schema.graphql", Input: s})
+	if gqlErr != nil {
+		t.Fatalf("%s\n%s", gqlErr, s)
+	}
+	ping := schema.Mutation.Fields.ForName("healthServicePing")
+	is.True(ping != nil)
+	is.Equal(len(ping.Arguments), 0)
+	is.Equal(ping.Type.String(), "Boolean")
+	reset := schema.Mutation.Fields.ForName("healthServiceReset")
+	is.Equal(len(reset.Arguments), 1)
+	is.Equal(schema.Mutation.Fields.ForName("healthServiceStatus").Type.String(), "StatusResponse!")
+}
@@ -0,0 +1,229 @@
+package oto
+
+import (
+	"github.com/xeipuuv/gojsonschema"
+)
+
+// jsonSchemaDraft07 is the $schema of generated JSON Schemas.
+const jsonSchemaDraft07 = "http://json-schema.org/draft-07/schema#"
+
+// GenerateJSONSchema generates a JSON Schema (draft-07) with an entry
+// in $defs for each Object in the Definition.
+func GenerateJSONSchema(def Definition) (map[string]interface{}, error) {
+	defs := make(map[string]interface{})
+	for _, object := range def.Objects {
+		defs[object.Name] = jsonSchemaObject(object)
+	}
+	schema := map[string]interface{}{
+		"$schema": jsonSchemaDraft07,
+		"$defs":   defs,
+	}
+	return schema, nil
+}
+
+func jsonSchemaObject(object Object) map[string]interface{} {
+	properties := make(map[string]interface{})
+	required := make([]interface{}, 0, len(object.Fields))
+	for _, field := range object.Fields {
+		schema := jsonSchemaFieldType(field.Type)
+		if field.Comment != "" {
+			schema["description"] = field.Comment
+		}
+		if field.Example != nil {
+			schema["examples"] = []interface{}{field.Example}
+		}
+		if field.HasDefault {
+			schema["default"] = field.Default
+		}
+		if len(field.Enum) > 0 {
+			itemsSchema(schema, field.Type)["enum"] = field.Enum
+		}
+		if field.Constraints != nil {
+			addJSONSchemaConstraints(schema, field.Type, *field.Constraints)
+		}
+		properties[field.JSONName] = schema
+		if field.Required {
+			required = append(required, field.JSONName)
+		}
+	}
+	schema := map[string]interface{}{
+		"type":       "object",
+		"properties": properties,
+	}
+	if len(required) > 0 {
+		schema["required"] = required
+	}
+	if object.Comment != "" {
+		schema["description"] = object.Comment
+	}
+	if object.Example != nil {
+		schema["examples"] = []interface{}{object.Example}
+	}
+	return schema
+}
+
+func jsonSchemaFieldType(ftype FieldType) map[string]interface{} {
+	if isNestedSlice(ftype) {
+		schema := map[string]interface{}{
+			"type":  "array",
+			"items": jsonSchemaFieldType(*ftype.ElementType),
+		}
+		if ftype.Nullable {
+			schema["type"] = []interface{}{"array", "null"}
+		}
+		return schema
+	}
+	schema := make(map[string]interface{})
+	switch {
+	case len(ftype.OneOf) > 0:
+		oneOf := make([]interface{}, 0, len(ftype.OneOf)+1)
+		for _, name := range ftype.OneOf {
+			oneOf = append(oneOf, map[string]interface{}{"$ref": "#/$defs/" + name})
+		}
+		if ftype.Nullable {
+			oneOf = append(oneOf, map[string]interface{}{"type": "null"})
+		}
+		schema["oneOf"] = oneOf
+	case ftype.IsObject:
+		schema["$ref"] = "#/$defs/" + ftype.ObjectName
+		if isNullableObject(ftype) {
+			// $ref cannot be combined with a type
+			schema = map[string]interface{}{
+				"oneOf": []interface{}{
+					schema,
+					map[string]interface{}{"type": "null"},
+				},
+			}
+		}
+	case ftype.IsMap:
+		schema["type"] = "object"
+		if ftype.MapValueType != nil {
+			schema["additionalProperties"] = jsonSchemaFieldType(*ftype.MapValueType)
+		}
+	default:
+		typ := jsonSchemaType(ftype)
+		if typ != "" {
+			schema["type"] = typ
+			if ftype.Nullable {
+				schema["type"] = []interface{}{typ, "null"}
+			}
+		}
+		if ftype.Format != "" {
+			schema["format"] = ftype.Format
+		}
+	}
+	if ftype.Multiple {
+		schema = map[string]interface{}{
+			"type":  "array",
+			"items": schema,
+		}
+	}
+	return schema
+}
+
+// itemsSchema gets the schema of the items of a field that is a
+// slice, or else the schema itself.
+func itemsSchema(schema map[string]interface{}, ftype FieldType) map[string]interface{} {
+	if items, ok := schema["items"].(map[string]interface{}); ok && ftype.Multiple {
+		return items
+	}
+	return schema
+}
+
+// addJSONSchemaConstraints adds the keywords for the constraints
+// to the schema of a field.
+func addJSONSchemaConstraints(schema map[string]interface{}, ftype FieldType, constraints FieldConstraints) {
+	// lengths are the number of items, everything else
+	// is about the items
+	value := itemsSchema(schema, ftype)
+	if ftype.Multiple {
+		if constraints.MinLength != nil {
+			schema["minItems"] = *constraints.MinLength
+		}
+		if constraints.MaxLength != nil {
+			schema["maxItems"] = *constraints.MaxLength
+		}
+	} else if ftype.IsMap {
+		if constraints.MinLength != nil {
+			schema["minProperties"] = *constraints.MinLength
+		}
+		if constraints.MaxLength != nil {
+			schema["maxProperties"] = *constraints.MaxLength
+		}
+	} else {
+		if constraints.MinLength != nil {
+			schema["minLength"] = *constraints.MinLength
+		}
+		if constraints.MaxLength != nil {
+			schema["maxLength"] = *constraints.MaxLength
+		}
+	}
+	if constraints.Min != nil {
+		value["minimum"] = *constraints.Min
+	}
+	if constraints.Max != nil {
+		value["maximum"] = *constraints.Max
+	}
+	if constraints.Pattern != "" {
+		value["pattern"] = constraints.Pattern
+	}
+	if len(constraints.Enum) > 0 {
+		value["enum"] = constraints.Enum
+	}
+	if constraints.Email {
+		value["format"] = "email"
+	}
+	if constraints.URL {
+		value["format"] = "uri"
+	}
+}
+
+// jsonSchemaType gets the JSON Schema type for the FieldType.
+// Returns an empty string if any type is allowed.
+func jsonSchemaType(ftype FieldType) string {
+	switch ftype.JSType {
+	case "string", "boolean", "object":
+		return ftype.JSType
+	case "number":
+		if isIntegerTypeName(ftype.TypeName) {
+			return "integer"
+		}
+		return "number"
+	}
+	return ""
+}
+
+// ValidationError describes why a value does not match a
+// JSON Schema.
+type ValidationError struct {
+	// Field is the path to the invalid value.
+	Field string `json:"field"`
+	// Message describes the problem.
+	Message string `json:"message"`
+}
+
+// Validate validates the instance against the named object in a
+// schema generated by GenerateJSONSchema.
+// Returns no ValidationErrors if the instance is valid.
+func Validate(schema map[string]interface{}, objectName string, instance interface{}) ([]ValidationError, error) {
+	root := make(map[string]interface{}, len(schema)+1)
+	for k, v := range schema {
+		root[k] = v
+	}
+	root["$ref"] = "#/$defs/" + objectName
+	result, err := gojsonschema.Validate(
+		gojsonschema.NewGoLoader(root),
+		gojsonschema.NewGoLoader(instance),
+	)
+	if err != nil {
+		return nil, err
+	}
+	var validationErrors []ValidationError
+	for _, resultErr := range result.Errors() {
+		validationErrors = append(validationErrors, ValidationError{
+			Field:   resultErr.Field(),
+			Message: resultErr.Description(),
+		})
+	}
+	return validationErrors, nil
+}
@@ -0,0 +1,126 @@
+package oto
+
+import (
+	"testing"
+
+	"github.com/matryer/is"
+)
+
+func TestGenerateJSONSchema(t *testing.T) {
+	is := is.New(t)
+	parser := NewParser("./testdata/services/pleasantries")
+	parser.ExcludeInterfaces = []string{"Ignorer"}
+	def, err := parser.Parse()
+	is.NoErr(err)
+
+	schema, err := GenerateJSONSchema(def)
+	is.NoErr(err)
+	is.Equal(schema["$schema"], jsonSchemaDraft07)
+	defs := schema["$defs"].(map[string]interface{})
+	is.Equal(len(defs), len(def.Objects))
+
+	welcomeRequest := defs["WelcomeRequest"].(map[string]interface{})
+	is.Equal(welcomeRequest["type"], "object")
+	is.Equal(welcomeRequest["description"], "WelcomeRequest is the request object for Welcomer.Welcome.")
+	properties := welcomeRequest["properties"].(map[string]interface{})
+	is.Equal(properties["to"].(map[string]interface{})["type"], "string")
+	is.Equal(properties["times"].(map[string]interface{})["type"], "integer")
+	is.Equal(properties["newCustomer"].(map[string]interface{})["type"], "boolean")
+	is.Equal(len(welcomeRequest["required"].([]interface{})), 4)
+
+	getGreetingsResponse := defs["GetGreetingsResponse"].(map[string]interface{})
+	properties = getGreetingsResponse["properties"].(map[string]interface{})
+	greetings := properties["greetings"].(map[string]interface{})
+	is.Equal(greetings["type"], "array")
+	is.Equal(greetings["items"].(map[string]interface{})["$ref"], "#/$defs/Greeting")
+	// Error is omitted when empty, so not required
+	is.Equal(getGreetingsResponse["required"], []interface{}{"greetings"})
+}
+
+func TestGenerateJSONSchemaMaps(t *testing.T) {
+	is := is.New(t)
+	parser := NewParser("./testdata/services/jstypes")
+	def, err := parser.Parse()
+	is.NoErr(err)
+	schema, err := GenerateJSONSchema(def)
+	is.NoErr(err)
+	checkRequest := schema["$defs"].(map[string]interface{})["CheckRequest"].(map[string]interface{})
+	lookup := checkRequest["properties"].(map[string]interface{})["lookup"].(map[string]interface{})
+	is.Equal(lookup["type"], "object")
+	is.Equal(lookup["additionalProperties"].(map[string]interface{})["type"], "string")
+}
+
+func TestGenerateJSONSchemaOneOf(t *testing.T) {
+	is := is.New(t)
+	def, err := NewParser("./testdata/services/oneof").Parse()
+	is.NoErr(err)
+	schema, err := GenerateJSONSchema(def)
+	is.NoErr(err)
+	payRequest := schema["$defs"].(map[string]interface{})["PayRequest"].(map[string]interface{})
+	method := payRequest["properties"].(map[string]interface{})["method"].(map[string]interface{})
+	is.Equal(method["oneOf"], []interface{}{
+		map[string]interface{}{"$ref": "#/$defs/Card"},
+		map[string]interface{}{"$ref": "#/$defs/BankTransfer"},
+	})
+}
+
+func TestValidate(t *testing.T) {
+	is := is.New(t)
+	parser := NewParser("./testdata/services/pleasantries")
+	parser.ExcludeInterfaces = []string{"Ignorer"}
+	def, err := parser.Parse()
+	is.NoErr(err)
+	schema, err := GenerateJSONSchema(def)
+	is.NoErr(err)
+
+	validationErrors, err := Validate(schema, "WelcomeRequest", map[string]interface{}{
+		"to":          "your@email.com",
+		"name":        "John Smith",
+		"times":       3,
+		"newCustomer": true,
+	})
+	is.NoErr(err)
+	is.Equal(len(validationErrors), 0)
+
+	validationErrors, err = Validate(schema, "WelcomeRequest", map[string]interface{}{
+		"to":          "your@email.com",
+		"name":        "John Smith",
+		"times":       "three",
+		"newCustomer": true,
+	})
+	is.NoErr(err)
+	is.Equal(len(validationErrors), 1)
+	is.Equal(validationErrors[0].Field, "times")
+}
+
+func TestGenerateJSONSchemaNullableObjects(t *testing.T) {
+	is := is.New(t)
+	def, err := NewParser("./testdata/services/nullable").Parse()
+	is.NoErr(err)
+	schema, err := GenerateJSONSchema(def)
+	is.NoErr(err)
+	updateRequest := schema["$defs"].(map[string]interface{})["UpdateRequest"].(map[string]interface{})
+	address := updateRequest["properties"].(map[string]interface{})["address"].(map[string]interface{})
+	is.Equal(address["oneOf"], []interface{}{
+		map[string]interface{}{"$ref": "#/$defs/Address"},
+		map[string]interface{}{"type": "null"},
+	})
+	is.Equal(address["description"], "Address is an optional address.")
+
+	for _, test := range []struct {
+		address interface{}
+		valid   bool
+	}{
+		{address: nil, valid: true},
+		{address: map[string]interface{}{"line1": "1 Main Street"}, valid: true},
+		{address: "1 Main Street", valid: false},
+	} {
+		validationErrors, err := Validate(schema, "UpdateRequest", map[string]interface{}{
+			"address": test.address,
+			"bio":     nil,
+			"name":    "Mat",
+		})
+		is.NoErr(err)
+		is.Equal(len(validationErrors) == 0, test.valid) // address
+	}
+}
@@ -0,0 +1,164 @@
+package oto
+
+import (
+	"fmt"
+	"go/token"
+	"io"
+	"strings"
+	"unicode"
+)
+
+// LintIssue is a problem with the style of a Definition (see Lint).
+type LintIssue struct {
+	// Severity is "error" or "warning".
+	Severity string `json:"severity"`
+	// Path is what the issue is about, like "GreeterService",
+	// "GreeterService.Greet", "GreetRequest" or "GreetRequest.Name".
+	Path    string `json:"path"`
+	Message string `json:"message"`
+}
+
+// LintOptions are the checks that Lint makes.
+// Issues from the Require options and EnforceNamingConvention are
+// errors, and objects and services that are too big are warnings.
+type LintOptions struct {
+	// RequireMethodComments requires every method to have a comment.
+	RequireMethodComments bool
+	// RequireFieldComments requires every field to have a comment.
+	RequireFieldComments bool
+	// RequireExamples requires every field to have an example.
+	RequireExamples bool
+	// MaxMethodsPerService (if not zero) is the most methods a
+	// service should have.
+	MaxMethodsPerService int
+	// MaxFieldsPerObject (if not zero) is the most fields an object
+	// should have.
+	MaxFieldsPerObject int
+	// EnforceNamingConvention requires service names to end with
+	// Service, the input and output objects of methods to be named
+	// MethodRequest and MethodResponse, and JSON names of fields
+	// to be lowerCamel.
+	EnforceNamingConvention bool
+}
+
+const (
+	lintError   = "error"
+	lintWarning = "warning"
+)
+
+// Lint checks the style of an already parsed Definition, and gets
+// the issues it finds, services (with their methods) first, and then
+// objects (with their fields).
+func Lint(def Definition, opts LintOptions) []LintIssue {
+	var issues []LintIssue
+	issue := func(severity, path, format string, args ...interface{}) {
+		issues = append(issues, LintIssue{
+			Severity: severity,
+			Path:     path,
+			Message:  fmt.Sprintf(format, args...),
+		})
+	}
+	for _, service := range def.Services {
+		if opts.EnforceNamingConvention && !strings.HasSuffix(service.Name, "Service") {
+			issue(lintError, service.Name, "service names should end with Service")
+		}
+		if opts.MaxMethodsPerService > 0 && len(service.Methods) > opts.MaxMethodsPerService {
+			issue(lintWarning, service.Name, "has %d methods (more than %d)", len(service.Methods), opts.MaxMethodsPerService)
+		}
+		for _, method := range service.Methods {
+			path := service.Name + "." + method.Name
+			if opts.RequireMethodComments && method.Comment == "" {
+				issue(lintError, path, "has no comment")
+			}
+			if !opts.EnforceNamingConvention {
+				continue
+			}
+			if method.HasInput && method.InputObject.IsObject && method.InputObject.ObjectName != method.Name+"Request" {
+				issue(lintError, path, "input object %s should be named %sRequest", method.InputObject.ObjectName, method.Name)
+			}
+			if method.HasOutput && method.OutputObject.IsObject && method.OutputObject.ObjectName != method.Name+"Response" {
+				issue(lintError, path, "output object %s should be named %sResponse", method.OutputObject.ObjectName, method.Name)
+			}
+		}
+	}
+	for _, object := range def.Objects {
+		if opts.MaxFieldsPerObject > 0 && len(object.Fields) > opts.MaxFieldsPerObject {
+			issue(lintWarning, object.Name, "has %d fields (more than %d)", len(object.Fields), opts.MaxFieldsPerObject)
+		}
+		for _, field := range object.Fields {
+			if object.IsOutput && field.Name == "Error" && field.JSONName == "error" {
+				// added by the parser (see Parser.AddErrorField)
+				continue
+			}
+			path := object.Name + "." + field.Name
+			if opts.RequireFieldComments && field.Comment == "" {
+				issue(lintError, path, "has no comment")
+			}
+			if opts.RequireExamples && field.Example == nil {
+				issue(lintError, path, "has no example")
+			}
+			if opts.EnforceNamingConvention && !isLowerCamel(field.JSONName) {
+				issue(lintError, path, "JSON name %q should be lowerCamel", field.JSONName)
+			}
+		}
+	}
+	return issues
+}
+
+// isLowerCamel gets whether s is lowerCamel, like "userID".
+func isLowerCamel(s string) bool {
+	for i, r := range s {
+		if i == 0 && !unicode.IsLower(r) {
+			return false
+		}
+		if !unicode.IsLetter(r) && !unicode.IsDigit(r) {
+			return false
+		}
+	}
+	return s != ""
+}
+
+// lintPosition gets the source position of the service, method,
+// object or field at the Path of a LintIssue.
+func (p *Parser) lintPosition(path string) (token.Position, bool) {
+	if position, ok := p.positions["service:"+path]; ok {
+		return position, true
+	}
+	if position, ok := p.positions["method:"+path]; ok {
+		return position, true
+	}
+	objectName, fieldName, isField := strings.Cut(path, ".")
+	for _, object := range p.def.Objects {
+		if object.Name != objectName {
+			continue
+		}
+		if position, ok := p.positions["field:"+object.TypeID+"."+fieldName]; ok && isField {
+			return position, true
+		}
+		position, ok := p.positions["object:"+object.TypeID]
+		return position, ok
+	}
+	return token.Position{}, false
+}
+
+// WriteLintIssues writes the issues to w, one per line, like
+// "file.go:12:2: error: GreeterService.Greet: has no comment", using
+// the parsers to find their positions. It gets the number of issues
+// that are errors.
+func WriteLintIssues(w io.Writer, issues []LintIssue, parsers []*Parser) int {
+	var errorCount int
+	for _, issue := range issues {
+		if issue.Severity == lintError {
+			errorCount++
+		}
+		line := fmt.Sprintf("%s: %s: %s", issue.Severity, issue.Path, issue.Message)
+		for _, parser := range parsers {
+			if position, ok := parser.lintPosition(issue.Path); ok {
+				line = position.String() + ": " + line
+				break
+			}
+		}
+		fmt.Fprintln(w, line)
+	}
+	return errorCount
+}
@@ -0,0 +1,94 @@
+package oto
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/matryer/is"
+)
+
+func TestLint(t *testing.T) {
+	is := is.New(t)
+	stringType := FieldType{TypeID: "string", TypeName: "string", JSType: "string"}
+	request := FieldType{TypeID: "example.com/api.GreetRequest", TypeName: "GreetRequest", ObjectName: "GreetRequest", IsObject: true}
+	response := FieldType{TypeID: "example.com/api.Greeting", TypeName: "Greeting", ObjectName: "Greeting", IsObject: true}
+	def := Definition{
+		Services: []Service{
+			{Name: "Greeter", Methods: []Method{
+				{Name: "Greet", Comment: "Greet greets.", HasInput: true, InputObject: request, HasOutput: true, OutputObject: response},
+				{Name: "Wave"},
+			}},
+		},
+		Objects: []Object{
+			{Name: "GreetRequest", IsInput: true, Fields: []Field{
+				{Name: "Name", JSONName: "name", Comment: "Name is who to greet.", Example: "Mat", Type: stringType},
+				{Name: "UserID", JSONName: "user_id", Type: stringType},
+			}},
+			{Name: "Greeting", IsOutput: true, Fields: []Field{
+				{Name: "Text", JSONName: "text", Comment: "Text is the greeting.", Example: "Hi", Type: stringType},
+				{Name: "Error", JSONName: "error", Comment: "Error is added by the parser.", Type: stringType},
+			}},
+		},
+	}
+
+	is.Equal(len(Lint(def, LintOptions{})), 0) // no checks
+
+	issues := Lint(def, LintOptions{
+		RequireMethodComments:   true,
+		RequireFieldComments:    true,
+		RequireExamples:         true,
+		MaxMethodsPerService:    1,
+		MaxFieldsPerObject:      1,
+		EnforceNamingConvention: true,
+	})
+	is.Equal(issues, []LintIssue{
+		{Severity: "error", Path: "Greeter", Message: "service names should end with Service"},
+		{Severity: "warning", Path: "Greeter", Message: "has 2 methods (more than 1)"},
+		{Severity: "error", Path: "Greeter.Greet", Message: "output object Greeting should be named GreetResponse"},
+		{Severity: "error", Path: "Greeter.Wave", Message: "has no comment"},
+		{Severity: "warning", Path: "GreetRequest", Message: "has 2 fields (more than 1)"},
+		{Severity: "error", Path: "GreetRequest.UserID", Message: "has no comment"},
+		{Severity: "error", Path: "GreetRequest.UserID", Message: "has no example"},
+		{Severity: "error", Path: "GreetRequest.UserID", Message: `JSON name "user_id" should be lowerCamel`},
+		{Severity: "warning", Path: "Greeting", Message: "has 2 fields (more than 1)"},
+	})
+}
+
+func TestIsLowerCamel(t *testing.T) {
+	is := is.New(t)
+	is.True(isLowerCamel("name"))
+	is.True(isLowerCamel("userID"))
+	is.True(isLowerCamel("line2"))
+	is.True(!isLowerCamel(""))
+	is.True(!isLowerCamel("Name"))
+	is.True(!isLowerCamel("user_id"))
+	is.True(!isLowerCamel("user-id"))
+	is.True(!isLowerCamel("-"))
+}
+
+func TestWriteLintIssues(t *testing.T) {
+	is := is.New(t)
+
+	parser := NewParser("./testdata/services/pleasantries")
+	def, err := parser.Parse()
+	is.NoErr(err)
+	var buf bytes.Buffer
+	errorCount := WriteLintIssues(&buf, Lint(def, LintOptions{
+		RequireFieldComments:    true,
+		MaxFieldsPerObject:      3,
+		EnforceNamingConvention: true,
+	}), []*Parser{parser})
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	is.Equal(errorCount, 3)
+	is.Equal(len(lines), 4)
+	is.True(strings.HasSuffix(lines[0], "ignorer.go:4:6: error: Ignorer: service names should end with Service"))
+	is.True(strings.HasSuffix(lines[1], "welcomer.go:4:6: error: Welcomer: service names should end with Service"))
+	is.True(strings.HasSuffix(lines[2], "greeter.go:37:2: error: GetGreetingsResponse.Greetings: has no comment")) // fields have positions
+	is.True(strings.HasSuffix(lines[3], "welcomer.go:10:6: warning: WelcomeRequest: has 4 fields (more than 3)"))
+
+	// issues without a position are just the path
+	buf.Reset()
+	WriteLintIssues(&buf, []LintIssue{{Severity: "warning", Path: "Unknown", Message: "is unknown"}}, []*Parser{parser})
+	is.Equal(buf.String(), "warning: Unknown: is unknown\n")
+}
@@ -0,0 +1,75 @@
+package oto
+
+import (
+	"sort"
+
+	"github.com/pkg/errors"
+)
+
+// Merge adds the Services, Objects, Enums and Imports from the other
+// Definition into this one.
+// Objects and Enums with the same TypeID are only included once, but
+// it is an error for both Definitions to have a Service with the same
+// name. Services, Objects and Enums are sorted by name afterwards.
+// The Version of the other Definition is only used if this one does
+// not have one.
+func (d *Definition) Merge(other Definition) error {
+	for _, otherService := range other.Services {
+		for _, service := range d.Services {
+			if service.Name != otherService.Name {
+				continue
+			}
+			for _, method := range service.Methods {
+				for _, otherMethod := range otherService.Methods {
+					if method.Name == otherMethod.Name {
+						return errors.Errorf("merge: duplicate method %s.%s", service.Name, method.Name)
+					}
+				}
+			}
+			return errors.Errorf("merge: duplicate service %s with different methods", service.Name)
+		}
+	}
+	d.Services = append(d.Services, other.Services...)
+	if d.Version == "" {
+		d.Version = other.Version
+	}
+	typeIDs := make(map[string]struct{}, len(d.Objects))
+	for _, object := range d.Objects {
+		typeIDs[object.TypeID] = struct{}{}
+	}
+	for _, object := range other.Objects {
+		if _, ok := typeIDs[object.TypeID]; ok {
+			continue
+		}
+		typeIDs[object.TypeID] = struct{}{}
+		d.Objects = append(d.Objects, object)
+	}
+	enumTypeIDs := make(map[string]struct{}, len(d.Enums))
+	for _, enum := range d.Enums {
+		enumTypeIDs[enum.TypeID] = struct{}{}
+	}
+	for _, enum := range other.Enums {
+		if _, ok := enumTypeIDs[enum.TypeID]; ok {
+			continue
+		}
+		enumTypeIDs[enum.TypeID] = struct{}{}
+		d.Enums = append(d.Enums, enum)
+	}
+	if len(other.Imports) > 0 && d.Imports == nil {
+		d.Imports = make(map[string]string, len(other.Imports))
+	}
+	for path, name := range other.Imports {
+		d.Imports[path] = name
+	}
+	d.Warnings = append(d.Warnings, other.Warnings...)
+	sort.SliceStable(d.Services, func(i, j int) bool {
+		return d.Services[i].Name < d.Services[j].Name
+	})
+	sort.SliceStable(d.Objects, func(i, j int) bool {
+		return d.Objects[i].Name < d.Objects[j].Name
+	})
+	sort.SliceStable(d.Enums, func(i, j int) bool {
+		return d.Enums[i].Name < d.Enums[j].Name
+	})
+	return nil
+}
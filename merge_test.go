@@ -0,0 +1,93 @@
+package oto
+
+import (
+	"testing"
+
+	"github.com/matryer/is"
+)
+
+func TestMerge(t *testing.T) {
+	is := is.New(t)
+	def, err := NewParser("./testdata/services/pleasantries").Parse()
+	is.NoErr(err)
+	other, err := NewParser("./testdata/services/pointers").Parse()
+	is.NoErr(err)
+	err = def.Merge(other)
+	is.NoErr(err)
+
+	var pages int
+	for _, object := range def.Objects {
+		if object.TypeID == "github.com/pacedotdev/oto/testdata/services.Page" {
+			pages++
+		}
+	}
+	is.Equal(pages, 1) // shared objects are only included once
+	_, err = def.Object("UpdateRequest")
+	is.NoErr(err)
+	_, err = def.Object("GreetRequest")
+	is.NoErr(err)
+
+	var names []string
+	for _, service := range def.Services {
+		names = append(names, service.Name)
+	}
+	is.Equal(names, []string{"GreeterService", "Ignorer", "TeamService", "Welcomer"})
+	for i := 1; i < len(def.Objects); i++ {
+		is.True(def.Objects[i-1].Name <= def.Objects[i].Name) // objects are sorted
+	}
+}
+
+func TestMergeImports(t *testing.T) {
+	is := is.New(t)
+	def := Definition{
+		Imports: map[string]string{"time": "time"},
+	}
+	err := def.Merge(Definition{
+		Imports: map[string]string{"encoding/json": "json"},
+	})
+	is.NoErr(err)
+	is.Equal(def.Imports, map[string]string{"time": "time", "encoding/json": "json"})
+
+	def = Definition{}
+	err = def.Merge(Definition{
+		Imports: map[string]string{"time": "time"},
+	})
+	is.NoErr(err)
+	is.Equal(def.Imports, map[string]string{"time": "time"})
+}
+
+func TestMergeEnums(t *testing.T) {
+	is := is.New(t)
+	def := Definition{
+		Enums: []Enum{{Name: "Status", TypeID: "example.com/tasks.Status"}},
+	}
+	err := def.Merge(Definition{
+		Enums: []Enum{
+			{Name: "Status", TypeID: "example.com/tasks.Status"},
+			{Name: "Priority", TypeID: "example.com/tasks.Priority"},
+		},
+	})
+	is.NoErr(err)
+	is.Equal(len(def.Enums), 2) // shared enums are only included once
+	is.Equal(def.Enums[0].Name, "Priority")
+	is.Equal(def.Enums[1].Name, "Status")
+}
+
+func TestMergeConflicts(t *testing.T) {
+	is := is.New(t)
+	def := Definition{
+		Services: []Service{{Name: "UserService", Methods: []Method{{Name: "Create"}}}},
+	}
+	err := def.Merge(Definition{
+		Services: []Service{{Name: "UserService", Methods: []Method{{Name: "Delete"}}}},
+	})
+	is.True(err != nil)
+	is.Equal(err.Error(), "merge: duplicate service UserService with different methods")
+
+	err = def.Merge(Definition{
+		Services: []Service{{Name: "UserService", Methods: []Method{{Name: "Create"}}}},
+	})
+	is.True(err != nil)
+	is.Equal(err.Error(), "merge: duplicate method UserService.Create")
+	is.Equal(len(def.Services), 1) // def is unchanged after an error
+}
@@ -0,0 +1,408 @@
+package oto
+
+import (
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// GenerateOpenAPI generates an OpenAPI 3.0 specification from
+// the Definition.
+// The info.version is the Version of the Definition (or 1.0.0).
+// Services become tags, and each method is an operation (using its
+// HTTPMethod) at its Path. All Objects are added to the
+// components.schemas section.
+// The base spec (which may be nil) is merged into the output, its
+// values taking precedence over generated ones. Use it to provide
+// info, servers and security blocks.
+// Services and methods with an oto:auth comment line get security
+// requirements, and a matching components.securitySchemes entry.
+// The oto:scopes of a method are added to oauth2 requirements.
+func GenerateOpenAPI(def Definition, base map[string]interface{}) (map[string]interface{}, error) {
+	version := def.Version
+	if version == "" {
+		version = "1.0.0"
+	}
+	spec := map[string]interface{}{
+		"openapi": "3.0.3",
+		"info": map[string]interface{}{
+			"title":   def.PackageName,
+			"version": version,
+		},
+	}
+	tags := make([]interface{}, 0, len(def.Services))
+	paths := make(map[string]interface{})
+	securitySchemes := make(map[string]interface{})
+	for _, service := range def.Services {
+		tag := map[string]interface{}{
+			"name": service.Name,
+		}
+		if service.Comment != "" {
+			tag["description"] = service.Comment
+		}
+		tags = append(tags, tag)
+		for _, method := range service.Methods {
+			// methods without an output object respond with
+			// 204 No Content
+			responses := map[string]interface{}{
+				"204": map[string]interface{}{
+					"description": "No Content",
+				},
+			}
+			if method.HasOutput {
+				responses = map[string]interface{}{
+					"200": map[string]interface{}{
+						"description": "OK",
+						"content":     openAPIJSONContent(method.OutputObject),
+					},
+				}
+			}
+			operation := map[string]interface{}{
+				"tags":        []interface{}{service.Name},
+				"operationId": service.Name + "." + method.Name,
+				"responses":   responses,
+			}
+			parameters, hasBody := openAPIParameters(&def, method)
+			if len(parameters) > 0 {
+				operation["parameters"] = parameters
+			}
+			if hasBody {
+				content := openAPIJSONContent(method.InputObject)
+				if method.IsMultipart {
+					content = map[string]interface{}{
+						"multipart/form-data": content["application/json"],
+					}
+				}
+				operation["requestBody"] = map[string]interface{}{
+					"required": true,
+					"content":  content,
+				}
+			}
+			if method.Comment != "" {
+				operation["description"] = method.Comment
+			}
+			if method.Deprecated || service.Deprecated {
+				operation["deprecated"] = true
+			}
+			authValue := service.AuthScheme
+			if method.AuthOverride != "" {
+				authValue = method.AuthOverride
+			}
+			if authValue != "" {
+				auth, err := parseAuthScheme(authValue)
+				if err != nil {
+					return nil, errors.Wrapf(err, "%s.%s", service.Name, method.Name)
+				}
+				security := []interface{}{}
+				if auth.Scheme != "none" {
+					scopes := make([]interface{}, 0, len(auth.Scopes))
+					seen := make(map[string]struct{})
+					for _, scope := range auth.Scopes {
+						seen[scope] = struct{}{}
+						scopes = append(scopes, scope)
+					}
+					// OpenAPI 3.0 only allows scopes for oauth2
+					for _, scope := range method.Scopes {
+						if _, ok := seen[scope]; ok || auth.Scheme != "oauth2" {
+							continue
+						}
+						seen[scope] = struct{}{}
+						scopes = append(scopes, scope)
+					}
+					security = append(security, map[string]interface{}{
+						auth.key(): scopes,
+					})
+					securitySchemes[auth.key()] = auth.openAPISecurityScheme()
+				}
+				operation["security"] = security
+			}
+			path := openAPIPath(method.Path)
+			pathItem, ok := paths[path].(map[string]interface{})
+			if !ok {
+				pathItem = make(map[string]interface{})
+				paths[path] = pathItem
+			}
+			pathItem[strings.ToLower(method.HTTPMethod)] = operation
+		}
+	}
+	spec["tags"] = tags
+	spec["paths"] = paths
+	schemas := make(map[string]interface{})
+	for _, object := range def.Objects {
+		schemas[object.Name] = openAPIObjectSchema(object)
+	}
+	components := map[string]interface{}{
+		"schemas": schemas,
+	}
+	if len(securitySchemes) > 0 {
+		components["securitySchemes"] = securitySchemes
+	}
+	spec["components"] = components
+	mergeOpenAPI(spec, base)
+	return spec, nil
+}
+
+// openAPIParameters gets the parameter objects for the fields of the
+// input object that are not in the body (see Field.In), and for any
+// other path parameters.
+// hasBody is whether the request has a body, which is when the method
+// has an input object with any body fields (or no fields at all).
+func openAPIParameters(def *Definition, method Method) (parameters []interface{}, hasBody bool) {
+	var fields []Field
+	if input, err := def.Object(method.InputObject.ObjectName); err == nil && method.HasInput {
+		fields = input.Fields
+	}
+	hasBody = method.HasInput && len(fields) == 0
+	inPath := make(map[string]struct{})
+	for _, field := range fields {
+		switch field.In {
+		case "", "body":
+			hasBody = true
+			continue
+		case "path":
+			inPath[strings.ToLower(field.Name)] = struct{}{}
+		}
+		parameter := map[string]interface{}{
+			"name":   field.JSONName,
+			"in":     field.In,
+			"schema": openAPIFieldTypeSchema(field.Type),
+		}
+		if field.In == "path" {
+			// path parameters are always required
+			parameter["required"] = true
+			for _, param := range method.PathParams() {
+				if strings.EqualFold(param, field.Name) {
+					parameter["name"] = param
+				}
+			}
+		}
+		if field.Comment != "" {
+			parameter["description"] = field.Comment
+		}
+		parameters = append(parameters, parameter)
+	}
+	for _, param := range method.PathParams() {
+		if _, ok := inPath[strings.ToLower(param)]; ok {
+			continue
+		}
+		parameters = append(parameters, map[string]interface{}{
+			"name":     param,
+			"in":       "path",
+			"required": true,
+			"schema": map[string]interface{}{
+				"type": "string",
+			},
+		})
+	}
+	return parameters, hasBody
+}
+
+// openAPIPath converts :param segments in the path to {param}.
+func openAPIPath(path string) string {
+	segments := strings.Split(path, "/")
+	for i, segment := range segments {
+		if strings.HasPrefix(segment, ":") {
+			segments[i] = "{" + segment[1:] + "}"
+		}
+	}
+	return strings.Join(segments, "/")
+}
+
+func openAPIJSONContent(ftype FieldType) map[string]interface{} {
+	return map[string]interface{}{
+		"application/json": map[string]interface{}{
+			"schema": openAPIRef(ftype.ObjectName),
+		},
+	}
+}
+
+func openAPIRef(objectName string) map[string]interface{} {
+	return map[string]interface{}{
+		"$ref": "#/components/schemas/" + objectName,
+	}
+}
+
+func openAPIObjectSchema(object Object) map[string]interface{} {
+	properties := make(map[string]interface{})
+	var required []interface{}
+	for _, field := range object.Fields {
+		if field.Required {
+			required = append(required, field.JSONName)
+		}
+		schema := openAPIFieldTypeSchema(field.Type)
+		if _, isRef := schema["$ref"]; isRef {
+			// siblings of $ref are ignored
+			properties[field.JSONName] = schema
+			continue
+		}
+		if field.Comment != "" {
+			schema["description"] = field.Comment
+		}
+		if field.Example != nil {
+			schema["example"] = field.Example
+		}
+		if field.HasDefault {
+			schema["default"] = field.Default
+		}
+		if len(field.Enum) > 0 {
+			itemsSchema(schema, field.Type)["enum"] = field.Enum
+		}
+		if field.Deprecated {
+			schema["deprecated"] = true
+		}
+		if field.ReadOnly {
+			schema["readOnly"] = true
+		}
+		if field.WriteOnly {
+			schema["writeOnly"] = true
+		}
+		if field.Discriminator != "" {
+			oneOfSchema := schema
+			if items, ok := schema["items"].(map[string]interface{}); ok {
+				oneOfSchema = items
+			}
+			oneOfSchema["discriminator"] = map[string]interface{}{
+				"propertyName": field.Discriminator,
+			}
+		}
+		properties[field.JSONName] = schema
+	}
+	schema := map[string]interface{}{
+		"type":       "object",
+		"properties": properties,
+	}
+	if len(required) > 0 {
+		schema["required"] = required
+	}
+	if object.Comment != "" {
+		schema["description"] = object.Comment
+	}
+	if object.Example != nil {
+		schema["example"] = object.Example
+	}
+	if object.Deprecated {
+		schema["deprecated"] = true
+	}
+	return schema
+}
+
+func openAPIFieldTypeSchema(ftype FieldType) map[string]interface{} {
+	if isNestedSlice(ftype) {
+		schema := map[string]interface{}{
+			"type":  "array",
+			"items": openAPIFieldTypeSchema(*ftype.ElementType),
+		}
+		if ftype.Nullable {
+			schema["nullable"] = true
+		}
+		return schema
+	}
+	var schema map[string]interface{}
+	if len(ftype.OneOf) > 0 {
+		oneOf := make([]interface{}, 0, len(ftype.OneOf))
+		for _, name := range ftype.OneOf {
+			oneOf = append(oneOf, openAPIRef(name))
+		}
+		schema = map[string]interface{}{
+			"oneOf": oneOf,
+		}
+		if ftype.Nullable {
+			schema["nullable"] = true
+		}
+	} else if ftype.IsObject {
+		schema = openAPIRef(ftype.ObjectName)
+		if isNullableObject(ftype) {
+			// $ref cannot have siblings in OpenAPI 3.0
+			schema = map[string]interface{}{
+				"allOf":    []interface{}{schema},
+				"nullable": true,
+			}
+		}
+	} else if ftype.IsFile {
+		schema = map[string]interface{}{
+			"type":   "string",
+			"format": "binary",
+		}
+		if ftype.Nullable {
+			schema["nullable"] = true
+		}
+	} else {
+		schema = make(map[string]interface{})
+		typ, format := openAPIType(ftype)
+		if typ != "" {
+			schema["type"] = typ
+		}
+		if format != "" {
+			schema["format"] = format
+		}
+		if typ == "object" {
+			schema["additionalProperties"] = true
+		}
+		if ftype.Nullable {
+			schema["nullable"] = true
+		}
+	}
+	if ftype.Multiple {
+		schema = map[string]interface{}{
+			"type":  "array",
+			"items": schema,
+		}
+	}
+	return schema
+}
+
+// openAPIType gets the OpenAPI type and format for
+// the FieldType.
+func openAPIType(ftype FieldType) (string, string) {
+	switch ftype.JSType {
+	case "string", "boolean", "object":
+		return ftype.JSType, ftype.Format
+	case "number":
+		if ftype.Format != "" {
+			return "number", ftype.Format
+		}
+		switch ftype.TypeName {
+		case "float32":
+			return "number", "float"
+		case "float64":
+			return "number", "double"
+		case "int64", "uint64":
+			return "integer", "int64"
+		case "int32", "uint32":
+			return "integer", "int32"
+		}
+		if isIntegerTypeName(ftype.TypeName) {
+			return "integer", ""
+		}
+		return "number", ""
+	}
+	// any
+	return "", ftype.Format
+}
+
+// isIntegerTypeName gets whether the Go type name is one
+// of the built-in integer types.
+func isIntegerTypeName(typeName string) bool {
+	switch typeName {
+	case "int", "int8", "int16", "int32", "int64",
+		"uint", "uint8", "uint16", "uint32", "uint64",
+		"byte", "rune", "uintptr":
+		return true
+	}
+	return false
+}
+
+// mergeOpenAPI merges src into dst, values in src
+// taking precedence.
+// Maps are merged recursively.
+func mergeOpenAPI(dst, src map[string]interface{}) {
+	for key, srcValue := range src {
+		srcMap, srcIsMap := srcValue.(map[string]interface{})
+		dstMap, dstIsMap := dst[key].(map[string]interface{})
+		if srcIsMap && dstIsMap {
+			mergeOpenAPI(dstMap, srcMap)
+			continue
+		}
+		dst[key] = srcValue
+	}
+}
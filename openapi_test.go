@@ -0,0 +1,244 @@
+package oto
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/matryer/is"
+)
+
+func TestGenerateOpenAPI(t *testing.T) {
+	is := is.New(t)
+	parser := NewParser("./testdata/services/pleasantries")
+	parser.ExcludeInterfaces = []string{"Ignorer"}
+	def, err := parser.Parse()
+	is.NoErr(err)
+
+	base := map[string]interface{}{
+		"info": map[string]interface{}{
+			"version": "2.0.0",
+		},
+	}
+	spec, err := GenerateOpenAPI(def, base)
+	is.NoErr(err)
+	// round trip through JSON to make it easier to inspect
+	b, err := json.Marshal(spec)
+	is.NoErr(err)
+	var s struct {
+		OpenAPI string `json:"openapi"`
+		Info    struct {
+			Title   string `json:"title"`
+			Version string `json:"version"`
+		} `json:"info"`
+		Tags []struct {
+			Name        string `json:"name"`
+			Description string `json:"description"`
+		} `json:"tags"`
+		Paths      map[string]map[string]map[string]interface{} `json:"paths"`
+		Components struct {
+			Schemas map[string]struct {
+				Type       string                            `json:"type"`
+				Properties map[string]map[string]interface{} `json:"properties"`
+			} `json:"schemas"`
+		} `json:"components"`
+	}
+	is.NoErr(json.Unmarshal(b, &s))
+	is.Equal(s.OpenAPI, "3.0.3")
+	is.Equal(s.Info.Title, "pleasantries")
+	is.Equal(s.Info.Version, "2.0.0") // from base
+	is.Equal(len(s.Tags), 2)
+	is.Equal(s.Tags[0].Name, "GreeterService")
+	is.Equal(len(s.Paths), 3)
+	greet := s.Paths["/GreeterService/Greet"]["post"]
+	is.True(greet != nil)
+	is.Equal(greet["description"], "Greet creates a Greeting for one or more people.")
+	is.Equal(greet["requestBody"].(map[string]interface{})["content"].(map[string]interface{})["application/json"].(map[string]interface{})["schema"].(map[string]interface{})["$ref"], "#/components/schemas/GreetRequest")
+	is.Equal(len(s.Components.Schemas), len(def.Objects))
+	welcomeRequest := s.Components.Schemas["WelcomeRequest"]
+	is.Equal(welcomeRequest.Type, "object")
+	is.Equal(welcomeRequest.Properties["to"]["type"], "string")
+	is.Equal(welcomeRequest.Properties["to"]["example"], "your@email.com")
+	is.Equal(welcomeRequest.Properties["times"]["type"], "integer")
+	is.Equal(welcomeRequest.Properties["newCustomer"]["type"], "boolean")
+	greetRequest := s.Components.Schemas["GreetRequest"]
+	is.Equal(greetRequest.Properties["names"]["type"], "array")
+	getGreetingsResponse := s.Components.Schemas["GetGreetingsResponse"]
+	is.Equal(getGreetingsResponse.Properties["greetings"]["items"].(map[string]interface{})["$ref"], "#/components/schemas/Greeting")
+}
+
+func TestGenerateOpenAPIWithoutPayloads(t *testing.T) {
+	is := is.New(t)
+	def, err := NewParser("./testdata/services/payloads").Parse()
+	is.NoErr(err)
+
+	spec, err := GenerateOpenAPI(def, nil)
+	is.NoErr(err)
+	paths := spec["paths"].(map[string]interface{})
+	ping := paths["/HealthService/Ping"].(map[string]interface{})["post"].(map[string]interface{})
+	_, hasRequestBody := ping["requestBody"]
+	is.Equal(hasRequestBody, false)
+	is.Equal(ping["responses"], map[string]interface{}{
+		"204": map[string]interface{}{"description": "No Content"},
+	})
+	reset := paths["/HealthService/Reset"].(map[string]interface{})["post"].(map[string]interface{})
+	_, hasRequestBody = reset["requestBody"]
+	is.Equal(hasRequestBody, true)
+}
+
+func TestGenerateOpenAPIHTTPMethods(t *testing.T) {
+	is := is.New(t)
+	def, err := NewParser("./testdata/services/routes").Parse()
+	is.NoErr(err)
+
+	spec, err := GenerateOpenAPI(def, nil)
+	is.NoErr(err)
+	paths := spec["paths"].(map[string]interface{})
+	_, ok := paths["/UserService/Get"].(map[string]interface{})["get"]
+	is.True(ok)
+	_, ok = paths["/UserService/Create"].(map[string]interface{})["post"]
+	is.True(ok)
+	deleteOperation, ok := paths["/users/{id}"].(map[string]interface{})["delete"].(map[string]interface{})
+	is.True(ok)
+	is.Equal(deleteOperation["parameters"], []interface{}{
+		map[string]interface{}{
+			"name":     "id",
+			"in":       "path",
+			"required": true,
+			"schema":   map[string]interface{}{"type": "string"},
+		},
+	})
+	_, ok = paths["/users/{id}/{version}"].(map[string]interface{})["post"]
+	is.True(ok)
+}
+
+func TestGenerateOpenAPIParameters(t *testing.T) {
+	is := is.New(t)
+	def, err := NewParser("./testdata/services/locations").Parse()
+	is.NoErr(err)
+
+	spec, err := GenerateOpenAPI(def, nil)
+	is.NoErr(err)
+	path := spec["paths"].(map[string]interface{})["/orders/{orderID}"].(map[string]interface{})
+	get := path["get"].(map[string]interface{})
+	_, hasRequestBody := get["requestBody"]
+	is.Equal(hasRequestBody, false) // no body fields
+	is.Equal(get["parameters"], []interface{}{
+		map[string]interface{}{
+			"name":        "orderID",
+			"in":          "path",
+			"required":    true,
+			"schema":      map[string]interface{}{"type": "string"},
+			"description": "OrderID is the ID of the order.",
+		},
+		map[string]interface{}{
+			"name":        "token",
+			"in":          "header",
+			"schema":      map[string]interface{}{"type": "string"},
+			"description": "Token is the auth token.",
+		},
+		map[string]interface{}{
+			"name":        "expand",
+			"in":          "query",
+			"schema":      map[string]interface{}{"type": "boolean"},
+			"description": "Expand includes the items.",
+		},
+	})
+	update := path["post"].(map[string]interface{})
+	_, hasRequestBody = update["requestBody"]
+	is.Equal(hasRequestBody, true) // Status is in the body
+	is.Equal(len(update["parameters"].([]interface{})), 3)
+}
+
+func TestGenerateOpenAPIMultipart(t *testing.T) {
+	is := is.New(t)
+	def, err := NewParser("./testdata/services/files").Parse()
+	is.NoErr(err)
+
+	spec, err := GenerateOpenAPI(def, nil)
+	is.NoErr(err)
+	paths := spec["paths"].(map[string]interface{})
+	content := func(path string) map[string]interface{} {
+		operation := paths[path].(map[string]interface{})["post"].(map[string]interface{})
+		return operation["requestBody"].(map[string]interface{})["content"].(map[string]interface{})
+	}
+	_, ok := content("/FileService/Upload")["multipart/form-data"]
+	is.True(ok)
+	_, ok = content("/FileService/Rename")["application/json"]
+	is.True(ok)
+	schemas := spec["components"].(map[string]interface{})["schemas"].(map[string]interface{})
+	file := schemas["UploadRequest"].(map[string]interface{})["properties"].(map[string]interface{})["file"]
+	is.Equal(file, map[string]interface{}{"type": "string", "format": "binary", "nullable": true})
+}
+
+func TestGenerateOpenAPIDeprecated(t *testing.T) {
+	is := is.New(t)
+	def, err := NewParser("./testdata/services/deprecated").Parse()
+	is.NoErr(err)
+
+	spec, err := GenerateOpenAPI(def, nil)
+	is.NoErr(err)
+	paths := spec["paths"].(map[string]interface{})
+	deprecated := func(path string) interface{} {
+		return paths[path].(map[string]interface{})["post"].(map[string]interface{})["deprecated"]
+	}
+	is.Equal(deprecated("/AccountService/Find"), true)
+	is.Equal(deprecated("/AccountService/Get"), nil)
+	is.Equal(deprecated("/LegacyService/Get"), true) // the service is deprecated
+	schemas := spec["components"].(map[string]interface{})["schemas"].(map[string]interface{})
+	is.Equal(schemas["GetResponse"].(map[string]interface{})["deprecated"], true)
+	email := schemas["GetRequest"].(map[string]interface{})["properties"].(map[string]interface{})["email"]
+	is.Equal(email.(map[string]interface{})["deprecated"], true)
+}
+
+func TestGenerateOpenAPIOneOf(t *testing.T) {
+	is := is.New(t)
+	def, err := NewParser("./testdata/services/oneof").Parse()
+	is.NoErr(err)
+
+	spec, err := GenerateOpenAPI(def, nil)
+	is.NoErr(err)
+	schemas := spec["components"].(map[string]interface{})["schemas"].(map[string]interface{})
+	properties := schemas["PayRequest"].(map[string]interface{})["properties"].(map[string]interface{})
+	is.Equal(properties["method"].(map[string]interface{})["oneOf"], []interface{}{
+		map[string]interface{}{"$ref": "#/components/schemas/Card"},
+		map[string]interface{}{"$ref": "#/components/schemas/BankTransfer"},
+	})
+	is.Equal(properties["method"].(map[string]interface{})["discriminator"], map[string]interface{}{
+		"propertyName": "type",
+	})
+	items := properties["fallbacks"].(map[string]interface{})["items"].(map[string]interface{})
+	is.Equal(len(items["oneOf"].([]interface{})), 2)
+	is.Equal(items["discriminator"], nil)
+}
+
+func TestGenerateOpenAPIReadWriteOnly(t *testing.T) {
+	is := is.New(t)
+	def, err := NewParser("./testdata/services/readwrite").Parse()
+	is.NoErr(err)
+
+	spec, err := GenerateOpenAPI(def, nil)
+	is.NoErr(err)
+	schemas := spec["components"].(map[string]interface{})["schemas"].(map[string]interface{})
+	properties := schemas["Account"].(map[string]interface{})["properties"].(map[string]interface{})
+	is.Equal(properties["id"].(map[string]interface{})["readOnly"], true)
+	is.Equal(properties["createdAt"].(map[string]interface{})["readOnly"], true)
+	is.Equal(properties["password"].(map[string]interface{})["writeOnly"], true)
+	is.Equal(properties["email"].(map[string]interface{})["readOnly"], nil)
+	is.Equal(properties["email"].(map[string]interface{})["writeOnly"], nil)
+}
+
+func TestGenerateOpenAPINullableObjects(t *testing.T) {
+	is := is.New(t)
+	def, err := NewParser("./testdata/services/nullable").Parse()
+	is.NoErr(err)
+	spec, err := GenerateOpenAPI(def, nil)
+	is.NoErr(err)
+	schemas := spec["components"].(map[string]interface{})["schemas"].(map[string]interface{})
+	properties := schemas["UpdateRequest"].(map[string]interface{})["properties"].(map[string]interface{})
+	address := properties["address"].(map[string]interface{})
+	is.Equal(address["allOf"], []interface{}{
+		map[string]interface{}{"$ref": "#/components/schemas/Address"},
+	})
+	is.Equal(address["nullable"], true)
+	is.Equal(address["description"], "Address is an optional address.")
+}
@@ -0,0 +1,56 @@
+package oto
+
+import (
+	"go/token"
+	"strings"
+)
+
+// ParseError is an error at a position in the source, like an
+// invalid method signature or comment line.
+type ParseError struct {
+	// File, Line and Col are the position of the error.
+	File string `json:"file"`
+	Line int    `json:"line"`
+	Col  int    `json:"col"`
+	// Msg describes the error, without the position.
+	Msg string `json:"msg"`
+	// Cause is the underlying error.
+	Cause error `json:"-"`
+}
+
+// Error gets the position and message, like
+// "file.go:12:2: Name must be exported".
+// Errors without a position are just the message.
+func (e *ParseError) Error() string {
+	position := token.Position{
+		Filename: e.File,
+		Line:     e.Line,
+		Column:   e.Col,
+	}
+	if !position.IsValid() {
+		return e.Msg
+	}
+	return position.String() + ": " + e.Msg
+}
+
+// Unwrap gets the Cause, for errors.Is and errors.As.
+func (e *ParseError) Unwrap() error {
+	return e.Cause
+}
+
+// ParseErrorList is a list of ParseErrors.
+type ParseErrorList []*ParseError
+
+// Error gets the errors, one per line.
+func (l ParseErrorList) Error() string {
+	messages := make([]string, 0, len(l))
+	for _, err := range l {
+		messages = append(messages, err.Error())
+	}
+	return strings.Join(messages, "\n")
+}
+
+// Errors gets the ParseErrors in the list.
+func (l ParseErrorList) Errors() []*ParseError {
+	return l
+}
@@ -0,0 +1,34 @@
+package oto
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/matryer/is"
+)
+
+func TestParseError(t *testing.T) {
+	is := is.New(t)
+
+	_, err := NewParser("./testdata/services/errors/variadic").Parse()
+	is.True(err != nil)
+	var parseErr *ParseError
+	is.True(errors.As(err, &parseErr))
+	is.True(strings.HasSuffix(parseErr.File, "variadic.go"))
+	is.True(parseErr.Line > 0)
+	is.True(parseErr.Col > 0)
+	is.True(strings.HasPrefix(parseErr.Msg, "SearchService.Search: variadic parameters are not supported"))
+	is.True(strings.HasSuffix(err.Error(), parseErr.Error()))
+
+	err = &ParseError{File: "a.go", Line: 3, Col: 2, Msg: "bad", Cause: errors.New("bad")}
+	is.Equal(err.Error(), "a.go:3:2: bad")
+	is.Equal(errors.Unwrap(err).Error(), "bad")
+
+	list := ParseErrorList{
+		{File: "a.go", Line: 3, Col: 2, Msg: "bad"},
+		{File: "b.go", Line: 1, Msg: "worse"},
+	}
+	is.Equal(list.Error(), "a.go:3:2: bad\nb.go:1: worse")
+	is.Equal(len(list.Errors()), 2)
+}
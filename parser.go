@@ -1,15 +1,25 @@
-package main
+package oto
 
 import (
 	"bufio"
 	"encoding/json"
 	"fmt"
 	"go/ast"
+	"go/constant"
 	"go/doc"
 	"go/token"
 	"go/types"
+	"os"
+	"path"
+	"path/filepath"
+	"reflect"
+	"regexp"
 	"sort"
+	"strconv"
 	"strings"
+	"time"
+	"unicode"
+	"unicode/utf8"
 
 	"github.com/fatih/structtag"
 	"github.com/pkg/errors"
@@ -21,14 +31,28 @@ var errNotFound = errors.New("not found")
 // Definition describes an Oto definition.
 type Definition struct {
 	// PackageName is the name of the package.
+	// When more than one package is parsed, it is the name of
+	// the first one.
 	PackageName string `json:"packageName"`
+	// Version is the version of the API, from the oto:version line
+	// in the package comment (like "oto:version 1.2.3"), or else a
+	// Version string constant in the package. When more than one
+	// package is parsed, the first version found is used. The
+	// Parser's VersionOverride takes precedence.
+	Version string `json:"version"`
 	// Services are the services described in this definition.
 	Services []Service `json:"services"`
 	// Objects are the structures that are used throughout this definition.
 	Objects []Object `json:"objects"`
+	// Enums are the named primitive types (like type Status string)
+	// used by the Objects that have constant values.
+	Enums []Enum `json:"enums"`
 	// Imports is a map of Go imports that should be imported into
 	// Go code.
 	Imports map[string]string `json:"imports"`
+	// Warnings describe problems that were found while parsing,
+	// that did not stop the Definition from being created.
+	Warnings []string `json:"warnings,omitempty"`
 }
 
 // Object looks up an object by name. Returns errNotFound error
@@ -43,41 +67,348 @@ func (d *Definition) Object(name string) (*Object, error) {
 	return nil, errNotFound
 }
 
+// FieldRef is a Field of an Object.
+type FieldRef struct {
+	ObjectName string `json:"objectName"`
+	Field      Field  `json:"field"`
+}
+
+// SensitiveFields gets the Sensitive fields of all of the Objects,
+// for templates that redact them (like logging middleware).
+func (d *Definition) SensitiveFields() []FieldRef {
+	var refs []FieldRef
+	for _, object := range d.Objects {
+		for _, field := range object.Fields {
+			if field.Sensitive {
+				refs = append(refs, FieldRef{ObjectName: object.Name, Field: field})
+			}
+		}
+	}
+	return refs
+}
+
+// Enum looks up an enum by name. Returns errNotFound error
+// if it cannot find it.
+func (d *Definition) Enum(name string) (*Enum, error) {
+	for i := range d.Enums {
+		enum := &d.Enums[i]
+		if enum.Name == name {
+			return enum, nil
+		}
+	}
+	return nil, errNotFound
+}
+
 // Service describes a service, akin to an interface in Go.
 type Service struct {
 	Name    string   `json:"name"`
 	Methods []Method `json:"methods"`
 	Comment string   `json:"comment"`
+	// GoName is the name of the interface in Go, which is the Name
+	// unless the service has an oto:rename comment line, like
+	// `oto:rename "Accounts"`.
+	GoName string `json:"goName"`
+	// PackageName and PackagePath are the name and import path
+	// of the package that declares the service. Service names
+	// must be unique across all packages.
+	PackageName string `json:"packageName"`
+	PackagePath string `json:"packagePath"`
+	// Embeds are the names of the interfaces embedded in this
+	// service. Their methods are included in Methods.
+	// Standard library interfaces (like fmt.Stringer) are ignored.
+	Embeds []string `json:"embeds,omitempty"`
+	// AuthScheme is the authentication the service requires,
+	// from the oto:auth comment line, like "bearer",
+	// "apikey header X-API-Key" or "oauth2 scopes:read,write".
+	AuthScheme string `json:"authScheme"`
+	// RoutePrefix is the path that the Path of every method
+	// starts with, from the oto:prefix comment line, like
+	// "/api/v2/accounts".
+	RoutePrefix string `json:"routePrefix"`
+	// Version is the version of the service, from the oto:version
+	// comment line, like "oto:version 2". It is empty if there is
+	// no oto:version line. It does not change the paths of the
+	// methods (use an oto:prefix line for that).
+	Version string `json:"version"`
+	// Scopes are the scopes the methods of the service require
+	// by default, from the oto:scopes comment line, like
+	// "oto:scopes admin,billing:write".
+	Scopes []string `json:"scopes"`
+	// DefaultTimeout is the Timeout of methods that do not
+	// have their own, from the oto:timeout comment line.
+	DefaultTimeout string `json:"defaultTimeout"`
+	// DefaultRateLimit is the RateLimit of methods that do not
+	// have their own, from the oto:rate-limit comment line.
+	DefaultRateLimit *RateLimit `json:"defaultRateLimit"`
+	// Deprecated is true if the comment has a "Deprecated: "
+	// paragraph, and DeprecationMessage is the rest of it.
+	Deprecated         bool   `json:"deprecated"`
+	DeprecationMessage string `json:"deprecationMessage"`
 }
 
 // Method describes a method that a Service can perform.
 type Method struct {
-	Name           string    `json:"name"`
-	NameLowerCamel string    `json:"nameLowerCamel"`
-	InputObject    FieldType `json:"inputObject"`
-	OutputObject   FieldType `json:"outputObject"`
-	Comment        string    `json:"comment"`
+	Name           string `json:"name"`
+	NameLowerCamel string `json:"nameLowerCamel"`
+	// NameSnake and NameKebab are the Name in snake_case and
+	// kebab-case, like "get_greetings" and "get-greetings".
+	NameSnake    string    `json:"nameSnake"`
+	NameKebab    string    `json:"nameKebab"`
+	InputObject  FieldType `json:"inputObject"`
+	OutputObject FieldType `json:"outputObject"`
+	Comment      string    `json:"comment"`
+	// GoName is the name of the method in Go, which is the Name
+	// unless the method has an oto:rename comment line.
+	GoName string `json:"goName"`
+	// GraphQLOperation is "query" or "mutation" if the method has
+	// the oto:graphql-query or oto:graphql-mutation comment
+	// directive, otherwise it is empty.
+	GraphQLOperation string `json:"graphQLOperation"`
+	// HasContext is true if the method takes a context.Context
+	// as its first parameter.
+	HasContext bool `json:"hasContext"`
+	// ReturnsError is true if the method returns an error
+	// after the response object.
+	ReturnsError bool `json:"returnsError"`
+	// HasInput is false if the method takes no request object,
+	// in which case InputObject is empty.
+	HasInput bool `json:"hasInput"`
+	// HasOutput is false if the method returns no response
+	// object, in which case OutputObject is empty.
+	HasOutput bool `json:"hasOutput"`
+	// AuthOverride is the authentication the method requires
+	// (from its oto:auth comment line) instead of the
+	// AuthScheme of the Service. It is "none" for methods
+	// that need no authentication.
+	AuthOverride string `json:"authOverride"`
+	// Scopes are the scopes the method requires, from its
+	// oto:scopes comment line. Methods without one get the
+	// Scopes of the Service (method scopes replace them, they
+	// are not added to them).
+	Scopes []string `json:"scopes"`
+	// Timeout is how long clients should wait for the method,
+	// like "30s" or "5m", from the oto:timeout comment line (or
+	// the DefaultTimeout of the Service). It is empty if there
+	// is no timeout.
+	Timeout string `json:"timeout"`
+	// RateLimit is how often the method may be called, from
+	// the oto:rate-limit comment line (or the DefaultRateLimit
+	// of the Service), like "oto:rate-limit 100 per minute".
+	// It is nil if there is no limit.
+	RateLimit *RateLimit `json:"rateLimit"`
+	// HTTPMethod is the HTTP verb for the method, from the
+	// oto:method comment line (default: POST).
+	HTTPMethod string `json:"httpMethod"`
+	// HTTPPath is the path from the oto:route comment line,
+	// like "/users/:id", or empty if there isn't one.
+	HTTPPath string `json:"httpPath"`
+	// Path is the path of the method, from the oto:path (or
+	// oto:route) comment line, like "/users/{id}", after the
+	// RoutePrefix of the Service.
+	// The default is "/ServiceName/MethodName".
+	Path string `json:"path"`
+	// Stream is "server", "client" or "bidi" if the method
+	// streams its OutputObjects, InputObjects or both, from the
+	// oto:stream comment line ("oto:stream" alone is "server").
+	// It is empty for methods that do not stream.
+	Stream string `json:"stream"`
+	// IsMultipart is true if any field of the InputObject is a
+	// file (see FieldType.IsFile), so the request has to be sent
+	// as multipart/form-data.
+	IsMultipart bool `json:"isMultipart"`
+	// Streaming is true if the response is a stream (like
+	// server-sent events), with an OutputObject for each event.
+	// It comes from the oto:streaming (or oto:stream) comment
+	// line. Streamed OutputObjects only get the Error field if
+	// the method also has the oto:streaming-error line.
+	Streaming bool `json:"streaming"`
+	// Index is the position (from zero) of the method in the
+	// declaration of the interface, which is not always its
+	// position in Methods. Reordering the declarations changes it.
+	// Methods from embedded interfaces are ordered by where they
+	// are declared.
+	Index int `json:"index"`
+	// DeclaredIn is the name of the interface that declares the
+	// method, which is the name of the Service unless the method
+	// comes from an embedded interface (see Service.Embeds).
+	// Methods from interfaces embedded in embedded interfaces
+	// have the name of the innermost one.
+	DeclaredIn string `json:"declaredIn"`
+	// Deprecated is true if the comment has a "Deprecated: "
+	// paragraph, and DeprecationMessage is the rest of it.
+	Deprecated         bool   `json:"deprecated"`
+	DeprecationMessage string `json:"deprecationMessage"`
+}
+
+// RateLimit is the number of Requests allowed Per period.
+type RateLimit struct {
+	Requests int `json:"requests"`
+	// Per is second, minute, hour or day.
+	Per string `json:"per"`
+}
+
+// PathParams gets the names of the parameters in the Path,
+// like "id" for "/users/{id}" (or "/users/:id").
+func (m *Method) PathParams() []string {
+	var params []string
+	for _, segment := range strings.Split(m.Path, "/") {
+		switch {
+		case strings.HasPrefix(segment, "{") && strings.HasSuffix(segment, "}"):
+			params = append(params, segment[1:len(segment)-1])
+		case strings.HasPrefix(segment, ":"):
+			params = append(params, segment[1:])
+		}
+	}
+	return params
 }
 
 // Object describes a data structure that is part of this definition.
 type Object struct {
-	TypeID   string  `json:"typeID"`
-	Name     string  `json:"name"`
-	Imported bool    `json:"imported"`
-	Fields   []Field `json:"fields"`
-	Comment  string  `json:"comment"`
+	TypeID string `json:"typeID"`
+	Name   string `json:"name"`
+	// NameSnake and NameKebab are the Name in snake_case and
+	// kebab-case, like "greet_request" and "greet-request".
+	NameSnake string  `json:"nameSnake"`
+	NameKebab string  `json:"nameKebab"`
+	Imported  bool    `json:"imported"`
+	Fields    []Field `json:"fields"`
+	Comment   string  `json:"comment"`
+	// GoName is the name of the struct in Go, which is the Name
+	// unless the object has an oto:rename comment line. The TypeID
+	// always uses the GoName.
+	GoName string `json:"goName"`
+	// Example is a whole example of the object, from an example:
+	// line (a JSON object) in its comment. It may show how fields
+	// are used together, and is nil if there is no such line.
+	// Keys that are not the JSONName of a field are warnings.
+	Example map[string]interface{} `json:"example,omitempty"`
+	// Used is true if the object has the oto:used comment line,
+	// which stops it being reported as unreferenced when no
+	// service uses it.
+	Used bool `json:"used"`
+	// IsInput and IsOutput are true if the object is the input or
+	// output object of any method. Objects that are only used by
+	// the fields of other objects are neither.
+	IsInput  bool `json:"isInput"`
+	IsOutput bool `json:"isOutput"`
+	// Circular is true if this object is part of a reference
+	// cycle, like a struct with a field of its own type, or two
+	// structs that refer to each other.
+	Circular bool `json:"circular"`
+	// Deprecated is true if the comment has a "Deprecated: "
+	// paragraph, and DeprecationMessage is the rest of it.
+	Deprecated         bool   `json:"deprecated"`
+	DeprecationMessage string `json:"deprecationMessage"`
+}
+
+// Enum describes a named primitive type with constant values,
+// like:
+//
+//	type Status string
+//
+//	const (
+//		Active   Status = "active"
+//		Inactive Status = "inactive"
+//	)
+type Enum struct {
+	Name    string `json:"name"`
+	TypeID  string `json:"typeID"`
+	JSType  string `json:"jsType"`
+	Comment string `json:"comment"`
+	// Values are the constants of the type, in the order they
+	// are declared.
+	Values []EnumValue `json:"values"`
+}
+
+// EnumValue is one of the constant values of an Enum.
+type EnumValue struct {
+	Name string `json:"name"`
+	// Value is a string, int64, float64 or bool.
+	Value   interface{} `json:"value"`
+	Comment string      `json:"comment"`
 }
 
 // Field describes the field inside an Object.
+// The Fields of an Object are in the order they are declared in the
+// struct (unless the Parser sorts them with SortFields), which is
+// the same every time, and Index is always the declared position,
+// so templates can rely on it for positional formats, like CSV
+// columns or proto field numbers.
 type Field struct {
-	Name           string              `json:"name"`
-	NameLowerCamel string              `json:"nameLowerCamel"`
-	Type           FieldType           `json:"type"`
-	OmitEmpty      bool                `json:"omitEmpty"`
-	Comment        string              `json:"comment"`
-	Tag            string              `json:"tag"`
-	ParsedTags     map[string]FieldTag `json:"parsedTags"`
-	Example        interface{}         `json:"example"`
+	Name           string `json:"name"`
+	NameLowerCamel string `json:"nameLowerCamel"`
+	// NameSnake and NameKebab are the Name in snake_case and
+	// kebab-case. Acronyms are kept together, so UserID is
+	// "user_id" and "user-id".
+	NameSnake  string              `json:"nameSnake"`
+	NameKebab  string              `json:"nameKebab"`
+	Type       FieldType           `json:"type"`
+	OmitEmpty  bool                `json:"omitEmpty"`
+	Comment    string              `json:"comment"`
+	Tag        string              `json:"tag"`
+	ParsedTags map[string]FieldTag `json:"parsedTags"`
+	Example    interface{}         `json:"example"`
+	// JSONName is the name of the field on the wire, from the json
+	// tag (like json:"user_id,omitempty"), or NameLowerCamel if the
+	// tag does not set one.
+	JSONName string `json:"jsonName"`
+	// Default is the value the field has if it is not set, from
+	// the default: (or oto:default) comment line, in JSON, like
+	// "default: 42" or `default: "active"`. It must match the type
+	// of the field. HasDefault is true if the field has a default,
+	// since it may be null.
+	Default    interface{} `json:"default"`
+	HasDefault bool        `json:"hasDefault"`
+	// Required is whether the field must be set. It comes from
+	// (in order of precedence) a "required: true" (or false)
+	// comment line, a required validator in the validate or
+	// binding tag, or otherwise the field is required unless it
+	// is omitempty or Nullable. The Error field added to output
+	// objects is never required, and fields for path parameters
+	// always are.
+	Required bool `json:"required"`
+	// Sensitive is true if the field holds personal or secret
+	// data (like passwords) that should be redacted from logs,
+	// from the oto:sensitive comment line or oto:"sensitive" tag.
+	Sensitive bool `json:"sensitive"`
+	// ReadOnly fields are only in responses (like IDs set by the
+	// server), and WriteOnly fields are only in requests (like
+	// passwords). They come from the oto:readonly and
+	// oto:writeonly comment lines, or the oto:"readonly" and
+	// oto:"writeonly" tags. A field cannot be both.
+	ReadOnly  bool `json:"readOnly"`
+	WriteOnly bool `json:"writeOnly"`
+	// Enum are the only values the field may have, from an enum:
+	// comment line with a JSON array, like `enum: ["asc", "desc"]`
+	// (the brackets are optional). For fields that are slices, they
+	// are the values of the items. Named types with constant values
+	// are Enums of the Definition instead (see FieldType.EnumName).
+	Enum []interface{} `json:"enum,omitempty"`
+	// Constraints describe the values the field may have, from
+	// the validate tag. It is nil if there are none.
+	Constraints *FieldConstraints `json:"constraints"`
+	// In is where the field goes in an HTTP request: body, query,
+	// header, path or cookie. It comes from the oto:in comment
+	// line or the oto:"in=query" tag. Fields of the input objects
+	// of GET methods are in the query by default, and fields for
+	// path parameters are in the path.
+	In string `json:"in"`
+	// Index is the position (from zero) of the field in the
+	// declaration of the struct (or the parameters of the method
+	// for synthesized request objects), before any -sort-fields.
+	// Fields that are left out (like json:"-" ones) are not
+	// counted, so the indexes of an Object are 0 to len(Fields)-1.
+	// Reordering the declarations changes it. The Error field
+	// added to output objects comes last.
+	Index int `json:"index"`
+	// Discriminator is the name of the property that says which
+	// of the Type.OneOf objects the value is, from the
+	// oto:discriminator comment line, like "oto:discriminator type".
+	Discriminator string `json:"discriminator,omitempty"`
+	// Deprecated is true if the comment has a "Deprecated: "
+	// paragraph, and DeprecationMessage is the rest of it.
+	Deprecated         bool   `json:"deprecated"`
+	DeprecationMessage string `json:"deprecationMessage"`
 }
 
 // FieldTag is a parsed tag.
@@ -96,17 +427,252 @@ type FieldType struct {
 	TypeName             string `json:"typeName"`
 	ObjectName           string `json:"objectName"`
 	ObjectNameLowerCamel string `json:"objectNameLowerCamel"`
-	Multiple             bool   `json:"multiple"`
-	Package              string `json:"package"`
-	IsObject             bool   `json:"isObject"`
-	JSType               string `json:"jsType"`
+	// ObjectNameSnake and ObjectNameKebab are the ObjectName in
+	// snake_case and kebab-case.
+	ObjectNameSnake string `json:"objectNameSnake"`
+	ObjectNameKebab string `json:"objectNameKebab"`
+	// Multiple is true if this is a slice. The other fields
+	// describe the slice element, except for JSType (see below).
+	Multiple bool   `json:"multiple"`
+	Package  string `json:"package"`
+	IsObject bool   `json:"isObject"`
+	// JSType is the JavaScript type of the value, or of each element
+	// for slices (so []string is "string" with Multiple true, and
+	// [][]string is "string" too, with an ElementType that is also
+	// Multiple).
+	// []byte is not a slice (see IsBytes).
+	JSType string `json:"jsType"`
+	// ElementType describes the elements of the slice when Multiple
+	// is true, otherwise it is nil.
+	ElementType *FieldType `json:"elementType"`
+	// Nullable is true if the field may be null, either because it
+	// is a pointer, or because it has the oto:nullable comment
+	// directive. For pointers, the other fields describe the type
+	// pointed to, so *[]User and []*User are both Multiple objects
+	// (for []*User, it is the ElementType that is Nullable).
+	Nullable bool `json:"nullable"`
+	// JSTypeUnknown is true if oto could not work out a JSType
+	// for this type, and fell back to "any".
+	// It is false for interface{}, which is deliberately "any".
+	JSTypeUnknown bool `json:"jsTypeUnknown"`
+	// UnderlyingTypeName is the name of the primitive type that a
+	// named type (like type UserID string) is defined as, like
+	// "string". It is empty for other types.
+	UnderlyingTypeName string `json:"underlyingTypeName"`
+	// IsEnum is true if the type is a named primitive type (like
+	// type Status string) with constant values, and EnumName is
+	// the name of the Enum (see Definition.Enums), or empty for
+	// other types. Use def.Enum(EnumName) to get the values.
+	IsEnum   bool   `json:"isEnum"`
+	EnumName string `json:"enumName"`
+	// CustomMarshaler is true if the type has its own MarshalJSON
+	// or MarshalText method. These types are not parsed as Objects,
+	// and the JSType is a best guess (MarshalText types are strings)
+	// unless the type has an oto:jstype comment line.
+	CustomMarshaler bool `json:"customMarshaler"`
+	// Format is a hint about the format of the value,
+	// like "date-time" or "uuid". It comes from the TypeOverrides,
+	// or the oto:format (or format:) comment line (like "oto:format
+	// email") or oto:"format=email" tag of the field, with the tag
+	// taking precedence.
+	Format string `json:"format"`
+	// IsMap is true if this is a map type. MapKeyType and
+	// MapValueType describe the keys and values in the map.
+	// Slices and maps can be nested, so templates can walk
+	// ElementType, MapKeyType and MapValueType to any depth.
+	IsMap        bool       `json:"isMap"`
+	MapKeyType   *FieldType `json:"mapKeyType"`
+	MapValueType *FieldType `json:"mapValueType"`
+	// IsFile is true for file uploads: *multipart.FileHeader and
+	// io.Reader types, or fields with the oto:file comment line.
+	IsFile bool `json:"isFile"`
+	// IsBytes is true for []byte (and []uint8), which is a binary
+	// value encoded as a base64 string, not a slice of numbers.
+	// Multiple is false, the TypeName is "[]byte", the JSType is
+	// "string" and the Format is "byte".
+	IsBytes bool `json:"isBytes"`
+	// OneOf are the names of the Objects that the value may be one
+	// of, from the oto:oneof comment line of the field, like
+	// "oto:oneof Card,BankTransfer". The Go type is usually
+	// interface{} or json.RawMessage.
+	OneOf []string `json:"oneOf,omitempty"`
+}
+
+// TypeOverride describes how a named type that should be treated
+// as a single value (rather than an Object) is represented.
+type TypeOverride struct {
+	// TypeName overrides the Go type name, if set.
+	TypeName string
+	// JSType is the JavaScript type.
+	JSType string
+	// Format is an optional hint about the format of the value.
+	Format string
+	// Nullable is whether the value may be null.
+	Nullable bool
+}
+
+// StrictChecks are the checks that are made when the parser is
+// in Strict mode. Each one turns something that is usually
+// tolerated into an error.
+type StrictChecks struct {
+	// UnknownJSTypes fails if oto cannot work out the JSType
+	// of a field.
+	UnknownJSTypes bool
+	// MethodComments fails if a method has no comment.
+	MethodComments bool
+	// FieldExamples fails if a field has no example: line in
+	// its comment.
+	FieldExamples bool
+	// MissingObjects fails if the input or output of a method
+	// is not an Object in the Definition.
+	MissingObjects bool
+	// UnusedObjects fails if an Object is not used by any service
+	// (see findUnreferencedObjects).
+	UnusedObjects bool
+}
+
+// AllStrictChecks gets StrictChecks with every check turned on.
+func AllStrictChecks() StrictChecks {
+	return StrictChecks{
+		UnknownJSTypes: true,
+		MethodComments: true,
+		FieldExamples:  true,
+		MissingObjects: true,
+		UnusedObjects:  true,
+	}
+}
+
+// defaultTypeOverrides gets the well-known types that are treated
+// as scalars, keyed by TypeID.
+// These types have custom JSON encoding, so their fields do not
+// describe what goes over the wire.
+func defaultTypeOverrides() map[string]TypeOverride {
+	scalarTypes := map[string]TypeOverride{
+		"time.Time":                                           {JSType: "string", Format: "date-time"},
+		"time.Duration":                                       {JSType: "string", Format: "duration"},
+		"encoding/json.RawMessage":                            {JSType: "any"},
+		"encoding/json/jsontext.Value":                        {JSType: "any"},
+		"encoding/json.Number":                                {JSType: "number"},
+		"math/big.Int":                                        {JSType: "number"},
+		"github.com/google/uuid.UUID":                         {JSType: "string", Format: "uuid", TypeName: "string"},
+		"github.com/gofrs/uuid.UUID":                          {JSType: "string", Format: "uuid", TypeName: "string"},
+		"github.com/satori/go.uuid.UUID":                      {JSType: "string", Format: "uuid", TypeName: "string"},
+		"go.mongodb.org/mongo-driver/bson/primitive.ObjectID": {JSType: "string"},
+		"github.com/shopspring/decimal.Decimal":               {JSType: "string"},
+	}
+	for typeID, scalarType := range SQLNullTypeOverrides() {
+		scalarTypes[typeID] = scalarType
+	}
+	return scalarTypes
+}
+
+// SQLNullTypeOverrides gets the database/sql Null* types, which
+// are treated as nullable versions of the value they hold.
+func SQLNullTypeOverrides() map[string]TypeOverride {
+	return map[string]TypeOverride{
+		"database/sql.NullString":  {JSType: "string", Nullable: true},
+		"database/sql.NullBool":    {JSType: "boolean", Nullable: true},
+		"database/sql.NullByte":    {JSType: "number", Nullable: true},
+		"database/sql.NullInt16":   {JSType: "number", Nullable: true},
+		"database/sql.NullInt32":   {JSType: "number", Nullable: true},
+		"database/sql.NullInt64":   {JSType: "number", Nullable: true},
+		"database/sql.NullFloat64": {JSType: "number", Nullable: true},
+		"database/sql.NullTime":    {JSType: "string", Format: "date-time", Nullable: true},
+	}
 }
 
-type parser struct {
+// Parser parses Go packages into a Definition.
+// Use NewParser to make one, set the options and call Parse.
+type Parser struct {
+	// Verbose writes progress to stdout.
 	Verbose bool
 
+	// ExcludeInterfaces are the names of interfaces that
+	// will not become services. They may be glob patterns (see
+	// path.Match), like "Internal*".
 	ExcludeInterfaces []string
 
+	// MatchInterfaces (if set) is the pattern that the names of
+	// interfaces must match to become services, like "Service$".
+	// The objects that other interfaces use are left out.
+	MatchInterfaces *regexp.Regexp
+
+	// IncludeInterfaces (if not empty) are the names of the only
+	// interfaces that will become services.
+	// The objects that other interfaces use are left out.
+	IncludeInterfaces []string
+
+	// ExcludePackages are the import paths of packages that
+	// will be skipped.
+	ExcludePackages []string
+
+	// AddErrorField adds the Error field to output objects.
+	AddErrorField bool
+
+	// BuildTags are the build tags used when loading packages.
+	BuildTags []string
+
+	// Strict makes the StrictChecks errors, and stops errors
+	// in objects not used by any service from being ignored.
+	Strict bool
+	// StrictChecks are the checks made in Strict mode
+	// (default: all of them).
+	StrictChecks StrictChecks
+
+	// FailOnUnused makes objects that are not used by any service
+	// an error, instead of a warning.
+	FailOnUnused bool
+
+	// TypeOverrides are named types (keyed by TypeID) that are
+	// treated as single values instead of being parsed as Objects.
+	TypeOverrides map[string]TypeOverride
+
+	// Int64AsString marks all int64 and uint64 types with the
+	// "string" JSType, since JavaScript numbers cannot hold them.
+	Int64AsString bool
+
+	// SynthesizeRequests allows methods with more than one
+	// parameter, like Get(id string, deleted bool), and makes a
+	// request object (GetRequest) with a field for each one.
+	SynthesizeRequests bool
+
+	// MaxRecursionDepth is how deeply objects may be nested inside
+	// each other before parsing fails, which stops deeply nested
+	// (but not circular) types from overflowing the stack.
+	// Zero means no limit.
+	MaxRecursionDepth int
+
+	// SkipUnexportedFields leaves unexported struct fields out of
+	// Objects, like encoding/json does, instead of failing.
+	SkipUnexportedFields bool
+
+	// ValidateExamples checks that the examples of fields match
+	// their types, like a number for an int field.
+	ValidateExamples bool
+
+	// VersionOverride (if set) is the Version of the Definition,
+	// instead of the one in the source.
+	VersionOverride string
+
+	// SortFields sorts the Fields of each Object by name, instead
+	// of keeping the order they are declared in.
+	SortFields bool
+	// SortObjects sorts the Objects by name, instead of keeping
+	// the order they are found in.
+	SortObjects bool
+	// PreserveOrder keeps Services, their Methods and Objects in
+	// the order they are declared in the source, instead of sorting
+	// Services by name, and Methods and Objects in the order go/types
+	// and parsing finds them. SortObjects takes precedence for
+	// Objects.
+	PreserveOrder bool
+
+	// RecoverOnError keeps parsing after an error in a service,
+	// object or field, leaving it out of the Definition. Parse
+	// returns the rest of the Definition, and a ParseErrorList of
+	// the errors.
+	RecoverOnError bool
+
 	patterns []string
 	def      Definition
 
@@ -114,263 +680,2098 @@ type parser struct {
 	outputObjects map[string]struct{}
 	// objects marks object names.
 	objects map[string]struct{}
+	// parsingObjects is the stack of object names currently
+	// being parsed, used to detect cycles.
+	parsingObjects []string
+	// circularObjects marks the names of objects that are part
+	// of a reference cycle.
+	circularObjects map[string]struct{}
+	// renamedObjects are the names of objects with the oto:rename
+	// comment line, keyed by TypeID.
+	renamedObjects map[string]string
+	// ignoredTypeIDs are the TypeIDs of the parameters and
+	// results of methods with the oto:ignore comment line, and of
+	// interfaces that are excluded, not included or have the
+	// oto:skip comment line (see removeIgnoredObjects).
+	ignoredTypeIDs []string
+
+	// positions are the source positions of the services, methods,
+	// objects and fields, keyed by "service:Name",
+	// "method:Service.Method", "object:TypeID" and
+	// "field:TypeID.Field" (see sortBySourceOrder and lintPosition).
+	positions map[string]token.Position
+
+	// loadedPackages are all of the loaded packages (including
+	// dependencies), keyed by package path.
+	loadedPackages map[string]*packages.Package
+	// docs are the docs for extracting comments, keyed by
+	// package path (see packageDocs).
+	docs map[string]*doc.Package
 
-	// docs are the docs for extracting comments.
-	docs *doc.Package
+	// dirs are the directories of the parsed packages.
+	dirs []string
+
+	// recovering is whether errors are being recovered from (see
+	// RecoverOnError).
+	recovering bool
+	// errs are the errors that were recovered from.
+	errs ParseErrorList
 }
 
-// newParser makes a fresh parser using the specified patterns.
+// NewParser makes a fresh Parser using the specified patterns.
 // The patterns should be the args passed into the tool (after any flags)
 // and will be passed to the underlying build system.
-func newParser(patterns ...string) *parser {
-	return &parser{
-		patterns: patterns,
+func NewParser(patterns ...string) *Parser {
+	return &Parser{
+		patterns:          patterns,
+		TypeOverrides:     defaultTypeOverrides(),
+		AddErrorField:     true,
+		StrictChecks:      AllStrictChecks(),
+		MaxRecursionDepth: 20,
+		ValidateExamples:  true,
 	}
 }
 
-func (p *parser) parse() (Definition, error) {
+// Dirs gets the directories of the packages that were parsed by
+// Parse, which are the ones to watch for changes.
+func (p *Parser) Dirs() []string {
+	return p.dirs
+}
+
+// Parse loads the packages and parses the interfaces and
+// structs in them into a Definition.
+func (p *Parser) Parse() (Definition, error) {
+	for _, pattern := range p.ExcludeInterfaces {
+		if _, err := path.Match(pattern, ""); err != nil {
+			return p.def, errors.Wrapf(err, "exclude interfaces: %q", pattern)
+		}
+	}
 	cfg := &packages.Config{
-		Mode:  packages.NeedTypes | packages.NeedDeps | packages.NeedName | packages.NeedSyntax,
+		Mode:  packages.NeedTypes | packages.NeedImports | packages.NeedDeps | packages.NeedName | packages.NeedSyntax,
 		Tests: false,
 	}
+	if len(p.BuildTags) > 0 {
+		cfg.BuildFlags = []string{"-tags=" + strings.Join(p.BuildTags, ",")}
+	}
 	pkgs, err := packages.Load(cfg, p.patterns...)
 	if err != nil {
 		return p.def, err
 	}
 	p.outputObjects = make(map[string]struct{})
 	p.objects = make(map[string]struct{})
-	var excludedObjectsTypeIDs []string
+	p.circularObjects = make(map[string]struct{})
+	p.renamedObjects = make(map[string]string)
+	p.positions = make(map[string]token.Position)
+	p.loadedPackages = make(map[string]*packages.Package)
+	p.docs = make(map[string]*doc.Package)
+	p.recovering = p.RecoverOnError
+	packages.Visit(pkgs, nil, func(pkg *packages.Package) {
+		p.loadedPackages[pkg.PkgPath] = pkg
+	})
 	for _, pkg := range pkgs {
-		p.docs, err = doc.NewFromFiles(pkg.Fset, pkg.Syntax, "")
+		if isInSlice(p.ExcludePackages, pkg.PkgPath) {
+			continue
+		}
+		p.docs[pkg.PkgPath], err = doc.NewFromFiles(pkg.Fset, pkg.Syntax, pkg.PkgPath, doc.PreserveAST)
 		if err != nil {
-			panic(err)
+			return p.def, errors.Wrap(err, "read docs")
 		}
 
-		p.def.PackageName = pkg.Name
+		for _, file := range pkg.Syntax {
+			dir := filepath.Dir(pkg.Fset.File(file.Pos()).Name())
+			if !isInSlice(p.dirs, dir) {
+				p.dirs = append(p.dirs, dir)
+			}
+		}
+		if p.def.PackageName == "" {
+			// the first package names the Definition, use the
+			// -pkg flag to choose another name
+			p.def.PackageName = pkg.Name
+		}
+		if p.def.Version == "" {
+			if p.def.Version, err = packageVersion(p.docs[pkg.PkgPath]); err != nil {
+				return p.def, errors.Wrap(err, pkg.PkgPath)
+			}
+		}
 		scope := pkg.Types.Scope()
 		for _, name := range scope.Names() {
 			obj := scope.Lookup(name)
 			switch item := obj.Type().Underlying().(type) {
 			case *types.Interface:
+				if !item.IsMethodSet() {
+					// constraint interfaces (with type terms) are
+					// for generics, not services
+					if p.Verbose {
+						fmt.Printf("skipping constraint interface %s\n", name)
+					}
+					continue
+				}
+				if !p.isIncludedInterface(name) {
+					if p.Verbose {
+						fmt.Printf("skipping interface %s (not included)\n", name)
+					}
+					for i := 0; i < item.NumMethods(); i++ {
+						p.ignoredTypeIDs = append(p.ignoredTypeIDs, signatureTypeIDs(item.Method(i))...)
+					}
+					continue
+				}
+				if _, skip, _ := extractDirective(p.commentForType(pkg.PkgPath, name), "oto:skip"); skip {
+					if p.Verbose {
+						fmt.Printf("skipping interface %s (oto:skip)\n", name)
+					}
+					for i := 0; i < item.NumMethods(); i++ {
+						p.ignoredTypeIDs = append(p.ignoredTypeIDs, signatureTypeIDs(item.Method(i))...)
+					}
+					continue
+				}
 				s, err := p.parseService(pkg, obj, item)
 				if err != nil {
+					if p.recovering {
+						p.recoverFrom(err)
+						continue
+					}
 					return p.def, err
 				}
-				if isInSlice(p.ExcludeInterfaces, name) {
+				if p.isExcludedInterface(name) {
 					for _, method := range s.Methods {
-						excludedObjectsTypeIDs = append(excludedObjectsTypeIDs, method.InputObject.TypeID)
-						excludedObjectsTypeIDs = append(excludedObjectsTypeIDs, method.OutputObject.TypeID)
+						p.ignoredTypeIDs = append(p.ignoredTypeIDs, method.InputObject.TypeID, method.OutputObject.TypeID)
 					}
 					continue
 				}
+				if position, ok := p.positions["service:"+s.Name]; ok {
+					// templates (and generated files) are keyed by
+					// the service name, so it must be unique
+					err := p.wrapErr(fmt.Errorf("duplicate service %s (also declared at %s)", s.Name, position), pkg, obj.Pos())
+					if p.recovering {
+						p.recoverFrom(err)
+						continue
+					}
+					return p.def, err
+				}
+				p.positions["service:"+s.Name] = pkg.Fset.Position(obj.Pos())
 				p.def.Services = append(p.def.Services, s)
 			case *types.Struct:
-				p.parseObject(pkg, obj, item)
+				if isCustom, _ := p.customMarshaler(obj.Type()); isCustom {
+					// not an object on the wire
+					continue
+				}
+				// errors in objects are only reported in strict mode
+				// (or when a service uses them), so do not recover
+				// from the errors of objects that will be ignored
+				p.recovering = p.RecoverOnError && p.Strict
+				err := p.parseObject(pkg, obj, item, 0)
+				p.recovering = p.RecoverOnError
+				if err != nil && p.Strict {
+					if p.recovering {
+						p.recoverFrom(err)
+						continue
+					}
+					return p.def, err
+				}
 			}
 		}
 	}
-	// remove any excluded objects
-	nonExcludedObjects := make([]Object, 0, len(p.def.Objects))
-	for _, object := range p.def.Objects {
-		excluded := false
-		for _, excludedTypeID := range excludedObjectsTypeIDs {
-			if object.TypeID == excludedTypeID {
-				excluded = true
-				break
-			}
+	p.removeIgnoredObjects()
+	p.removeUnusedEnums()
+	sort.SliceStable(p.def.Enums, func(i, j int) bool {
+		return p.def.Enums[i].Name < p.def.Enums[j].Name
+	})
+	if p.PreserveOrder {
+		p.sortBySourceOrder()
+	} else {
+		sort.Slice(p.def.Services, func(i, j int) bool {
+			return p.def.Services[i].Name < p.def.Services[j].Name
+		})
+	}
+	if p.Strict && p.StrictChecks.MissingObjects {
+		if err := p.checkMissingObjects(); err != nil {
+			return p.def, err
 		}
-		if excluded {
-			continue
+	}
+	if missing := p.findMissingOneOfObjects(); len(missing) > 0 {
+		if p.Strict && p.StrictChecks.MissingObjects {
+			return p.def, fmt.Errorf("%s (strict)", missing[0])
 		}
-		nonExcludedObjects = append(nonExcludedObjects, object)
+		p.def.Warnings = append(p.def.Warnings, missing...)
 	}
-	p.def.Objects = nonExcludedObjects
-	sort.Slice(p.def.Services, func(i, j int) bool {
-		return p.def.Services[i].Name < p.def.Services[j].Name
-	})
-	if err := p.addOutputFields(); err != nil {
-		return p.def, err
+	if p.VersionOverride != "" {
+		p.def.Version = p.VersionOverride
+	}
+	p.resolveFieldLocations()
+	p.markMultipartMethods()
+	p.markInputOutputObjects()
+	if unused := findUnreferencedObjects(&p.def); len(unused) > 0 {
+		if p.FailOnUnused || (p.Strict && p.StrictChecks.UnusedObjects) {
+			return p.def, fmt.Errorf("objects not used by any service: %s (add oto:used to their comments to allow this)", strings.Join(unused, ", "))
+		}
+		for _, name := range unused {
+			p.def.Warnings = append(p.def.Warnings, fmt.Sprintf("object %s is not used by any service", name))
+		}
+	}
+	if p.Strict {
+		for _, ref := range p.sensitiveOutputFields() {
+			p.def.Warnings = append(p.def.Warnings, fmt.Sprintf("%s.%s is sensitive, but it is in a response", ref.ObjectName, ref.Field.Name))
+		}
+		p.def.Warnings = append(p.def.Warnings, p.misplacedReadWriteOnlyFields()...)
+	}
+	if p.AddErrorField {
+		if err := p.addOutputFields(); err != nil {
+			return p.def, err
+		}
+	}
+	if p.SortObjects {
+		sort.SliceStable(p.def.Objects, func(i, j int) bool {
+			return p.def.Objects[i].Name < p.def.Objects[j].Name
+		})
+	}
+	if len(p.errs) > 0 {
+		return p.def, p.errs
 	}
 	return p.def, nil
 }
 
-func (p *parser) parseService(pkg *packages.Package, obj types.Object, interfaceType *types.Interface) (Service, error) {
+// isExcludedInterface gets whether the interface matches any of
+// the ExcludeInterfaces patterns.
+func (p *Parser) isExcludedInterface(name string) bool {
+	for _, pattern := range p.ExcludeInterfaces {
+		if matched, _ := path.Match(pattern, name); matched {
+			return true
+		}
+	}
+	return false
+}
+
+// isIncludedInterface gets whether the interface matches
+// MatchInterfaces and is in IncludeInterfaces (if they are set).
+func (p *Parser) isIncludedInterface(name string) bool {
+	if p.MatchInterfaces != nil && !p.MatchInterfaces.MatchString(name) {
+		return false
+	}
+	if len(p.IncludeInterfaces) > 0 && !isInSlice(p.IncludeInterfaces, name) {
+		return false
+	}
+	return true
+}
+
+func (p *Parser) parseService(pkg *packages.Package, obj types.Object, interfaceType *types.Interface) (Service, error) {
 	var s Service
 	s.Name = obj.Name()
-	s.Comment = p.commentForType(s.Name)
+	s.GoName = s.Name
+	s.PackageName = obj.Pkg().Name()
+	s.PackagePath = obj.Pkg().Path()
+	s.Comment = p.commentForType(obj.Pkg().Path(), s.GoName)
+	rename, hasRename, comment := extractDirective(s.Comment, "oto:rename")
+	if hasRename {
+		s.Comment = comment
+		var err error
+		if s.Name, err = parseRename(rename); err != nil {
+			return s, p.wrapErr(err, pkg, obj.Pos())
+		}
+	}
+	var hasAuth bool
+	s.AuthScheme, hasAuth, s.Comment = extractDirective(s.Comment, "oto:auth")
+	if hasAuth {
+		if _, err := parseAuthScheme(s.AuthScheme); err != nil {
+			return s, p.wrapErr(err, pkg, obj.Pos())
+		}
+	}
+	scopes, hasScopes, comment := extractDirective(s.Comment, "oto:scopes")
+	if hasScopes {
+		s.Comment = comment
+		var err error
+		if s.Scopes, err = parseScopes(scopes); err != nil {
+			return s, p.wrapErr(err, pkg, obj.Pos())
+		}
+	}
+	var hasTimeout bool
+	s.DefaultTimeout, hasTimeout, s.Comment = extractDirective(s.Comment, "oto:timeout")
+	if hasTimeout {
+		if err := validateTimeout(s.DefaultTimeout); err != nil {
+			return s, p.wrapErr(err, pkg, obj.Pos())
+		}
+	}
+	rateLimit, hasRateLimit, comment := extractDirective(s.Comment, "oto:rate-limit")
+	if hasRateLimit {
+		s.Comment = comment
+		var err error
+		if s.DefaultRateLimit, err = parseRateLimit(rateLimit); err != nil {
+			return s, p.wrapErr(err, pkg, obj.Pos())
+		}
+	}
+	var hasPrefix bool
+	s.RoutePrefix, hasPrefix, s.Comment = extractDirective(s.Comment, "oto:prefix")
+	var hasVersion bool
+	s.Version, hasVersion, s.Comment = extractDirective(s.Comment, "oto:version")
+	s.Deprecated, s.DeprecationMessage = deprecation(s.Comment)
+	if hasVersion {
+		if s.Version == "" {
+			return s, p.wrapErr(errors.New("oto:version: missing version"), pkg, obj.Pos())
+		}
+		if strings.ContainsAny(s.Version, " \t/") {
+			return s, p.wrapErr(fmt.Errorf("oto:version: invalid version %q", s.Version), pkg, obj.Pos())
+		}
+	}
+	if hasPrefix {
+		if !strings.HasPrefix(s.RoutePrefix, "/") {
+			return s, p.wrapErr(fmt.Errorf("oto:prefix: %q must start with /", s.RoutePrefix), pkg, obj.Pos())
+		}
+		s.RoutePrefix = strings.TrimSuffix(s.RoutePrefix, "/")
+	}
 	if p.Verbose {
 		fmt.Printf("%s ", s.Name)
 	}
+	explicit := make(map[string]struct{})
+	for i := 0; i < interfaceType.NumExplicitMethods(); i++ {
+		explicit[interfaceType.ExplicitMethod(i).Name()] = struct{}{}
+	}
+	// embeddedBy maps method names to the embedded interface
+	// they came from
+	embeddedBy := make(map[string]string)
+	for i := 0; i < interfaceType.NumEmbeddeds(); i++ {
+		named, ok := types.Unalias(interfaceType.EmbeddedType(i)).(*types.Named)
+		if !ok || isStdlibPackage(named.Obj().Pkg()) {
+			continue
+		}
+		embeddedInterface, ok := named.Underlying().(*types.Interface)
+		if !ok {
+			continue
+		}
+		embedName := types.TypeString(named, func(other *types.Package) string {
+			if other == pkg.Types {
+				return ""
+			}
+			return other.Name()
+		})
+		s.Embeds = append(s.Embeds, embedName)
+		for j := 0; j < embeddedInterface.NumMethods(); j++ {
+			name := embeddedInterface.Method(j).Name()
+			if _, ok := explicit[name]; ok {
+				continue
+			}
+			if _, ok := embeddedBy[name]; !ok {
+				embeddedBy[name] = embedName
+			}
+		}
+	}
+	ignored := stdlibMethods(interfaceType)
+	// methods are unique by name, so methods embedded via
+	// multiple paths only appear once
 	l := interfaceType.NumMethods()
 	for i := 0; i < l; i++ {
 		m := interfaceType.Method(i)
-		method, err := p.parseMethod(pkg, s.Name, m)
+		if _, ok := ignored[m.Name()]; ok {
+			continue
+		}
+		embedName, isEmbedded := embeddedBy[m.Name()]
+		comment := p.commentForMethod(pkg.PkgPath, s.GoName, m.Name())
+		if isEmbedded && comment == "" {
+			comment = p.commentForDeclaredMethod(m)
+		}
+		if _, ignore, _ := extractDirective(comment, "oto:ignore"); ignore {
+			if p.Verbose {
+				fmt.Printf("(skipping %s) ", m.Name())
+			}
+			p.ignoredTypeIDs = append(p.ignoredTypeIDs, signatureTypeIDs(m)...)
+			continue
+		}
+		method, err := p.parseMethod(pkg, s, m)
 		if err != nil {
+			if isEmbedded {
+				return s, errors.Wrapf(err, "%s: embedded interface %s", s.Name, embedName)
+			}
 			return s, err
 		}
+		for _, other := range s.Methods {
+			if other.Name == method.Name {
+				return s, p.wrapErr(fmt.Errorf("%s.%s: oto:rename: %s is already the name of %s", s.Name, method.GoName, method.Name, other.GoName), pkg, m.Pos())
+			}
+		}
+		if isEmbedded && method.Comment == "" {
+			method.Comment = p.commentForDeclaredMethod(m)
+		}
+		method.DeclaredIn = s.Name
+		if declaredIn := declaringInterface(interfaceType, m); declaredIn != nil {
+			method.DeclaredIn = types.TypeString(declaredIn, func(other *types.Package) string {
+				if other == pkg.Types {
+					return ""
+				}
+				return other.Name()
+			})
+		}
+		method.Path = s.RoutePrefix + method.Path
+		if method.Scopes == nil {
+			method.Scopes = s.Scopes
+		}
+		if method.Timeout == "" {
+			method.Timeout = s.DefaultTimeout
+		}
+		if method.RateLimit == nil {
+			method.RateLimit = s.DefaultRateLimit
+		}
+		method.Deprecated, method.DeprecationMessage = deprecation(method.Comment)
+		if p.Strict && p.StrictChecks.MethodComments && method.Comment == "" && !p.isExcludedInterface(s.Name) {
+			return s, p.wrapErr(fmt.Errorf("%s.%s has no comment (strict)", s.Name, method.Name), pkg, m.Pos())
+		}
+		p.positions["method:"+s.Name+"."+method.Name] = pkg.Fset.Position(m.Pos())
 		s.Methods = append(s.Methods, method)
 	}
+	declared := make([]*Method, len(s.Methods))
+	for i := range s.Methods {
+		declared[i] = &s.Methods[i]
+	}
+	sort.SliceStable(declared, func(i, j int) bool {
+		return p.declaredBefore("method:"+s.Name+"."+declared[i].Name, "method:"+s.Name+"."+declared[j].Name)
+	})
+	for i, method := range declared {
+		method.Index = i
+	}
 	return s, nil
 }
 
-func (p *parser) parseMethod(pkg *packages.Package, serviceName string, methodType *types.Func) (Method, error) {
-	var m Method
-	m.Name = methodType.Name()
-	m.NameLowerCamel = camelizeDown(m.Name)
-	m.Comment = p.commentForMethod(serviceName, m.Name)
-	sig := methodType.Type().(*types.Signature)
-	inputParams := sig.Params()
-	if inputParams.Len() != 1 {
-		return m, p.wrapErr(errors.New("invalid method signature: expected Method(MethodRequest) MethodResponse"), pkg, methodType.Pos())
+// declaringInterface gets the embedded interface that declares
+// method, searching interfaces embedded in embedded interfaces too.
+// It returns nil if iface declares the method itself.
+func declaringInterface(iface *types.Interface, method *types.Func) *types.Named {
+	for i := 0; i < iface.NumEmbeddeds(); i++ {
+		named, ok := types.Unalias(iface.EmbeddedType(i)).(*types.Named)
+		if !ok {
+			continue
+		}
+		embeddedInterface, ok := named.Underlying().(*types.Interface)
+		if !ok {
+			continue
+		}
+		for j := 0; j < embeddedInterface.NumExplicitMethods(); j++ {
+			if embeddedInterface.ExplicitMethod(j) == method {
+				return named
+			}
+		}
+		if inner := declaringInterface(embeddedInterface, method); inner != nil {
+			return inner
+		}
 	}
-	var err error
-	m.InputObject, err = p.parseFieldType(pkg, inputParams.At(0))
-	if err != nil {
-		return m, errors.Wrap(err, "parse input object type")
+	return nil
+}
+
+// stdlibMethods gets the names of the methods in the interface that
+// only come from embedded standard library interfaces, including
+// those embedded indirectly.
+func stdlibMethods(iface *types.Interface) map[string]struct{} {
+	methods := make(map[string]struct{})
+	provided := make(map[string]struct{})
+	for i := 0; i < iface.NumExplicitMethods(); i++ {
+		provided[iface.ExplicitMethod(i).Name()] = struct{}{}
 	}
-	outputParams := sig.Results()
-	if outputParams.Len() != 1 {
-		return m, p.wrapErr(errors.New("invalid method signature: expected Method(MethodRequest) MethodResponse"), pkg, methodType.Pos())
+	for i := 0; i < iface.NumEmbeddeds(); i++ {
+		embedded := types.Unalias(iface.EmbeddedType(i))
+		embeddedInterface, ok := embedded.Underlying().(*types.Interface)
+		if !ok {
+			continue
+		}
+		if named, ok := embedded.(*types.Named); ok && isStdlibPackage(named.Obj().Pkg()) {
+			for j := 0; j < embeddedInterface.NumMethods(); j++ {
+				methods[embeddedInterface.Method(j).Name()] = struct{}{}
+			}
+			continue
+		}
+		inner := stdlibMethods(embeddedInterface)
+		for j := 0; j < embeddedInterface.NumMethods(); j++ {
+			name := embeddedInterface.Method(j).Name()
+			if _, ok := inner[name]; ok {
+				methods[name] = struct{}{}
+				continue
+			}
+			provided[name] = struct{}{}
+		}
 	}
-	m.OutputObject, err = p.parseFieldType(pkg, outputParams.At(0))
-	if err != nil {
-		return m, errors.Wrap(err, "parse output object type")
+	for name := range provided {
+		delete(methods, name)
 	}
-	p.outputObjects[m.OutputObject.TypeName] = struct{}{}
-	return m, nil
+	return methods
 }
 
-// parseObject parses a struct type and adds it to the Definition.
-func (p *parser) parseObject(pkg *packages.Package, o types.Object, v *types.Struct) error {
-	var obj Object
-	obj.Name = o.Name()
-	obj.Comment = p.commentForType(obj.Name)
-	if _, found := p.objects[obj.Name]; found {
-		// if this has already been parsed, skip it
-		return nil
-	}
-	if o.Pkg().Name() != pkg.Name {
-		obj.Imported = true
+// isFileType gets whether the type is a file upload:
+// a mime/multipart.FileHeader or an io.Reader.
+func isFileType(typ types.Type) bool {
+	named, ok := types.Unalias(typ).(*types.Named)
+	if !ok || named.Obj().Pkg() == nil {
+		return false
 	}
-	typ := v.Underlying()
-	st, ok := typ.(*types.Struct)
-	if !ok {
-		return p.wrapErr(errors.New(obj.Name+" must be a struct"), pkg, o.Pos())
+	switch named.Obj().Pkg().Path() + "." + named.Obj().Name() {
+	case "mime/multipart.FileHeader", "io.Reader":
+		return true
 	}
-	obj.TypeID = o.Pkg().Path() + "." + obj.Name
-	for i := 0; i < st.NumFields(); i++ {
-		field, err := p.parseField(pkg, obj.Name, st.Field(i))
-		if err != nil {
-			return err
-		}
-		field.Tag = v.Tag(i)
-		field.ParsedTags, err = p.parseTags(field.Tag)
-		if err != nil {
-			return errors.Wrap(err, "parse field tag")
-		}
-		obj.Fields = append(obj.Fields, field)
+	return false
+}
+
+// isHTTPMethod gets whether the (uppercase) verb is one of the
+// standard HTTP methods.
+func isHTTPMethod(verb string) bool {
+	switch verb {
+	case "GET", "POST", "PUT", "PATCH", "DELETE", "HEAD", "OPTIONS":
+		return true
 	}
-	p.def.Objects = append(p.def.Objects, obj)
-	p.objects[obj.Name] = struct{}{}
-	return nil
+	return false
 }
 
-func (p *parser) parseTags(tag string) (map[string]FieldTag, error) {
-	tags, err := structtag.Parse(tag)
+// validateTimeout checks the value of an oto:timeout comment
+// line is a positive duration, like "30s" or "5m".
+func validateTimeout(value string) error {
+	duration, err := time.ParseDuration(value)
 	if err != nil {
-		return nil, err
+		return errors.Errorf("oto:timeout: invalid duration %q (expected something like 30s or 5m)", value)
 	}
-	fieldTags := make(map[string]FieldTag)
-	for _, tag := range tags.Tags() {
-		fieldTags[tag.Key] = FieldTag{
-			Value:   tag.Name,
-			Options: tag.Options,
-		}
+	if duration <= 0 {
+		return errors.Errorf("oto:timeout: %q must be positive", value)
 	}
-	return fieldTags, nil
+	return nil
 }
 
-func (p *parser) parseField(pkg *packages.Package, objectName string, v *types.Var) (Field, error) {
-	var f Field
-	f.Name = v.Name()
-	f.NameLowerCamel = camelizeDown(f.Name)
-	f.Comment = p.commentForField(objectName, f.Name)
-	if !v.Exported() {
-		return f, p.wrapErr(errors.New(f.Name+" must be exported"), pkg, v.Pos())
+// parseRateLimit parses the value of an oto:rate-limit comment
+// line, like "100 per minute".
+func parseRateLimit(value string) (*RateLimit, error) {
+	parts := strings.Fields(value)
+	if len(parts) != 3 || parts[1] != "per" {
+		return nil, errors.Errorf("oto:rate-limit: invalid %q (expected something like 100 per minute)", value)
 	}
-	var err error
-	f.Example, f.Comment, err = extractExample(f.Comment)
-	if err != nil {
-		return f, p.wrapErr(errors.New("extract comment example"), pkg, v.Pos())
+	requests, err := strconv.Atoi(parts[0])
+	if err != nil || requests <= 0 {
+		return nil, errors.Errorf("oto:rate-limit: %q must be a positive number of requests", parts[0])
 	}
-	f.Type, err = p.parseFieldType(pkg, v)
-	if err != nil {
-		return f, errors.Wrap(err, "parse type")
+	switch parts[2] {
+	case "second", "minute", "hour", "day":
+	default:
+		return nil, errors.Errorf("oto:rate-limit: unknown period %q (expected second, minute, hour or day)", parts[2])
 	}
-	return f, nil
+	return &RateLimit{Requests: requests, Per: parts[2]}, nil
 }
 
-func (p *parser) parseFieldType(pkg *packages.Package, obj types.Object) (FieldType, error) {
-	var ftype FieldType
-	pkgPath := pkg.PkgPath
-	resolver := func(other *types.Package) string {
-		if other.Name() != pkg.Name {
-			if p.def.Imports == nil {
-				p.def.Imports = make(map[string]string)
-			}
-			p.def.Imports[other.Path()] = other.Name()
-			ftype.Package = other.Path()
-			pkgPath = other.Path()
-			return other.Name()
-		}
-		return "" // no package prefix
+// isErrorType gets whether the type is the built-in error.
+func isErrorType(typ types.Type) bool {
+	return types.Identical(typ, types.Universe.Lookup("error").Type())
+}
+
+// isStdlibPackage gets whether the package is part of the
+// Go standard library. Built-in types (like error) have
+// no package, and are considered part of it.
+// Like the go command, packages are in the standard library if the
+// first element of their path has no dot.
+func isStdlibPackage(pkg *types.Package) bool {
+	if pkg == nil {
+		return true
 	}
-	typ := obj.Type()
-	if slice, ok := obj.Type().(*types.Slice); ok {
-		typ = slice.Elem()
-		ftype.Multiple = true
+	first, _, _ := strings.Cut(pkg.Path(), "/")
+	return !strings.Contains(first, ".")
+}
+
+// isContextType gets whether the type is context.Context.
+func isContextType(typ types.Type) bool {
+	named, ok := types.Unalias(typ).(*types.Named)
+	if !ok || named.Obj().Pkg() == nil {
+		return false
 	}
-	if named, ok := typ.(*types.Named); ok {
+	return named.Obj().Pkg().Path()+"."+named.Obj().Name() == "context.Context"
+}
+
+func (p *Parser) parseMethod(pkg *packages.Package, service Service, methodType *types.Func) (Method, error) {
+	serviceName := service.Name
+	var m Method
+	m.Name = methodType.Name()
+	m.GoName = m.Name
+	m.Comment = p.commentForMethod(pkg.PkgPath, service.GoName, m.GoName)
+	rename, hasRename, comment := extractDirective(m.Comment, "oto:rename")
+	if hasRename {
+		m.Comment = comment
+		var err error
+		if m.Name, err = parseRename(rename); err != nil {
+			return m, p.wrapErr(err, pkg, methodType.Pos())
+		}
+	}
+	m.NameLowerCamel = camelizeDown(m.Name)
+	m.NameSnake = snakeDown(m.Name)
+	m.NameKebab = kebabDown(m.Name)
+	var isQuery, isMutation bool
+	_, isQuery, m.Comment = extractDirective(m.Comment, "oto:graphql-query")
+	_, isMutation, m.Comment = extractDirective(m.Comment, "oto:graphql-mutation")
+	switch {
+	case isQuery && isMutation:
+		return m, p.wrapErr(errors.New("oto:graphql-query and oto:graphql-mutation cannot be used together"), pkg, methodType.Pos())
+	case isQuery:
+		m.GraphQLOperation = "query"
+	case isMutation:
+		m.GraphQLOperation = "mutation"
+	}
+	var hasAuth bool
+	m.AuthOverride, hasAuth, m.Comment = extractDirective(m.Comment, "oto:auth")
+	if hasAuth {
+		if _, err := parseAuthScheme(m.AuthOverride); err != nil {
+			return m, p.wrapErr(err, pkg, methodType.Pos())
+		}
+	}
+	scopes, hasScopes, comment := extractDirective(m.Comment, "oto:scopes")
+	if hasScopes {
+		m.Comment = comment
+		var err error
+		if m.Scopes, err = parseScopes(scopes); err != nil {
+			return m, p.wrapErr(err, pkg, methodType.Pos())
+		}
+	}
+	var hasTimeout bool
+	m.Timeout, hasTimeout, m.Comment = extractDirective(m.Comment, "oto:timeout")
+	if hasTimeout {
+		if err := validateTimeout(m.Timeout); err != nil {
+			return m, p.wrapErr(err, pkg, methodType.Pos())
+		}
+	}
+	rateLimit, hasRateLimit, comment := extractDirective(m.Comment, "oto:rate-limit")
+	if hasRateLimit {
+		m.Comment = comment
+		var err error
+		if m.RateLimit, err = parseRateLimit(rateLimit); err != nil {
+			return m, p.wrapErr(err, pkg, methodType.Pos())
+		}
+	}
+	m.HTTPMethod = "POST"
+	httpMethod, hasHTTPMethod, comment := extractDirective(m.Comment, "oto:method")
+	if hasHTTPMethod {
+		m.Comment = comment
+		if isHTTPMethod(strings.ToUpper(httpMethod)) {
+			m.HTTPMethod = strings.ToUpper(httpMethod)
+		} else {
+			position := pkg.Fset.Position(methodType.Pos())
+			p.def.Warnings = append(p.def.Warnings, fmt.Sprintf("%s: %s.%s: unknown HTTP method %q in oto:method (using POST)", position, serviceName, m.Name, httpMethod))
+		}
+	}
+	route, hasRoute, comment := extractDirective(m.Comment, "oto:route")
+	if hasRoute {
+		if hasHTTPMethod {
+			return m, p.wrapErr(errors.New("oto:method and oto:route cannot be used together"), pkg, methodType.Pos())
+		}
+		m.Comment = comment
+		fields := strings.Fields(route)
+		if len(fields) != 2 {
+			return m, p.wrapErr(fmt.Errorf("oto:route: expected VERB /path, not %q", route), pkg, methodType.Pos())
+		}
+		if !isHTTPMethod(strings.ToUpper(fields[0])) {
+			return m, p.wrapErr(fmt.Errorf("oto:route: unknown HTTP method %q", fields[0]), pkg, methodType.Pos())
+		}
+		if !strings.HasPrefix(fields[1], "/") {
+			return m, p.wrapErr(fmt.Errorf("oto:route: path %q must start with /", fields[1]), pkg, methodType.Pos())
+		}
+		m.HTTPMethod = strings.ToUpper(fields[0])
+		m.HTTPPath = fields[1]
+	}
+	m.Path = "/" + serviceName + "/" + m.Name
+	if m.HTTPPath != "" {
+		m.Path = m.HTTPPath
+	}
+	path, hasPath, comment := extractDirective(m.Comment, "oto:path")
+	if hasPath {
+		if hasRoute {
+			return m, p.wrapErr(errors.New("oto:path and oto:route cannot be used together"), pkg, methodType.Pos())
+		}
+		m.Comment = comment
+		if !strings.HasPrefix(path, "/") {
+			return m, p.wrapErr(fmt.Errorf("oto:path: path %q must start with /", path), pkg, methodType.Pos())
+		}
+		m.Path = path
+	}
+	stream, isStream, comment := extractDirective(m.Comment, "oto:stream")
+	if isStream {
+		m.Comment = comment
+		switch stream {
+		case "", "server":
+			m.Stream = "server"
+		case "client", "bidi":
+			m.Stream = stream
+		default:
+			return m, p.wrapErr(fmt.Errorf("oto:stream: unknown stream %q (expected server, client or bidi)", stream), pkg, methodType.Pos())
+		}
+	}
+	var isStreaming, hasStreamingError bool
+	_, isStreaming, m.Comment = extractDirective(m.Comment, "oto:streaming")
+	_, hasStreamingError, m.Comment = extractDirective(m.Comment, "oto:streaming-error")
+	if isStreaming && m.Stream == "" {
+		m.Stream = "server"
+	}
+	m.Streaming = m.Stream == "server" || m.Stream == "bidi"
+	sig := methodType.Type().(*types.Signature)
+	if sig.Variadic() {
+		return m, p.wrapErr(fmt.Errorf("%s.%s: variadic parameters are not supported: expected Method(MethodRequest) MethodResponse", serviceName, m.Name), pkg, methodType.Pos())
+	}
+	inputParams := make([]*types.Var, 0, sig.Params().Len())
+	for i := 0; i < sig.Params().Len(); i++ {
+		inputParams = append(inputParams, sig.Params().At(i))
+	}
+	if len(inputParams) > 0 && isContextType(inputParams[0].Type()) {
+		m.HasContext = true
+		inputParams = inputParams[1:]
+	}
+	if len(inputParams) > 1 && !p.SynthesizeRequests {
+		return m, p.wrapErr(errors.New("invalid method signature: expected Method(MethodRequest) MethodResponse"), pkg, methodType.Pos())
+	}
+	var err error
+	if len(inputParams) > 1 {
+		m.HasInput = true
+		m.InputObject, err = p.synthesizeRequest(pkg, serviceName, methodType, inputParams)
+		if err != nil {
+			return m, err
+		}
+	} else if len(inputParams) == 1 {
+		m.HasInput = true
+		m.InputObject, err = p.parseFieldType(pkg, inputParams[0], 0)
+		if err != nil {
+			return m, errors.Wrap(err, "parse input object type")
+		}
+	}
+	outputParams := make([]*types.Var, 0, sig.Results().Len())
+	for i := 0; i < sig.Results().Len(); i++ {
+		outputParams = append(outputParams, sig.Results().At(i))
+	}
+	if len(outputParams) > 0 && isErrorType(outputParams[len(outputParams)-1].Type()) {
+		m.ReturnsError = true
+		outputParams = outputParams[:len(outputParams)-1]
+	}
+	if len(outputParams) > 1 {
+		return m, p.wrapErr(errors.New("invalid method signature: expected Method(MethodRequest) MethodResponse"), pkg, methodType.Pos())
+	}
+	if len(outputParams) == 1 {
+		m.HasOutput = true
+		m.OutputObject, err = p.parseFieldType(pkg, outputParams[0], 0)
+		if err != nil {
+			return m, errors.Wrap(err, "parse output object type")
+		}
+		if !m.Streaming || hasStreamingError {
+			p.outputObjects[m.OutputObject.ObjectName] = struct{}{}
+		}
+	}
+	if m.Streaming {
+		if !m.HasOutput || !m.OutputObject.IsObject || m.OutputObject.Multiple {
+			return m, p.wrapErr(fmt.Errorf("%s.%s: streamed output must be a struct", serviceName, m.Name), pkg, methodType.Pos())
+		}
+	}
+	if m.Stream == "client" || m.Stream == "bidi" {
+		if !m.HasInput || !m.InputObject.IsObject || m.InputObject.Multiple {
+			return m, p.wrapErr(fmt.Errorf("%s.%s: streamed input must be a struct", serviceName, m.Name), pkg, methodType.Pos())
+		}
+	}
+	return m, nil
+}
+
+// synthesizeRequest makes a request object for a method that takes
+// more than one parameter, with a field for each parameter, and adds
+// it to the Definition.
+// The object is named after the method, so Get(id string) gets a
+// GetRequest with an Id field.
+func (p *Parser) synthesizeRequest(pkg *packages.Package, serviceName string, methodType *types.Func, params []*types.Var) (FieldType, error) {
+	var obj Object
+	obj.Name = methodType.Name() + "Request"
+	obj.NameSnake = snakeDown(obj.Name)
+	obj.NameKebab = kebabDown(obj.Name)
+	obj.TypeID = pkg.PkgPath + "." + obj.Name
+	obj.Comment = fmt.Sprintf("%s is the request object for %s.%s.", obj.Name, serviceName, methodType.Name())
+	_, isDeclared := p.objects[obj.Name]
+	if pkg.Types.Scope().Lookup(obj.Name) != nil || isDeclared {
+		return FieldType{}, p.wrapErr(fmt.Errorf("%s.%s: cannot make request object %s because the name is already used", serviceName, methodType.Name(), obj.Name), pkg, methodType.Pos())
+	}
+	p.objects[obj.Name] = struct{}{}
+	for _, param := range params {
+		if param.Name() == "" || param.Name() == "_" {
+			return FieldType{}, p.wrapErr(fmt.Errorf("%s.%s: parameters must be named to make a request object", serviceName, methodType.Name()), pkg, methodType.Pos())
+		}
+		var f Field
+		first, size := utf8.DecodeRuneInString(param.Name())
+		f.Name = string(unicode.ToUpper(first)) + param.Name()[size:]
+		f.NameLowerCamel = camelizeDown(f.Name)
+		f.NameSnake = snakeDown(f.Name)
+		f.NameKebab = kebabDown(f.Name)
+		f.JSONName = f.NameLowerCamel
+		var err error
+		f.Type, err = p.parseFieldType(pkg, param, 0)
+		if err != nil {
+			return FieldType{}, errors.Wrapf(err, "parse type of %s.%s", obj.Name, f.Name)
+		}
+		f.Required = !f.Type.Nullable
+		f.Index = len(obj.Fields)
+		obj.Fields = append(obj.Fields, f)
+		p.positions["field:"+obj.TypeID+"."+f.Name] = pkg.Fset.Position(param.Pos())
+	}
+	p.sortFields(&obj)
+	p.positions["object:"+obj.TypeID] = pkg.Fset.Position(methodType.Pos())
+	p.def.Objects = append(p.def.Objects, obj)
+	return FieldType{
+		TypeID:               obj.TypeID,
+		TypeName:             obj.Name,
+		ObjectName:           obj.Name,
+		ObjectNameLowerCamel: camelizeDown(obj.Name),
+		ObjectNameSnake:      obj.NameSnake,
+		ObjectNameKebab:      obj.NameKebab,
+		IsObject:             true,
+		JSType:               "object",
+	}, nil
+}
+
+// isSkippedObject gets whether the struct has the oto:skip
+// comment line.
+func (p *Parser) isSkippedObject(o types.Object) bool {
+	_, skip, _ := extractDirective(p.commentForType(o.Pkg().Path(), o.Name()), "oto:skip")
+	return skip
+}
+
+// parseObject parses a struct type and adds it to the Definition.
+func (p *Parser) parseObject(pkg *packages.Package, o types.Object, v *types.Struct, depth int) error {
+	var obj Object
+	obj.Name = o.Name()
+	obj.GoName = obj.Name
+	obj.TypeID = o.Pkg().Path() + "." + obj.GoName
+	if name, ok := p.renamedObjects[obj.TypeID]; ok {
+		obj.Name = name
+	}
+	if p.isParsedObject(obj.Name) {
+		// before the comment lines are handled (and example
+		// files read) again
+		return nil
+	}
+	obj.Comment = p.commentForType(o.Pkg().Path(), obj.GoName)
+	// before the comment lines are extracted, which removes the
+	// blank lines that end the paragraph
+	obj.Deprecated, obj.DeprecationMessage = deprecation(obj.Comment)
+	if p.isSkippedObject(o) {
+		if p.Verbose {
+			fmt.Printf("(skipping object %s: oto:skip) ", obj.Name)
+		}
+		return nil
+	}
+	rename, hasRename, comment := extractDirective(obj.Comment, "oto:rename")
+	if hasRename {
+		obj.Comment = comment
+		var err error
+		if obj.Name, err = parseRename(rename); err != nil {
+			return p.wrapErr(err, pkg, o.Pos())
+		}
+		if other := o.Pkg().Scope().Lookup(obj.Name); other != nil && other != o {
+			return p.wrapErr(fmt.Errorf("oto:rename: %s is already declared at %s", obj.Name, pkg.Fset.Position(other.Pos())), pkg, o.Pos())
+		}
+		for typeID, name := range p.renamedObjects {
+			if name == obj.Name && typeID != obj.TypeID {
+				return p.wrapErr(fmt.Errorf("oto:rename: %s is already the name of %s", obj.Name, typeID), pkg, o.Pos())
+			}
+		}
+		p.renamedObjects[obj.TypeID] = obj.Name
+	}
+	obj.NameSnake = snakeDown(obj.Name)
+	obj.NameKebab = kebabDown(obj.Name)
+	_, obj.Used, obj.Comment = extractDirective(obj.Comment, "oto:used")
+	example, hasExample, comment, err := extractCommentValue(obj.Comment, "example:")
+	if err != nil {
+		pos := commentLinePos(p.docForType(o.Pkg().Path(), obj.GoName), "example:", o.Pos())
+		return p.wrapErr(fmt.Errorf("%s: example: invalid JSON: %s", obj.Name, err), pkg, pos)
+	}
+	if hasExample {
+		obj.Comment = comment
+	}
+	exampleFile, hasExampleFile, comment := extractDirective(obj.Comment, "example-file:")
+	if hasExampleFile {
+		obj.Comment = comment
+		pos := commentLinePos(p.docForType(o.Pkg().Path(), obj.GoName), "example-file:", o.Pos())
+		if hasExample {
+			return p.wrapErr(fmt.Errorf("%s: has both example: and example-file: lines", obj.Name), pkg, pos)
+		}
+		if example, err = readExampleFile(pkg.Fset, exampleFile, pos); err != nil {
+			return p.wrapErr(fmt.Errorf("%s: %s", obj.Name, err), pkg, pos)
+		}
+		hasExample = true
+	}
+	if hasExample {
+		var ok bool
+		if obj.Example, ok = example.(map[string]interface{}); !ok {
+			return p.wrapErr(fmt.Errorf("%s: example: expected a JSON object", obj.Name), pkg, o.Pos())
+		}
+	}
+	if p.isParsedObject(obj.Name) {
+		// another type already has this name
+		return nil
+	}
+	if o.Pkg().Name() != pkg.Name {
+		obj.Imported = true
+	}
+	typ := v.Underlying()
+	st, ok := typ.(*types.Struct)
+	if !ok {
+		return p.wrapErr(errors.New(obj.Name+" must be a struct"), pkg, o.Pos())
+	}
+	// mark the object before parsing the fields, so cycles
+	// do not recurse forever
+	p.objects[obj.Name] = struct{}{}
+	p.parsingObjects = append(p.parsingObjects, obj.Name)
+	defer func() {
+		p.parsingObjects = p.parsingObjects[:len(p.parsingObjects)-1]
+	}()
+	for i := 0; i < st.NumFields(); i++ {
+		if jsonTag, _ := reflect.StructTag(v.Tag(i)).Lookup("json"); jsonTag == "-" {
+			// never on the wire (but json:"-," is a field named "-")
+			continue
+		}
+		if p.SkipUnexportedFields && !st.Field(i).Exported() {
+			if p.Verbose {
+				fmt.Printf("(skipping unexported field %s.%s) ", obj.Name, st.Field(i).Name())
+			}
+			continue
+		}
+		field, err := p.parseObjectField(pkg, obj.Name, obj.GoName, st, i, depth)
+		if err != nil {
+			if p.recovering {
+				// leave the field out, and carry on with the rest
+				p.recoverFrom(err)
+				continue
+			}
+			delete(p.objects, obj.Name)
+			return err
+		}
+		field.Index = len(obj.Fields)
+		p.positions["field:"+obj.TypeID+"."+field.Name] = pkg.Fset.Position(st.Field(i).Pos())
+		obj.Fields = append(obj.Fields, field)
+	}
+	if _, ok := p.circularObjects[obj.Name]; ok {
+		obj.Circular = true
+	}
+	p.checkObjectExample(pkg, o, obj)
+	p.sortFields(&obj)
+	p.positions["object:"+obj.TypeID] = pkg.Fset.Position(o.Pos())
+	p.def.Objects = append(p.def.Objects, obj)
+	return nil
+}
+
+// isParsedObject gets whether the object with the name has already
+// been parsed (or is being parsed further up the stack, in which case
+// the objects in between are marked as circular).
+func (p *Parser) isParsedObject(name string) bool {
+	if _, found := p.objects[name]; !found {
+		return false
+	}
+	for i := range p.parsingObjects {
+		if p.parsingObjects[i] != name {
+			continue
+		}
+		// every object from here up the stack is in the cycle
+		for _, name := range p.parsingObjects[i:] {
+			p.circularObjects[name] = struct{}{}
+		}
+		break
+	}
+	return true
+}
+
+// parseObjectField parses the ith field of the struct st, with
+// its tags and comment lines.
+func (p *Parser) parseObjectField(pkg *packages.Package, objectName, goName string, st *types.Struct, i, depth int) (Field, error) {
+	field, err := p.parseField(pkg, goName, st.Field(i), depth)
+	if err != nil {
+		return field, err
+	}
+	field.Tag = st.Tag(i)
+	field.ParsedTags, err = p.parseTags(field.Tag)
+	if err != nil {
+		return field, errors.Wrap(err, "parse field tag")
+	}
+	field.JSONName = field.NameLowerCamel
+	if jsonTag, ok := field.ParsedTags["json"]; ok {
+		if jsonTag.Value != "" {
+			field.JSONName = jsonTag.Value
+		}
+		field.OmitEmpty = isInSlice(jsonTag.Options, "omitempty")
+	}
+	required, hasRequired, comment := extractDirective(field.Comment, "required:")
+	if hasRequired {
+		field.Comment = comment
+		if field.Required, err = strconv.ParseBool(required); err != nil {
+			return field, p.wrapErr(fmt.Errorf("%s.%s: required: invalid value %q (expected true or false)", objectName, field.Name, required), pkg, st.Field(i).Pos())
+		}
+	} else {
+		field.Required = hasRequiredValidator(field.ParsedTags) || (!field.OmitEmpty && !field.Type.Nullable)
+	}
+	if validateTag, ok := field.ParsedTags["validate"]; ok {
+		field.Constraints, err = parseValidateTag(validateTag, field.Type)
+		if err != nil {
+			return field, p.wrapErr(fmt.Errorf("%s.%s: %s", objectName, field.Name, err), pkg, st.Field(i).Pos())
+		}
+	}
+	field.Constraints, field.Comment, err = parseConstraintLines(field.Comment, field.Type, field.Constraints)
+	if err == nil {
+		err = checkConstraints(field.Constraints)
+	}
+	if err != nil {
+		return field, p.wrapErr(fmt.Errorf("%s.%s: %s", objectName, field.Name, err), pkg, st.Field(i).Pos())
+	}
+	if otoTag, ok := field.ParsedTags["oto"]; ok {
+		for _, option := range append([]string{otoTag.Value}, otoTag.Options...) {
+			if strings.HasPrefix(option, "in=") {
+				field.In = strings.TrimPrefix(option, "in=")
+			}
+			switch option {
+			case "sensitive":
+				field.Sensitive = true
+			case "readonly":
+				field.ReadOnly = true
+			case "writeonly":
+				field.WriteOnly = true
+			}
+			if strings.HasPrefix(option, "format=") {
+				format := strings.TrimPrefix(option, "format=")
+				if err := p.checkFormat(format, objectName+"."+field.Name+": oto tag", pkg, st.Field(i).Pos()); err != nil {
+					return field, err
+				}
+				field.Type.Format = format
+			}
+		}
+	}
+	if field.ReadOnly && field.WriteOnly {
+		return field, p.wrapErr(fmt.Errorf("%s.%s: cannot be both readonly and writeonly", objectName, field.Name), pkg, st.Field(i).Pos())
+	}
+	switch field.In {
+	case "", "body", "query", "header", "path", "cookie":
+	default:
+		return field, p.wrapErr(fmt.Errorf("%s.%s: unknown location %q (expected body, query, header, path or cookie)", objectName, field.Name, field.In), pkg, st.Field(i).Pos())
+	}
+	return field, nil
+}
+
+func (p *Parser) parseTags(tag string) (map[string]FieldTag, error) {
+	tags, err := structtag.Parse(tag)
+	if err != nil {
+		return nil, err
+	}
+	fieldTags := make(map[string]FieldTag)
+	for _, tag := range tags.Tags() {
+		fieldTags[tag.Key] = FieldTag{
+			Value:   tag.Name,
+			Options: tag.Options,
+		}
+	}
+	return fieldTags, nil
+}
+
+func (p *Parser) parseField(pkg *packages.Package, objectName string, v *types.Var, depth int) (Field, error) {
+	var f Field
+	f.Name = v.Name()
+	f.NameLowerCamel = camelizeDown(f.Name)
+	f.NameSnake = snakeDown(f.Name)
+	f.NameKebab = kebabDown(f.Name)
+	f.Comment = p.commentForField(v.Pkg().Path(), objectName, f.Name)
+	// before the comment lines are extracted, which removes the
+	// blank lines that end the paragraph
+	f.Deprecated, f.DeprecationMessage = deprecation(f.Comment)
+	if !v.Exported() {
+		return f, p.wrapErr(errors.New(f.Name+" must be exported"), pkg, v.Pos())
+	}
+	var err error
+	f.Example, f.Comment, err = extractExample(f.Comment)
+	if err != nil {
+		pos := commentLinePos(p.docForField(v.Pkg().Path(), objectName, f.Name), "example:", v.Pos())
+		return f, p.wrapErr(fmt.Errorf("%s.%s: example: invalid JSON: %s", objectName, f.Name, err), pkg, pos)
+	}
+	exampleFile, hasExampleFile, comment := extractDirective(f.Comment, "example-file:")
+	if hasExampleFile {
+		f.Comment = comment
+		pos := commentLinePos(p.docForField(v.Pkg().Path(), objectName, f.Name), "example-file:", v.Pos())
+		if f.Example != nil {
+			return f, p.wrapErr(fmt.Errorf("%s.%s: has both example: and example-file: lines", objectName, f.Name), pkg, pos)
+		}
+		if f.Example, err = readExampleFile(pkg.Fset, exampleFile, pos); err != nil {
+			return f, p.wrapErr(fmt.Errorf("%s.%s: %s", objectName, f.Name, err), pkg, pos)
+		}
+	}
+	var nullable, asString bool
+	_, nullable, f.Comment = extractDirective(f.Comment, "oto:nullable")
+	_, asString, f.Comment = extractDirective(f.Comment, "oto:int64-as-string")
+	f.In, _, f.Comment = extractDirective(f.Comment, "oto:in")
+	var isFile bool
+	_, isFile, f.Comment = extractDirective(f.Comment, "oto:file")
+	_, f.Sensitive, f.Comment = extractDirective(f.Comment, "oto:sensitive")
+	_, f.ReadOnly, f.Comment = extractDirective(f.Comment, "oto:readonly")
+	_, f.WriteOnly, f.Comment = extractDirective(f.Comment, "oto:writeonly")
+	format, hasFormat, comment := extractDirective(f.Comment, "oto:format")
+	formatSource := "oto:format"
+	if !hasFormat {
+		format, hasFormat, comment = extractDirective(f.Comment, "format:")
+		formatSource = "format"
+	}
+	if hasFormat {
+		f.Comment = comment
+		if err := p.checkFormat(format, objectName+"."+f.Name+": "+formatSource, pkg, v.Pos()); err != nil {
+			return f, err
+		}
+	}
+	f.Default, f.HasDefault, f.Comment, err = extractDefault(f.Comment)
+	if err != nil {
+		pos := commentLinePos(p.docForField(v.Pkg().Path(), objectName, f.Name), "default:", v.Pos())
+		return f, p.wrapErr(fmt.Errorf("%s.%s: default: %s", objectName, f.Name, err), pkg, pos)
+	}
+	defaultValue, hasDefaultDirective, comment := extractDirective(f.Comment, "oto:default")
+	if hasDefaultDirective {
+		f.Comment = comment
+		if f.HasDefault {
+			return f, p.wrapErr(fmt.Errorf("%s.%s: has both default: and oto:default lines", objectName, f.Name), pkg, v.Pos())
+		}
+		f.HasDefault = true
+		if err := json.Unmarshal([]byte(defaultValue), &f.Default); err != nil {
+			err = p.wrapErr(fmt.Errorf("%s.%s: oto:default: invalid JSON value %q", objectName, f.Name, defaultValue), pkg, v.Pos())
+			if p.Strict {
+				return f, err
+			}
+			p.def.Warnings = append(p.def.Warnings, err.Error())
+			f.HasDefault = false
+		}
+	}
+	enum, hasEnum, comment := extractDirective(f.Comment, "enum:")
+	f.Comment = comment
+	oneOf, hasOneOf, comment := extractDirective(f.Comment, "oto:oneof")
+	var hasDiscriminator bool
+	f.Discriminator, hasDiscriminator, comment = extractDirective(comment, "oto:discriminator")
+	f.Comment = comment
+	f.Type, err = p.parseFieldType(pkg, v, depth)
+	if err != nil {
+		return f, errors.Wrapf(err, "parse type of %s.%s", objectName, f.Name)
+	}
+	if hasOneOf {
+		if f.Type.OneOf, err = parseOneOf(oneOf); err != nil {
+			return f, p.wrapErr(err, pkg, v.Pos())
+		}
+	}
+	if hasDiscriminator {
+		if f.Discriminator == "" {
+			return f, p.wrapErr(errors.New("oto:discriminator: missing property name"), pkg, v.Pos())
+		}
+		if !hasOneOf {
+			return f, p.wrapErr(errors.New("oto:discriminator: the field has no oto:oneof line"), pkg, v.Pos())
+		}
+	}
+	if nullable {
+		f.Type.Nullable = true
+	}
+	if isFile {
+		f.Type.IsFile = true
+	}
+	if hasFormat {
+		f.Type.Format = format
+	}
+	if p.Strict && p.StrictChecks.FieldExamples && f.Example == nil {
+		return f, p.wrapErr(fmt.Errorf("%s.%s has no example (strict)", objectName, f.Name), pkg, v.Pos())
+	}
+	if p.Strict && p.StrictChecks.UnknownJSTypes && hasUnknownJSType(f.Type) {
+		return f, p.wrapErr(fmt.Errorf("%s.%s: unknown JSType for %s (strict)", objectName, f.Name, f.Type.TypeName), pkg, v.Pos())
+	}
+	if asString {
+		int64AsString(&f.Type)
+	}
+	if p.ValidateExamples && f.Example != nil {
+		if err := checkValueType(f.Example, f.Type); err != nil {
+			pos := commentLinePos(p.docForField(v.Pkg().Path(), objectName, f.Name), "example:", v.Pos())
+			return f, p.wrapErr(fmt.Errorf("%s.%s: example: %s", objectName, f.Name, err), pkg, pos)
+		}
+	}
+	if hasEnum {
+		if f.Enum, err = parseEnumLine(enum, f.Type); err != nil {
+			return f, p.wrapErr(fmt.Errorf("%s.%s: enum: %s", objectName, f.Name, err), pkg, v.Pos())
+		}
+	}
+	if f.HasDefault {
+		if err := checkValueType(f.Default, f.Type); err != nil {
+			return f, p.wrapErr(fmt.Errorf("%s.%s: default: %s", objectName, f.Name, err), pkg, v.Pos())
+		}
+	}
+	return f, nil
+}
+
+// knownFormats are the Format values that are checked by
+// checkFormat. They come from OpenAPI and JSON Schema.
+var knownFormats = []string{
+	"email", "uri", "uuid", "date-time", "date", "time", "duration",
+	"binary", "byte", "password", "hostname", "ipv4", "ipv6",
+	"int32", "int64", "float", "double",
+}
+
+// checkFormat checks a format from an oto:format comment line or
+// oto tag, described by source. Unknown formats are errors in
+// strict mode, and warnings otherwise.
+func (p *Parser) checkFormat(format, source string, pkg *packages.Package, pos token.Pos) error {
+	if format == "" {
+		return p.wrapErr(fmt.Errorf("%s: missing format", source), pkg, pos)
+	}
+	if isInSlice(knownFormats, format) {
+		return nil
+	}
+	err := p.wrapErr(fmt.Errorf("%s: unknown format %q (expected one of: %s)", source, format, strings.Join(knownFormats, ", ")), pkg, pos)
+	if p.Strict {
+		return err
+	}
+	p.def.Warnings = append(p.def.Warnings, err.Error())
+	return nil
+}
+
+// parseEnumLine parses the values of an enum: comment line, like
+// `["asc", "desc"]`, and checks that they are of the type ftype (or
+// its items).
+func parseEnumLine(value string, ftype FieldType) ([]interface{}, error) {
+	switch {
+	case ftype.IsObject, ftype.IsMap, len(ftype.OneOf) > 0:
+		return nil, errors.New("only strings, numbers and booleans can have enum values")
+	case value == "":
+		return nil, errors.New("missing values")
+	}
+	if !strings.HasPrefix(value, "[") {
+		value = "[" + value + "]"
+	}
+	var values []interface{}
+	if err := json.Unmarshal([]byte(value), &values); err != nil {
+		return nil, errors.Errorf("invalid JSON array %s", value)
+	}
+	if len(values) == 0 {
+		return nil, errors.New("missing values")
+	}
+	itemType := ftype
+	itemType.Multiple = false
+	for i, item := range values {
+		if err := checkValueType(item, itemType); err != nil {
+			return nil, errors.Wrapf(err, "value %d", i)
+		}
+	}
+	return values, nil
+}
+
+// checkObjectExample adds warnings for the keys in the Example of the
+// object that are not the JSONName of any of its fields.
+func (p *Parser) checkObjectExample(pkg *packages.Package, o types.Object, obj Object) {
+	keys := make([]string, 0, len(obj.Example))
+	for key := range obj.Example {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		var found bool
+		for _, field := range obj.Fields {
+			if field.JSONName == key {
+				found = true
+				break
+			}
+		}
+		if !found {
+			err := p.wrapErr(fmt.Errorf("%s: example: unknown field %q", obj.Name, key), pkg, o.Pos())
+			p.def.Warnings = append(p.def.Warnings, err.Error())
+		}
+	}
+}
+
+// parseRename parses the quoted name of an oto:rename comment line,
+// like `"HttpsProxy"`, which must be an identifier.
+func parseRename(value string) (string, error) {
+	name, err := strconv.Unquote(value)
+	if err != nil {
+		return "", errors.Errorf("oto:rename: expected a quoted name, like \"Name\", not %q", value)
+	}
+	if !token.IsIdentifier(name) {
+		return "", errors.Errorf("oto:rename: %q is not a valid name", name)
+	}
+	return name, nil
+}
+
+// parseOneOf parses the object names of an oto:oneof comment line,
+// like "Card,BankTransfer".
+func parseOneOf(value string) ([]string, error) {
+	if value == "" {
+		return nil, errors.New("oto:oneof: missing object names")
+	}
+	var names []string
+	for _, name := range strings.Split(value, ",") {
+		name = strings.TrimSpace(name)
+		if name == "" {
+			return nil, errors.Errorf("oto:oneof: empty object name in %q", value)
+		}
+		names = append(names, name)
+	}
+	return names, nil
+}
+
+// parseFieldType parses the type of obj, which is depth objects
+// deep (see MaxRecursionDepth).
+func (p *Parser) parseFieldType(pkg *packages.Package, obj types.Object, depth int) (FieldType, error) {
+	var ftype FieldType
+	if p.MaxRecursionDepth > 0 && depth > p.MaxRecursionDepth {
+		return ftype, p.wrapErr(errors.New("max recursion depth exceeded"), pkg, obj.Pos())
+	}
+	pkgPath := pkg.PkgPath
+	resolver := func(other *types.Package) string {
+		if other.Name() != pkg.Name {
+			if p.def.Imports == nil {
+				p.def.Imports = make(map[string]string)
+			}
+			p.def.Imports[other.Path()] = other.Name()
+			ftype.Package = other.Path()
+			pkgPath = other.Path()
+			return other.Name()
+		}
+		return "" // no package prefix
+	}
+	typ := obj.Type()
+	if pointer, ok := typ.(*types.Pointer); ok {
+		// pointers are nullable values of the type they point to
+		typ = pointer.Elem()
+		ftype.Nullable = true
+	}
+	if isBytesType(typ) {
+		ftype.IsBytes = true
+		ftype.TypeName = "[]byte"
+		ftype.ObjectName = "[]byte"
+		ftype.ObjectNameLowerCamel = "[]byte"
+		ftype.ObjectNameSnake = "[]byte"
+		ftype.ObjectNameKebab = "[]byte"
+		ftype.TypeID = "[]byte"
+		ftype.JSType = "string"
+		ftype.Format = "byte"
+		return ftype, nil
+	}
+	if slice, ok := typ.(*types.Slice); ok {
+		typ = slice.Elem()
+		ftype.Multiple = true
+		elementType, err := p.parseFieldType(pkg, types.NewVar(obj.Pos(), obj.Pkg(), obj.Name(), typ), depth)
+		if err != nil {
+			return ftype, err
+		}
+		ftype.ElementType = &elementType
+		if pointer, ok := typ.(*types.Pointer); ok {
+			// the ElementType is Nullable
+			typ = pointer.Elem()
+		}
+	}
+	ftype.IsFile = isFileType(typ)
+	if !ftype.IsFile {
+		if err := checkSupportedType(typ); err != nil {
+			return ftype, p.wrapErr(fmt.Errorf("%s: %s", obj.Name(), err), pkg, obj.Pos())
+		}
+	}
+	var scalar TypeOverride
+	var isScalar bool
+	if named, ok := types.Unalias(typ).(*types.Named); ok && named.Obj().Pkg() != nil && !ftype.IsFile {
+		scalar, isScalar = p.TypeOverrides[named.Obj().Pkg().Path()+"."+named.Obj().Name()]
+	}
+	var customJSType string
+	if !isScalar && !ftype.IsFile {
+		ftype.CustomMarshaler, customJSType = p.customMarshaler(typ)
+	}
+	if named, ok := typ.(*types.Named); ok && !isScalar && !ftype.CustomMarshaler && !ftype.IsFile {
 		if structure, ok := named.Underlying().(*types.Struct); ok {
-			if err := p.parseObject(pkg, named.Obj(), structure); err != nil {
+			if p.isSkippedObject(named.Obj()) {
+				// there would be no Object for the field
+				return ftype, p.wrapErr(fmt.Errorf("%s: %s has the oto:skip comment line, so it cannot be the type of a field", obj.Name(), named.Obj().Name()), pkg, obj.Pos())
+			}
+			if err := p.parseObject(pkg, named.Obj(), structure, depth+1); err != nil {
 				return ftype, err
 			}
-			ftype.IsObject = true
+			ftype.IsObject = true
+		}
+	}
+	if m, ok := typ.Underlying().(*types.Map); ok && !isScalar && !ftype.CustomMarshaler {
+		ftype.IsMap = true
+		mapKeyType, err := p.parseFieldType(pkg, types.NewVar(obj.Pos(), obj.Pkg(), obj.Name(), m.Key()), depth)
+		if err != nil {
+			return ftype, err
+		}
+		ftype.MapKeyType = &mapKeyType
+		mapValueType, err := p.parseFieldType(pkg, types.NewVar(obj.Pos(), obj.Pkg(), obj.Name(), m.Elem()), depth)
+		if err != nil {
+			return ftype, err
+		}
+		ftype.MapValueType = &mapValueType
+	}
+	ftype.TypeName = types.TypeString(typ, resolver)
+	ftype.ObjectName = types.TypeString(typ, func(other *types.Package) string { return "" })
+	ftype.ObjectNameLowerCamel = camelizeDown(ftype.ObjectName)
+	ftype.ObjectNameSnake = snakeDown(ftype.ObjectName)
+	ftype.ObjectNameKebab = kebabDown(ftype.ObjectName)
+	ftype.TypeID = pkgPath + "." + ftype.ObjectName
+	if name, ok := p.renamedObjects[ftype.TypeID]; ok && ftype.IsObject {
+		ftype.ObjectName = name
+		ftype.ObjectNameLowerCamel = camelizeDown(name)
+		ftype.ObjectNameSnake = snakeDown(name)
+		ftype.ObjectNameKebab = kebabDown(name)
+	}
+	if named, ok := types.Unalias(typ).(*types.Named); ok {
+		if basic, ok := named.Underlying().(*types.Basic); ok {
+			ftype.UnderlyingTypeName = basic.Name()
+			if !isScalar && !ftype.CustomMarshaler && p.parseEnum(named) {
+				ftype.IsEnum = true
+				ftype.EnumName = ftype.ObjectName
+			}
+		}
+	}
+	if ftype.IsFile {
+		ftype.JSType = "string"
+		ftype.Format = "binary"
+	} else if isScalar {
+		if scalar.TypeName != "" {
+			ftype.TypeName = scalar.TypeName
+		}
+		ftype.JSType = scalar.JSType
+		ftype.Format = scalar.Format
+		if scalar.Nullable {
+			ftype.Nullable = true
+		}
+	} else if ftype.CustomMarshaler {
+		ftype.JSType = customJSType
+		if ftype.JSType == "" {
+			ftype.JSType, ftype.JSTypeUnknown = fallbackJSType(typ)
+		}
+	} else if ftype.IsObject || ftype.IsMap {
+		ftype.JSType = "object"
+	} else if isNestedSlice(ftype) {
+		// the ElementType is the inner slice
+		ftype.JSType = ftype.ElementType.JSType
+		ftype.JSTypeUnknown = ftype.ElementType.JSTypeUnknown
+	} else {
+		switch ftype.TypeName {
+		case "interface{}", "any":
+			ftype.JSType = "any"
+		case "string":
+			ftype.JSType = "string"
+		case "bool":
+			ftype.JSType = "boolean"
+		case "int", "int8", "int16", "int32", "int64",
+			"uint", "uint8", "uint16", "uint32", "uint64", "uintptr",
+			"byte", "rune", "float32", "float64":
+			ftype.JSType = "number"
+		default:
+			ftype.JSType, ftype.JSTypeUnknown = fallbackJSType(typ)
+		}
+	}
+	if p.Int64AsString && !isScalar {
+		int64AsString(&ftype)
+	}
+	return ftype, nil
+}
+
+// isBytesType gets whether the type is an unnamed []byte
+// (or []uint8).
+func isBytesType(typ types.Type) bool {
+	slice, ok := types.Unalias(typ).(*types.Slice)
+	if !ok {
+		return false
+	}
+	basic, ok := types.Unalias(slice.Elem()).(*types.Basic)
+	return ok && basic.Kind() == types.Uint8
+}
+
+// int64AsString marks int64 and uint64 types, including slice
+// elements and map values, to be represented as strings.
+// The Go TypeName is unchanged.
+func int64AsString(ftype *FieldType) {
+	if ftype.ElementType != nil {
+		int64AsString(ftype.ElementType)
+	}
+	if ftype.MapValueType != nil {
+		int64AsString(ftype.MapValueType)
+	}
+	switch ftype.TypeName {
+	case "int64", "uint64":
+		ftype.JSType = "string"
+		ftype.Format = "int64"
+	}
+}
+
+// jsonMarshalerType and textMarshalerType are the json.Marshaler
+// and encoding.TextMarshaler interfaces.
+var (
+	jsonMarshalerType = newMarshalerType("MarshalJSON")
+	textMarshalerType = newMarshalerType("MarshalText")
+)
+
+// newMarshalerType makes an interface type with a single method
+// in the form: name() ([]byte, error)
+func newMarshalerType(name string) *types.Interface {
+	results := types.NewTuple(
+		types.NewVar(token.NoPos, nil, "", types.NewSlice(types.Typ[types.Byte])),
+		types.NewVar(token.NoPos, nil, "", types.Universe.Lookup("error").Type()),
+	)
+	signature := types.NewSignatureType(nil, nil, nil, nil, results, false)
+	method := types.NewFunc(token.NoPos, nil, name, signature)
+	return types.NewInterfaceType([]*types.Func{method}, nil).Complete()
+}
+
+// customMarshaler gets whether the type has its own MarshalJSON or
+// MarshalText method, and if so, the JSType it is encoded as (or
+// an empty string if it is not known).
+// An oto:jstype line in the comment of the type sets the JSType.
+func (p *Parser) customMarshaler(typ types.Type) (bool, string) {
+	named, ok := types.Unalias(typ).(*types.Named)
+	if !ok {
+		return false, ""
+	}
+	var jsType string
+	switch {
+	case implementsMarshaler(named, jsonMarshalerType):
+	case implementsMarshaler(named, textMarshalerType):
+		// encoding/json encodes these as strings
+		jsType = "string"
+	default:
+		return false, ""
+	}
+	if named.Obj().Pkg() != nil {
+		comment := p.commentForType(named.Obj().Pkg().Path(), named.Obj().Name())
+		if value, found, _ := extractDirective(comment, "oto:jstype"); found && value != "" {
+			jsType = value
+		}
+	}
+	return true, jsType
+}
+
+// parseEnum adds the named type to the Enums (if it is not
+// already there) when its package declares constants of the type.
+// It returns false if there are no constants.
+func (p *Parser) parseEnum(named *types.Named) bool {
+	typeName := named.Obj()
+	if typeName.Pkg() == nil {
+		return false
+	}
+	typeID := typeName.Pkg().Path() + "." + typeName.Name()
+	for _, enum := range p.def.Enums {
+		if enum.TypeID == typeID {
+			return true
+		}
+	}
+	var consts []*types.Const
+	scope := typeName.Pkg().Scope()
+	for _, name := range scope.Names() {
+		c, ok := scope.Lookup(name).(*types.Const)
+		if !ok || !types.Identical(c.Type(), named) {
+			continue
+		}
+		consts = append(consts, c)
+	}
+	if len(consts) == 0 {
+		return false
+	}
+	// scope names are sorted, but the values should be in the
+	// order they are declared
+	sort.Slice(consts, func(i, j int) bool {
+		return consts[i].Pos() < consts[j].Pos()
+	})
+	enum := Enum{
+		Name:    typeName.Name(),
+		TypeID:  typeID,
+		Comment: p.commentForType(typeName.Pkg().Path(), typeName.Name()),
+	}
+	enum.JSType, _ = fallbackJSType(named)
+	for _, c := range consts {
+		enum.Values = append(enum.Values, EnumValue{
+			Name:    c.Name(),
+			Value:   constantValue(c.Val()),
+			Comment: p.commentForConst(c),
+		})
+	}
+	p.def.Enums = append(p.def.Enums, enum)
+	return true
+}
+
+// constantValue gets the Go value of the constant, which is a
+// string, int64, float64 or bool.
+func constantValue(val constant.Value) interface{} {
+	switch val.Kind() {
+	case constant.String:
+		return constant.StringVal(val)
+	case constant.Bool:
+		return constant.BoolVal(val)
+	case constant.Int:
+		if v, exact := constant.Int64Val(val); exact {
+			return v
+		}
+		// too big for an int64 (like a large uint64)
+		v, _ := constant.Float64Val(val)
+		return v
+	case constant.Float:
+		v, _ := constant.Float64Val(val)
+		return v
+	}
+	return val.ExactString()
+}
+
+// implementsMarshaler gets whether the type, or a pointer to it,
+// implements the marshaler interface.
+func implementsMarshaler(typ types.Type, marshaler *types.Interface) bool {
+	return types.Implements(typ, marshaler) || types.Implements(types.NewPointer(typ), marshaler)
+}
+
+// checkSupportedType returns an error if values of the type
+// cannot be serialised.
+func checkSupportedType(typ types.Type) error {
+	typeName := types.TypeString(typ, nil)
+	switch underlying := typ.Underlying().(type) {
+	case *types.Interface:
+		if !underlying.Empty() {
+			return fmt.Errorf("interface type %s is not supported (only interface{} may be used)", typeName)
+		}
+	case *types.Chan, *types.Signature:
+		return fmt.Errorf("type %s is not supported (channels and funcs cannot be serialised)", typeName)
+	case *types.Basic:
+		switch underlying.Kind() {
+		case types.UnsafePointer, types.Complex64, types.Complex128:
+			return fmt.Errorf("type %s is not supported (cannot be serialised)", typeName)
+		}
+	}
+	return nil
+}
+
+// fallbackJSType gets the JSType for types not explicitly handled
+// by parseFieldType, by looking at the underlying basic type.
+// Unrecognized types are "any", and the second return
+// value will be true.
+func fallbackJSType(typ types.Type) (string, bool) {
+	basic, ok := typ.Underlying().(*types.Basic)
+	if !ok {
+		return "any", true
+	}
+	info := basic.Info()
+	switch {
+	case info&types.IsNumeric != 0:
+		return "number", false
+	case info&types.IsString != 0:
+		return "string", false
+	case info&types.IsBoolean != 0:
+		return "boolean", false
+	}
+	return "any", true
+}
+
+// isNullableObject gets whether the objects of an object FieldType
+// may be null. For []*User, it is the ElementType that is Nullable.
+func isNullableObject(ftype FieldType) bool {
+	if ftype.Multiple && ftype.ElementType != nil {
+		return ftype.ElementType.Nullable
+	}
+	return ftype.Nullable && !ftype.Multiple
+}
+
+// isNestedSlice gets whether the FieldType is a slice of slices,
+// like [][]string, in which case the ElementType describes the
+// inner slice.
+func isNestedSlice(ftype FieldType) bool {
+	return ftype.Multiple && ftype.ElementType != nil && ftype.ElementType.Multiple
+}
+
+// hasUnknownJSType gets whether the FieldType, or any slice element,
+// map key or map value inside it, has an unknown JSType.
+func hasUnknownJSType(ftype FieldType) bool {
+	if ftype.JSTypeUnknown {
+		return true
+	}
+	for _, inner := range []*FieldType{ftype.ElementType, ftype.MapKeyType, ftype.MapValueType} {
+		if inner != nil && hasUnknownJSType(*inner) {
+			return true
+		}
+	}
+	return false
+}
+
+// declaredBefore gets whether the item with the positions key a
+// is declared before b (see positions).
+func (p *Parser) declaredBefore(a, b string) bool {
+	posA, posB := p.positions[a], p.positions[b]
+	if posA.Filename != posB.Filename {
+		return posA.Filename < posB.Filename
+	}
+	return posA.Offset < posB.Offset
+}
+
+// sortBySourceOrder sorts the Services, their Methods and (unless
+// SortObjects is set) the Objects by where they are declared.
+// Methods from embedded interfaces are sorted by where they are
+// declared in the embedded interface.
+func (p *Parser) sortBySourceOrder() {
+	before := p.declaredBefore
+	sort.SliceStable(p.def.Services, func(i, j int) bool {
+		return before("service:"+p.def.Services[i].Name, "service:"+p.def.Services[j].Name)
+	})
+	for _, service := range p.def.Services {
+		methods := service.Methods
+		sort.SliceStable(methods, func(i, j int) bool {
+			return before("method:"+service.Name+"."+methods[i].Name, "method:"+service.Name+"."+methods[j].Name)
+		})
+	}
+	if !p.SortObjects {
+		sort.SliceStable(p.def.Objects, func(i, j int) bool {
+			return before("object:"+p.def.Objects[i].TypeID, "object:"+p.def.Objects[j].TypeID)
+		})
+	}
+}
+
+// sortFields sorts the Fields of the Object by name, if
+// SortFields is set.
+func (p *Parser) sortFields(obj *Object) {
+	if !p.SortFields {
+		return
+	}
+	sort.SliceStable(obj.Fields, func(i, j int) bool {
+		return obj.Fields[i].Name < obj.Fields[j].Name
+	})
+}
+
+// findUnreferencedObjects gets the names of the Objects that cannot
+// be reached from the input or output of any method, and do not
+// have the oto:used comment line.
+func findUnreferencedObjects(def *Definition) []string {
+	reachable := reachableObjects(def, methodObjects(def))
+	var unused []string
+	for _, object := range def.Objects {
+		if _, ok := reachable[object.TypeID]; ok || object.Used {
+			continue
+		}
+		unused = append(unused, object.Name)
+	}
+	return unused
+}
+
+// removeIgnoredObjects removes the Objects that are only used by
+// methods with the oto:ignore comment line (or interfaces that are
+// excluded or not included), keeping any that the other methods
+// use (directly or through the fields of other objects) or that
+// have the oto:used comment line.
+// Everything reachable from the ignored objects is marked, and then
+// swept unless it is reachable from the methods of the services, so
+// objects that are only used by the fields of ignored objects (at
+// any depth) are removed too.
+func (p *Parser) removeIgnoredObjects() {
+	if len(p.ignoredTypeIDs) == 0 {
+		return
+	}
+	roots := make([]FieldType, 0, len(p.ignoredTypeIDs))
+	for _, typeID := range p.ignoredTypeIDs {
+		roots = append(roots, FieldType{TypeID: typeID, IsObject: true})
+	}
+	ignored := reachableObjects(&p.def, roots)
+	used := reachableObjects(&p.def, methodObjects(&p.def))
+	objects := make([]Object, 0, len(p.def.Objects))
+	for _, object := range p.def.Objects {
+		_, isIgnored := ignored[object.TypeID]
+		_, isUsed := used[object.TypeID]
+		if isIgnored && !isUsed && !object.Used {
+			if p.Verbose {
+				fmt.Printf("skipping object %s (only used by ignored methods)\n", object.Name)
+			}
+			continue
 		}
+		objects = append(objects, object)
 	}
-	ftype.TypeName = types.TypeString(typ, resolver)
-	ftype.ObjectName = types.TypeString(typ, func(other *types.Package) string { return "" })
-	ftype.ObjectNameLowerCamel = camelizeDown(ftype.ObjectName)
-	ftype.TypeID = pkgPath + "." + ftype.ObjectName
-	if ftype.IsObject {
-		ftype.JSType = "object"
-	} else {
-		switch ftype.TypeName {
-		case "interface{}":
-			ftype.JSType = "any"
-		case "map[string]interface{}":
-			ftype.JSType = "object"
-		case "string":
-			ftype.JSType = "string"
-		case "bool":
-			ftype.JSType = "boolean"
-		case "int", "int16", "int32", "int64",
-			"uint", "uint16", "uint32", "uint64",
-			"float32", "float64":
-			ftype.JSType = "number"
+	p.def.Objects = objects
+}
+
+// removeUnusedEnums removes the Enums that are no longer used
+// by any Object, because the Objects were removed by
+// removeIgnoredObjects.
+func (p *Parser) removeUnusedEnums() {
+	used := make(map[string]struct{})
+	var visit func(ftype *FieldType)
+	visit = func(ftype *FieldType) {
+		if ftype == nil {
+			return
+		}
+		visit(ftype.ElementType)
+		visit(ftype.MapKeyType)
+		visit(ftype.MapValueType)
+		if ftype.IsEnum {
+			used[ftype.TypeID] = struct{}{}
+		}
+	}
+	for _, object := range p.def.Objects {
+		for i := range object.Fields {
+			visit(&object.Fields[i].Type)
 		}
 	}
+	enums := make([]Enum, 0, len(p.def.Enums))
+	for _, enum := range p.def.Enums {
+		if _, ok := used[enum.TypeID]; ok {
+			enums = append(enums, enum)
+		}
+	}
+	p.def.Enums = enums
+}
 
-	return ftype, nil
+// methodObjects gets the InputObject and OutputObject of every
+// method.
+func methodObjects(def *Definition) []FieldType {
+	var ftypes []FieldType
+	for _, service := range def.Services {
+		for _, method := range service.Methods {
+			ftypes = append(ftypes, method.InputObject, method.OutputObject)
+		}
+	}
+	return ftypes
+}
+
+// reachableObjects gets the TypeIDs of the Objects that can be
+// reached from the FieldTypes, through the types of their fields.
+func reachableObjects(def *Definition, ftypes []FieldType) map[string]struct{} {
+	objects := make(map[string]Object, len(def.Objects))
+	typeIDs := make(map[string]string, len(def.Objects))
+	for _, object := range def.Objects {
+		objects[object.TypeID] = object
+		typeIDs[object.Name] = object.TypeID
+	}
+	reachable := make(map[string]struct{})
+	var visit func(ftype *FieldType)
+	visit = func(ftype *FieldType) {
+		if ftype == nil {
+			return
+		}
+		visit(ftype.ElementType)
+		visit(ftype.MapKeyType)
+		visit(ftype.MapValueType)
+		for _, name := range ftype.OneOf {
+			if typeID, ok := typeIDs[name]; ok {
+				visit(&FieldType{TypeID: typeID, IsObject: true})
+			}
+		}
+		if !ftype.IsObject {
+			return
+		}
+		if _, ok := reachable[ftype.TypeID]; ok {
+			return
+		}
+		reachable[ftype.TypeID] = struct{}{}
+		for _, field := range objects[ftype.TypeID].Fields {
+			visit(&field.Type)
+		}
+	}
+	for i := range ftypes {
+		visit(&ftypes[i])
+	}
+	return reachable
+}
+
+// signatureTypeIDs gets the TypeIDs of the named types (or
+// pointers to them) that are parameters or results of the method.
+func signatureTypeIDs(method *types.Func) []string {
+	sig, ok := method.Type().(*types.Signature)
+	if !ok {
+		return nil
+	}
+	var typeIDs []string
+	for _, tuple := range []*types.Tuple{sig.Params(), sig.Results()} {
+		for i := 0; i < tuple.Len(); i++ {
+			typ := types.Unalias(tuple.At(i).Type())
+			if ptr, ok := typ.(*types.Pointer); ok {
+				typ = types.Unalias(ptr.Elem())
+			}
+			named, ok := typ.(*types.Named)
+			if !ok || named.Obj().Pkg() == nil {
+				continue
+			}
+			typeIDs = append(typeIDs, named.Obj().Pkg().Path()+"."+named.Obj().Name())
+		}
+	}
+	return typeIDs
+}
+
+// resolveFieldLocations sets the In of the fields of input objects
+// that have not got one, and adds warnings for path parameters that
+// are not path fields of the input object of the method.
+// Fields for path parameters are in the path (and are required), and
+// the other fields of the input objects of GET methods are in the
+// query.
+func (p *Parser) resolveFieldLocations() {
+	for _, service := range p.def.Services {
+		for _, method := range service.Methods {
+			var input *Object
+			if method.HasInput {
+				input, _ = p.def.Object(method.InputObject.ObjectName)
+			}
+			for _, param := range method.PathParams() {
+				var field *Field
+				if input != nil {
+					for i := range input.Fields {
+						if strings.EqualFold(input.Fields[i].Name, param) {
+							field = &input.Fields[i]
+							break
+						}
+					}
+				}
+				switch {
+				case field == nil:
+					p.def.Warnings = append(p.def.Warnings, fmt.Sprintf("%s.%s: path parameter %s is not a field of the input object", service.Name, method.Name, param))
+				case field.In == "" || field.In == "path":
+					field.In = "path"
+					// there is no path without it
+					field.Required = true
+				default:
+					p.def.Warnings = append(p.def.Warnings, fmt.Sprintf("%s.%s: path parameter %s is for field %s.%s, which is in the %s", service.Name, method.Name, param, input.Name, field.Name, field.In))
+				}
+			}
+			if input == nil || method.HTTPMethod != "GET" {
+				continue
+			}
+			for i := range input.Fields {
+				if input.Fields[i].In == "" {
+					input.Fields[i].In = "query"
+				}
+			}
+		}
+	}
+}
+
+// markMultipartMethods sets IsMultipart for the methods with file
+// fields in their input objects.
+func (p *Parser) markMultipartMethods() {
+	for i := range p.def.Services {
+		for j := range p.def.Services[i].Methods {
+			method := &p.def.Services[i].Methods[j]
+			if !method.HasInput {
+				continue
+			}
+			input, err := p.def.Object(method.InputObject.ObjectName)
+			if err != nil {
+				continue
+			}
+			for _, field := range input.Fields {
+				if field.Type.IsFile {
+					method.IsMultipart = true
+					break
+				}
+			}
+		}
+	}
+}
+
+// markInputOutputObjects sets IsInput and IsOutput for the objects
+// that are the input or output objects of methods.
+func (p *Parser) markInputOutputObjects() {
+	for _, service := range p.def.Services {
+		for _, method := range service.Methods {
+			if method.HasInput {
+				if input, err := p.def.Object(method.InputObject.ObjectName); err == nil {
+					input.IsInput = true
+				}
+			}
+			if method.HasOutput {
+				if output, err := p.def.Object(method.OutputObject.ObjectName); err == nil {
+					output.IsOutput = true
+				}
+			}
+		}
+	}
+}
+
+// checkMissingObjects returns an error if the input or output of
+// any method is not an Object in the Definition.
+func (p *Parser) checkMissingObjects() error {
+	for _, service := range p.def.Services {
+		for _, method := range service.Methods {
+			var ftypes []FieldType
+			if method.HasInput {
+				ftypes = append(ftypes, method.InputObject)
+			}
+			if method.HasOutput {
+				ftypes = append(ftypes, method.OutputObject)
+			}
+			for _, ftype := range ftypes {
+				if !ftype.IsObject {
+					return fmt.Errorf("%s.%s: %s is not an object (strict)", service.Name, method.Name, ftype.TypeName)
+				}
+				if _, err := p.def.Object(ftype.ObjectName); err != nil {
+					return fmt.Errorf("%s.%s: missing object %s (strict)", service.Name, method.Name, ftype.TypeName)
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// findMissingOneOfObjects describes the objects listed in
+// oto:oneof lines that are not in the Definition.
+func (p *Parser) findMissingOneOfObjects() []string {
+	var missing []string
+	for _, object := range p.def.Objects {
+		for _, field := range object.Fields {
+			for _, name := range field.Type.OneOf {
+				if _, err := p.def.Object(name); err != nil {
+					missing = append(missing, fmt.Sprintf("%s.%s: oto:oneof object %s not found", object.Name, field.Name, name))
+				}
+			}
+		}
+	}
+	return missing
+}
+
+// sensitiveOutputFields gets the Sensitive fields of the objects
+// that can be reached from the output objects of methods.
+// WriteOnly fields are never in responses, so they are skipped.
+func (p *Parser) sensitiveOutputFields() []FieldRef {
+	reachable := p.methodObjects(false)
+	var refs []FieldRef
+	for _, ref := range p.def.SensitiveFields() {
+		if ref.Field.WriteOnly {
+			continue
+		}
+		object, err := p.def.Object(ref.ObjectName)
+		if err != nil {
+			continue
+		}
+		if _, ok := reachable[object.TypeID]; ok {
+			refs = append(refs, ref)
+		}
+	}
+	return refs
+}
+
+// misplacedReadWriteOnlyFields describes the ReadOnly fields in
+// objects that can be reached from the input objects of methods
+// (which would be ignored), and the WriteOnly fields in objects that
+// can be reached from the output objects (which would be empty).
+func (p *Parser) misplacedReadWriteOnlyFields() []string {
+	inputs := p.methodObjects(true)
+	outputs := p.methodObjects(false)
+	var warnings []string
+	for _, object := range p.def.Objects {
+		_, isInput := inputs[object.TypeID]
+		_, isOutput := outputs[object.TypeID]
+		for _, field := range object.Fields {
+			if field.ReadOnly && isInput {
+				warnings = append(warnings, fmt.Sprintf("%s.%s is readonly, but it is in a request", object.Name, field.Name))
+			}
+			if field.WriteOnly && isOutput {
+				warnings = append(warnings, fmt.Sprintf("%s.%s is writeonly, but it is in a response", object.Name, field.Name))
+			}
+		}
+	}
+	return warnings
+}
+
+// methodObjects gets the TypeIDs of the objects that can be reached
+// from the input (or output) objects of methods.
+func (p *Parser) methodObjects(input bool) map[string]struct{} {
+	var ftypes []FieldType
+	for _, service := range p.def.Services {
+		for _, method := range service.Methods {
+			if input && method.HasInput {
+				ftypes = append(ftypes, method.InputObject)
+			}
+			if !input && method.HasOutput {
+				ftypes = append(ftypes, method.OutputObject)
+			}
+		}
+	}
+	return reachableObjects(&p.def, ftypes)
 }
 
 // addOutputFields adds built-in fields to the response objects
 // mentioned in p.outputObjects.
-func (p *parser) addOutputFields() error {
+// Methods without a response object are not included, and
+// templates should check Method.HasOutput to report errors
+// for them.
+func (p *Parser) addOutputFields() error {
 	errorField := Field{
 		OmitEmpty:      true,
 		Name:           "Error",
 		NameLowerCamel: "error",
+		NameSnake:      "error",
+		NameKebab:      "error",
+		JSONName:       "error",
 		Comment:        "Error is string explaining what went wrong. Empty if everything was fine.",
 		Type: FieldType{
 			TypeName: "string",
@@ -383,14 +2784,44 @@ func (p *parser) addOutputFields() error {
 			// skip if we can't find it - it must be excluded
 			continue
 		}
+		errorField.Index = len(obj.Fields)
 		obj.Fields = append(obj.Fields, errorField)
 	}
 	return nil
 }
 
-func (p *parser) wrapErr(err error, pkg *packages.Package, pos token.Pos) error {
+// wrapErr makes err a ParseError at pos.
+func (p *Parser) wrapErr(err error, pkg *packages.Package, pos token.Pos) *ParseError {
 	position := pkg.Fset.Position(pos)
-	return errors.Wrap(err, position.String())
+	return &ParseError{
+		File:  position.Filename,
+		Line:  position.Line,
+		Col:   position.Column,
+		Msg:   err.Error(),
+		Cause: err,
+	}
+}
+
+// recoverFrom adds err to the errors that Parse returns, in
+// RecoverOnError mode.
+func (p *Parser) recoverFrom(err error) {
+	if p.Verbose {
+		fmt.Printf("(recovering from error: %s) ", err)
+	}
+	var parseErr *ParseError
+	if !errors.As(err, &parseErr) {
+		p.errs = append(p.errs, &ParseError{Msg: err.Error(), Cause: err})
+		return
+	}
+	if err != error(parseErr) {
+		// keep the context the error was wrapped with, like
+		// "parse input object type"
+		wrapped := *parseErr
+		wrapped.Msg = strings.Replace(err.Error(), parseErr.Error(), parseErr.Msg, 1)
+		wrapped.Cause = err
+		parseErr = &wrapped
+	}
+	p.errs = append(p.errs, parseErr)
 }
 
 func isInSlice(slice []string, s string) bool {
@@ -402,25 +2833,92 @@ func isInSlice(slice []string, s string) bool {
 	return false
 }
 
-func (p *parser) lookupType(name string) *doc.Type {
-	for i := range p.docs.Types {
-		if p.docs.Types[i].Name == name {
-			return p.docs.Types[i]
+// packageVersion gets the version from the oto:version line in the
+// package comment, or else the value of the Version string constant.
+// It is empty if there is neither.
+func packageVersion(docs *doc.Package) (string, error) {
+	version, hasVersion, _ := extractDirective(docs.Doc, "oto:version")
+	if hasVersion {
+		if version == "" {
+			return "", errors.New("oto:version: missing version")
+		}
+		return version, nil
+	}
+	for _, value := range docs.Consts {
+		for _, spec := range value.Decl.Specs {
+			valueSpec, ok := spec.(*ast.ValueSpec)
+			if !ok {
+				continue
+			}
+			for i, name := range valueSpec.Names {
+				if name.Name != "Version" || i >= len(valueSpec.Values) {
+					continue
+				}
+				lit, ok := valueSpec.Values[i].(*ast.BasicLit)
+				if !ok || lit.Kind != token.STRING {
+					continue
+				}
+				return strconv.Unquote(lit.Value)
+			}
+		}
+	}
+	return "", nil
+}
+
+// packageDocs gets the docs for the package with the specified
+// path, or nil if they are not available.
+func (p *Parser) packageDocs(pkgPath string) *doc.Package {
+	if docs, ok := p.docs[pkgPath]; ok {
+		return docs
+	}
+	var docs *doc.Package
+	if pkg, ok := p.loadedPackages[pkgPath]; ok && len(pkg.Syntax) > 0 {
+		var err error
+		docs, err = doc.NewFromFiles(pkg.Fset, pkg.Syntax, pkgPath, doc.PreserveAST)
+		if err != nil {
+			// no comments for this package
+			docs = nil
+		}
+	}
+	p.docs[pkgPath] = docs
+	return docs
+}
+
+func (p *Parser) lookupType(pkgPath, name string) *doc.Type {
+	docs := p.packageDocs(pkgPath)
+	if docs == nil {
+		return nil
+	}
+	for i := range docs.Types {
+		if docs.Types[i].Name == name {
+			return docs.Types[i]
 		}
 	}
 	return nil
 }
 
-func (p *parser) commentForType(name string) string {
-	typ := p.lookupType(name)
+func (p *Parser) commentForType(pkgPath, name string) string {
+	typ := p.lookupType(pkgPath, name)
 	if typ == nil {
 		return ""
 	}
 	return cleanComment(typ.Doc)
 }
 
-func (p *parser) commentForMethod(service, method string) string {
-	typ := p.lookupType(service)
+// docForType gets the doc comment of the type, or nil.
+func (p *Parser) docForType(pkgPath, name string) *ast.CommentGroup {
+	typ := p.lookupType(pkgPath, name)
+	if typ == nil {
+		return nil
+	}
+	if spec, ok := typ.Decl.Specs[0].(*ast.TypeSpec); ok && spec.Doc != nil {
+		return spec.Doc
+	}
+	return typ.Decl.Doc
+}
+
+func (p *Parser) commentForMethod(pkgPath, service, method string) string {
+	typ := p.lookupType(pkgPath, service)
 	if typ == nil {
 		return ""
 	}
@@ -448,33 +2946,94 @@ outer:
 	return cleanComment(m.Doc.Text())
 }
 
-func (p *parser) commentForField(typeName, field string) string {
-	typ := p.lookupType(typeName)
-	if typ == nil {
+// commentForDeclaredMethod gets the comment for the method from
+// the interface it is declared in, which may be in another package.
+func (p *Parser) commentForDeclaredMethod(method *types.Func) string {
+	if method.Pkg() == nil {
+		return ""
+	}
+	docs := p.packageDocs(method.Pkg().Path())
+	if docs == nil {
+		return ""
+	}
+	for _, typ := range docs.Types {
+		spec, ok := typ.Decl.Specs[0].(*ast.TypeSpec)
+		if !ok {
+			continue
+		}
+		iface, ok := spec.Type.(*ast.InterfaceType)
+		if !ok {
+			continue
+		}
+		for _, field := range iface.Methods.List {
+			for _, name := range field.Names {
+				if name.Pos() == method.Pos() {
+					return cleanComment(field.Doc.Text())
+				}
+			}
+		}
+	}
+	return ""
+}
+
+// commentForConst gets the comment for the constant, or the
+// comment at the end of its line if it has none.
+func (p *Parser) commentForConst(c *types.Const) string {
+	docs := p.packageDocs(c.Pkg().Path())
+	if docs == nil {
 		return ""
 	}
+	values := append([]*doc.Value{}, docs.Consts...)
+	for _, typ := range docs.Types {
+		values = append(values, typ.Consts...)
+	}
+	for _, value := range values {
+		for _, spec := range value.Decl.Specs {
+			valueSpec, ok := spec.(*ast.ValueSpec)
+			if !ok {
+				continue
+			}
+			for _, name := range valueSpec.Names {
+				if name.Pos() != c.Pos() {
+					continue
+				}
+				if comment := cleanComment(valueSpec.Doc.Text()); comment != "" {
+					return comment
+				}
+				return cleanComment(valueSpec.Comment.Text())
+			}
+		}
+	}
+	return ""
+}
+
+func (p *Parser) commentForField(pkgPath, typeName, field string) string {
+	return cleanComment(p.docForField(pkgPath, typeName, field).Text())
+}
+
+// docForField gets the doc comment of the field of the struct type,
+// or nil.
+func (p *Parser) docForField(pkgPath, typeName, field string) *ast.CommentGroup {
+	typ := p.lookupType(pkgPath, typeName)
+	if typ == nil {
+		return nil
+	}
 	spec, ok := typ.Decl.Specs[0].(*ast.TypeSpec)
 	if !ok {
-		return ""
+		return nil
 	}
 	obj, ok := spec.Type.(*ast.StructType)
 	if !ok {
-		return ""
+		return nil
 	}
-	var f *ast.Field
-outer:
 	for i := range obj.Fields.List {
 		for _, name := range obj.Fields.List[i].Names {
 			if name.Name == field {
-				f = obj.Fields.List[i]
-				break outer
+				return obj.Fields.List[i].Doc
 			}
 		}
 	}
-	if f == nil {
-		return ""
-	}
-	return cleanComment(f.Doc.Text())
+	return nil
 }
 
 func cleanComment(s string) string {
@@ -484,29 +3043,214 @@ func cleanComment(s string) string {
 // extractExample extracts the example from the comment.
 // It returns a typed example, and the remaining
 // comment string.
-// The example should be on the last line.
 func extractExample(comment string) (interface{}, string, error) {
+	example, _, comment, err := extractCommentValue(comment, "example:")
+	return example, comment, err
+}
+
+// extractDefault extracts the default value from the default: line
+// of the comment, like extractExample. found is false if there is
+// no default, since the default may be null.
+func extractDefault(comment string) (interface{}, bool, string, error) {
+	return extractCommentValue(comment, "default:")
+}
+
+// extractCommentValue extracts the JSON value on the line of the
+// comment that starts with prefix (like "example:"), and returns the
+// remaining comment string. found is false if there is no such line,
+// or it has no value.
+// Values that are not complete on the first line continue on the
+// following lines, until a blank line or the end of the comment.
+func extractCommentValue(comment, prefix string) (value interface{}, found bool, rest string, err error) {
 	var lines []string
-	const exampleCommentPrefix = "example:"
-	s := bufio.NewScanner(strings.NewReader(comment))
-	for s.Scan() {
-		line := strings.TrimSpace(s.Text())
-		if strings.HasPrefix(line, exampleCommentPrefix) {
-			line = strings.TrimSpace(strings.TrimPrefix(line, exampleCommentPrefix))
-			if line == "" {
-				return nil, strings.Join(lines, "\n"), nil
+	commentLines := strings.Split(comment, "\n")
+	for i := 0; i < len(commentLines); i++ {
+		line := strings.TrimSpace(commentLines[i])
+		if strings.HasPrefix(line, prefix) && !found {
+			src := strings.TrimSpace(strings.TrimPrefix(line, prefix))
+			for !json.Valid([]byte(src)) && i+1 < len(commentLines) && strings.TrimSpace(commentLines[i+1]) != "" {
+				// keep the line as it is, so indentation inside
+				// the value survives
+				i++
+				src += "\n" + commentLines[i]
 			}
-			var val interface{}
-			if err := json.Unmarshal([]byte(line), &val); err != nil {
-				return nil, "", err
+			if src == "" {
+				continue
+			}
+			if err := json.Unmarshal([]byte(src), &value); err != nil {
+				return nil, false, "", err
+			}
+			found = true
+			continue
+		}
+		if line == "" {
+			continue
+		}
+		lines = append(lines, line)
+	}
+	return value, found, strings.Join(lines, "\n"), nil
+}
+
+// readExampleFile reads the JSON example in the file of an
+// example-file: comment line at pos. The path is relative to the
+// directory of the Go file.
+func readExampleFile(fset *token.FileSet, path string, pos token.Pos) (interface{}, error) {
+	if path == "" {
+		return nil, errors.New("example-file: missing path")
+	}
+	if !filepath.IsAbs(path) {
+		path = filepath.Join(filepath.Dir(fset.Position(pos).Filename), path)
+	}
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return nil, errors.Wrap(err, "example-file")
+	}
+	var example interface{}
+	if err := json.Unmarshal(b, &example); err != nil {
+		return nil, errors.Errorf("example-file: %s: invalid JSON: %s", path, err)
+	}
+	return example, nil
+}
+
+// commentLinePos gets the position of the first line of the comment
+// that starts with prefix, or pos if there is none.
+func commentLinePos(doc *ast.CommentGroup, prefix string, pos token.Pos) token.Pos {
+	if doc == nil {
+		return pos
+	}
+	for _, c := range doc.List {
+		text := strings.TrimSpace(strings.TrimPrefix(c.Text, "//"))
+		if strings.HasPrefix(text, prefix) {
+			return c.Pos()
+		}
+	}
+	return pos
+}
+
+// checkValueType returns an error if the value (decoded from JSON)
+// is not of the type ftype.
+func checkValueType(value interface{}, ftype FieldType) error {
+	if value == nil {
+		if ftype.Nullable || ftype.Multiple || ftype.IsMap || ftype.JSType == "any" {
+			return nil
+		}
+		return errors.Errorf("expected %s, got null", ftype.JSType)
+	}
+	if ftype.Multiple {
+		items, ok := value.([]interface{})
+		if !ok {
+			return errors.Errorf("expected an array, got %s", jsonTypeName(value))
+		}
+		itemType := ftype
+		itemType.Multiple = false
+		itemType.Nullable = false
+		for i, item := range items {
+			if err := checkValueType(item, itemType); err != nil {
+				return errors.Wrapf(err, "item %d", i)
 			}
-			return val, strings.Join(lines, "\n"), nil
 		}
+		return nil
+	}
+	var expected string
+	switch {
+	case ftype.IsObject || ftype.IsMap || len(ftype.OneOf) > 0:
+		expected = "object"
+	case ftype.JSType == "string", ftype.JSType == "boolean", ftype.JSType == "number":
+		expected = ftype.JSType
+	default:
+		// any
+		return nil
+	}
+	if actual := jsonTypeName(value); actual != expected {
+		return errors.Errorf("expected %s, got %s", expected, actual)
+	}
+	if n, ok := value.(float64); ok && isIntegerTypeName(ftype.TypeName) && n != float64(int64(n)) {
+		return errors.Errorf("expected an integer, got %v", n)
+	}
+	return nil
+}
+
+// jsonTypeName gets the JSON type of the value (decoded
+// from JSON).
+func jsonTypeName(value interface{}) string {
+	switch value.(type) {
+	case nil:
+		return "null"
+	case bool:
+		return "boolean"
+	case float64:
+		return "number"
+	case string:
+		return "string"
+	case []interface{}:
+		return "array"
+	}
+	return "object"
+}
+
+// deprecation gets whether the comment has a "Deprecated: "
+// paragraph (the Go convention), and the message in it.
+// Comment lines like example: or oto:nullable end the message too.
+func deprecation(comment string) (bool, string) {
+	var deprecated bool
+	var lines []string
+	for _, line := range strings.Split(comment, "\n") {
 		line = strings.TrimSpace(line)
+		if !deprecated {
+			if strings.HasPrefix(line, "Deprecated:") {
+				deprecated = true
+				lines = append(lines, strings.TrimSpace(strings.TrimPrefix(line, "Deprecated:")))
+			}
+			continue
+		}
 		if line == "" {
+			// end of the paragraph
+			break
+		}
+		if isCommentLineDirective(line) {
+			// the value may go on over the following lines
+			break
+		}
+		lines = append(lines, line)
+	}
+	return deprecated, strings.TrimSpace(strings.Join(lines, " "))
+}
+
+// commentLinePrefixes are the prefixes of the comment lines that
+// oto reads values from, which are not part of the text.
+var commentLinePrefixes = []string{
+	"oto:", "example:", "example-file:", "default:", "enum:",
+	"min:", "max:", "pattern:", "format:", "required:",
+}
+
+// isCommentLineDirective gets whether the comment line is one that
+// oto reads a value from, like "example: 42" or "oto:nullable".
+func isCommentLineDirective(line string) bool {
+	for _, prefix := range commentLinePrefixes {
+		if strings.HasPrefix(line, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// extractDirective finds the first line in the comment that starts
+// with the directive (e.g. "oto:nullable").
+// It returns the rest of that line, whether the directive was found,
+// and the remaining comment string without the directive line.
+func extractDirective(comment, directive string) (string, bool, string) {
+	var lines []string
+	var value string
+	var found bool
+	s := bufio.NewScanner(strings.NewReader(comment))
+	for s.Scan() {
+		line := strings.TrimSpace(s.Text())
+		if !found && (line == directive || strings.HasPrefix(line, directive+" ")) {
+			value = strings.TrimSpace(strings.TrimPrefix(line, directive))
+			found = true
 			continue
 		}
 		lines = append(lines, line)
 	}
-	return nil, strings.Join(lines, "\n"), nil
+	return value, found, strings.TrimSpace(strings.Join(lines, "\n"))
 }
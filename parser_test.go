@@ -1,6 +1,12 @@
-package main
+package oto
 
 import (
+	"bytes"
+	"fmt"
+	"go/types"
+	"path/filepath"
+	"regexp"
+	"strings"
 	"testing"
 
 	"github.com/matryer/is"
@@ -9,9 +15,9 @@ import (
 func TestParse(t *testing.T) {
 	is := is.New(t)
 	patterns := []string{"./testdata/services/pleasantries"}
-	parser := newParser(patterns...)
+	parser := NewParser(patterns...)
 	parser.ExcludeInterfaces = []string{"Ignorer"}
-	def, err := parser.parse()
+	def, err := parser.Parse()
 	is.NoErr(err)
 
 	is.Equal(def.PackageName, "pleasantries")
@@ -19,6 +25,8 @@ func TestParse(t *testing.T) {
 	is.Equal(def.Services[0].Name, "GreeterService")
 	is.Equal(def.Services[0].Comment, `GreeterService is a polite API.
 You will love it.`)
+	is.Equal(def.Services[0].PackageName, "pleasantries")
+	is.Equal(def.Services[0].PackagePath, "github.com/pacedotdev/oto/testdata/services/pleasantries")
 	is.Equal(len(def.Services[0].Methods), 2)
 	is.Equal(def.Services[0].Methods[0].Name, "GetGreetings")
 	is.Equal(def.Services[0].Methods[0].NameLowerCamel, "getGreetings")
@@ -75,6 +83,11 @@ You will love it.`)
 	is.Equal(greetOutputObject.Fields[0].Type.TypeName, "Greeting")
 	is.Equal(greetOutputObject.Fields[0].Type.Multiple, true)
 	is.Equal(greetOutputObject.Fields[0].Type.Package, "")
+	is.Equal(greetOutputObject.Fields[0].Type.JSType, "object")
+	is.Equal(greetOutputObject.Fields[0].Type.ElementType.IsObject, true)
+	is.Equal(greetOutputObject.Fields[0].Type.ElementType.ObjectName, "Greeting")
+	is.Equal(greetOutputObject.Fields[0].Type.ElementType.JSType, "object")
+	is.Equal(greetOutputObject.Fields[0].Type.ElementType.Multiple, false)
 	is.Equal(greetOutputObject.Fields[1].Name, "Error")
 	is.Equal(greetOutputObject.Fields[1].NameLowerCamel, "error")
 	is.Equal(greetOutputObject.Fields[1].OmitEmpty, true)
@@ -148,6 +161,9 @@ You will love it.`)
 			is.Equal(def.Objects[i].TypeID, "github.com/pacedotdev/oto/testdata/services.Page")
 			is.Equal(len(def.Objects[i].Fields), 3)
 			is.Equal(def.Objects[i].Imported, true)
+			// comments come from the imported package
+			is.Equal(def.Objects[i].Comment, "Page describes a page of data.")
+			is.Equal(def.Objects[i].Fields[0].Comment, "Cursor is the cursor to start at.")
 		}
 	}
 
@@ -184,3 +200,2141 @@ func TestExtractExample(t *testing.T) {
 	is.Equal(example, float64(123))
 
 }
+
+func TestParseObjectExamples(t *testing.T) {
+	is := is.New(t)
+
+	def, err := NewParser("./testdata/services/objectexamples").Parse()
+	is.NoErr(err)
+	obj, err := def.Object("SendRequest")
+	is.NoErr(err)
+	is.Equal(obj.Comment, "SendRequest is the request for ContactService.Send. Either the\nEmail or Phone must be set.")
+	is.Equal(obj.Example, map[string]interface{}{
+		"email":   "mat@example.com",
+		"message": "Hi",
+		"subject": "Hello",
+	})
+	is.Equal(obj.Fields[0].Example, "mat@example.com")
+	is.Equal(len(def.Warnings), 1)
+	is.True(strings.HasSuffix(def.Warnings[0], `objectexamples.go:12:6: SendRequest: example: unknown field "subject"`))
+	obj, err = def.Object("SendResponse")
+	is.NoErr(err)
+	is.Equal(obj.Example, nil)
+
+	spec, err := GenerateOpenAPI(def, nil)
+	is.NoErr(err)
+	schema := spec["components"].(map[string]interface{})["schemas"].(map[string]interface{})["SendRequest"].(map[string]interface{})
+	is.Equal(schema["example"].(map[string]interface{})["message"], "Hi")
+
+	_, err = NewParser("./testdata/services/errors/objectexamples").Parse()
+	is.True(err != nil)
+	is.True(strings.HasSuffix(err.Error(), "objectexamples.go:11:6: SendRequest: example: expected a JSON object"))
+}
+
+func TestParseMultilineExamples(t *testing.T) {
+	is := is.New(t)
+
+	def, err := NewParser("./testdata/services/multilineexamples").Parse()
+	is.NoErr(err)
+	obj, err := def.Object("PlaceRequest")
+	is.NoErr(err)
+	is.Equal(obj.Comment, "PlaceRequest is the request for OrderService.Place.\nOrders are placed right away.")
+	is.Equal(obj.Example["lines"], []interface{}{
+		map[string]interface{}{"sku": "ABC", "quantity": float64(2)},
+	})
+	is.Equal(obj.Fields[0].Example, map[string]interface{}{"name": "Mat", "email": "mat@example.com"})
+	is.Equal(obj.Fields[0].Comment, "Customer is who is placing the order.")
+	is.Equal(obj.Fields[1].Example, []interface{}{
+		map[string]interface{}{"sku": "ABC", "quantity": float64(2)},
+	})
+	is.Equal(obj.Fields[1].Comment, "Lines are the things being ordered.\nThe order must have at least one line.")
+	is.Equal(obj.Fields[2].Example, "Leave at the door")
+	is.Equal(obj.Fields[2].Comment, "Note is a note for the order.\nIt is optional.")
+
+	_, err = NewParser("./testdata/services/errors/multilineexamples").Parse()
+	is.True(err != nil)
+	// the position is the first line of the example
+	is.True(strings.Contains(err.Error(), "multilineexamples.go:12:2: PlaceRequest.Lines: example: invalid JSON: "))
+}
+
+func TestParseValidateExamples(t *testing.T) {
+	is := is.New(t)
+
+	_, err := NewParser("./testdata/services/errors/exampletypes").Parse()
+	is.True(err != nil)
+	is.True(strings.HasSuffix(err.Error(), "exampletypes.go:15:2: PlaceRequest.Quantity: example: expected number, got string"))
+
+	parser := NewParser("./testdata/services/errors/exampletypes")
+	parser.ValidateExamples = false
+	def, err := parser.Parse()
+	is.NoErr(err)
+	obj, err := def.Object("PlaceRequest")
+	is.NoErr(err)
+	is.Equal(obj.Fields[1].Example, "two")
+}
+
+func TestParseRecoverOnError(t *testing.T) {
+	is := is.New(t)
+
+	_, err := NewParser("./testdata/services/recovery").Parse()
+	is.True(err != nil) // fails on the first error
+	_, isList := err.(ParseErrorList)
+	is.True(!isList)
+
+	parser := NewParser("./testdata/services/recovery")
+	parser.RecoverOnError = true
+	def, err := parser.Parse()
+	errs, ok := err.(ParseErrorList)
+	is.True(ok)
+	is.Equal(len(errs), 3)
+	is.True(strings.HasSuffix(errs[0].File, "recovery.go"))
+	is.Equal(errs[0].Line, 12)
+	is.Equal(errs[0].Msg, "invalid method signature: expected Method(MethodRequest) MethodResponse")
+	is.Equal(errs[1].Line, 21)
+	is.Equal(errs[1].Msg, `GreetRequest.Polite: required: invalid value "maybe" (expected true or false)`)
+	is.Equal(errs[2].Line, 30)
+	is.True(strings.HasSuffix(errs[2].Msg, "secret must be exported"))
+
+	// the rest of the definition is still there
+	is.Equal(len(def.Services), 1)
+	is.Equal(def.Services[0].Name, "GreeterService")
+	request, err := def.Object("GreetRequest")
+	is.NoErr(err)
+	is.Equal(len(request.Fields), 2)
+	is.Equal(request.Fields[0].Name, "Name")
+	is.Equal(request.Fields[1].Name, "Times")
+	is.Equal(request.Fields[1].Index, 1)
+	response, err := def.Object("GreetResponse")
+	is.NoErr(err)
+	is.Equal(len(response.Fields), 2) // Greeting and Error
+
+	// without errors, it is nil
+	parser = NewParser("./testdata/services/pleasantries")
+	parser.RecoverOnError = true
+	_, err = parser.Parse()
+	is.NoErr(err)
+}
+
+func TestParseExampleFiles(t *testing.T) {
+	is := is.New(t)
+
+	def, err := NewParser("./testdata/services/examplefiles").Parse()
+	is.NoErr(err)
+	obj, err := def.Object("CreateRequest")
+	is.NoErr(err)
+	is.Equal(obj.Comment, "CreateRequest is the request for UserService.Create.")
+	is.Equal(obj.Example, map[string]interface{}{
+		"name":  "Mat",
+		"roles": []interface{}{"admin", "editor"},
+	})
+	is.Equal(obj.Fields[1].Example, []interface{}{"admin", "editor"})
+	is.Equal(obj.Fields[1].Comment, "Roles are what the user can do.")
+
+	_, err = NewParser("./testdata/services/errors/examplefile").Parse()
+	is.True(err != nil)
+	is.True(strings.Contains(err.Error(), "examplefile.go:12:2: CreateRequest.Name: example-file: open "))
+	is.True(strings.Contains(err.Error(), filepath.Join("errors", "examplefile", "missing.json")))
+
+	_, err = NewParser("./testdata/services/errors/examplefilejson").Parse()
+	is.True(err != nil)
+	is.True(strings.Contains(err.Error(), "examplefilejson.go:12:2: CreateRequest.Name: example-file: "))
+	is.True(strings.Contains(err.Error(), filepath.Join("errors", "examplefilejson", "invalid.json")+": invalid JSON: "))
+}
+
+func TestParseNullable(t *testing.T) {
+	is := is.New(t)
+	patterns := []string{"./testdata/services/nullable"}
+	parser := NewParser(patterns...)
+	def, err := parser.Parse()
+	is.NoErr(err)
+
+	obj, err := def.Object("UpdateRequest")
+	is.NoErr(err)
+	is.Equal(len(obj.Fields), 4)
+	is.Equal(obj.Fields[0].Name, "Nickname")
+	is.Equal(obj.Fields[0].Type.Nullable, true)
+	is.Equal(obj.Fields[1].Name, "Address")
+	is.Equal(obj.Fields[1].Type.Nullable, true)
+	is.Equal(obj.Fields[2].Name, "Bio")
+	is.Equal(obj.Fields[2].Type.Nullable, true)
+	is.Equal(obj.Fields[2].Comment, "Bio is the biography.")
+	is.Equal(obj.Fields[3].Name, "Name")
+	is.Equal(obj.Fields[3].Type.Nullable, false)
+}
+
+func TestParseInt64AsString(t *testing.T) {
+	is := is.New(t)
+	patterns := []string{"./testdata/services/int64s"}
+
+	def, err := NewParser(patterns...).Parse()
+	is.NoErr(err)
+	obj, err := def.Object("LookupRequest")
+	is.NoErr(err)
+	is.Equal(obj.Fields[0].Name, "ID")
+	is.Equal(obj.Fields[0].Type.JSType, "number")
+	is.Equal(obj.Fields[5].Name, "Token")
+	is.Equal(obj.Fields[5].Type.JSType, "string")
+	is.Equal(obj.Fields[5].Type.Format, "int64")
+	is.Equal(obj.Fields[5].Type.TypeName, "int64")
+	is.Equal(obj.Fields[5].Comment, "Token is a token.")
+
+	parser := NewParser(patterns...)
+	parser.Int64AsString = true
+	def, err = parser.Parse()
+	is.NoErr(err)
+	obj, err = def.Object("LookupRequest")
+	is.NoErr(err)
+	is.Equal(len(obj.Fields), 6)
+	is.Equal(obj.Fields[0].Type.JSType, "string") // ID
+	is.Equal(obj.Fields[0].Type.Format, "int64")
+	is.Equal(obj.Fields[0].Type.TypeName, "int64")
+	is.Equal(obj.Fields[1].Type.JSType, "string") // Unsigned
+	is.Equal(obj.Fields[1].Type.Format, "int64")
+	is.Equal(obj.Fields[2].Type.JSType, "string") // IDs
+	is.Equal(obj.Fields[2].Type.Multiple, true)
+	is.Equal(obj.Fields[2].Type.ElementType.JSType, "string")
+	is.Equal(obj.Fields[3].Type.JSType, "object") // Counts
+	is.Equal(obj.Fields[3].Type.MapValueType.JSType, "string")
+	is.Equal(obj.Fields[3].Type.MapValueType.Format, "int64")
+	is.Equal(obj.Fields[4].Type.JSType, "number") // Small
+	is.Equal(obj.Fields[4].Type.Format, "")
+	is.Equal(obj.Fields[5].Type.JSType, "string") // Token
+}
+
+func TestParseEmbeddedInterfaces(t *testing.T) {
+	is := is.New(t)
+	def, err := NewParser("./testdata/services/embedding").Parse()
+	is.NoErr(err)
+	is.Equal(len(def.Services), 3)
+
+	adminService := def.Services[0]
+	is.Equal(adminService.Name, "AdminService")
+	is.Equal(adminService.Embeds, []string{"UserService", "users.Finder"})
+	is.Equal(len(adminService.Methods), 3) // String is ignored
+	is.Equal(adminService.Methods[0].Name, "Ban")
+	is.Equal(adminService.Methods[0].Comment, "Ban bans a user.")
+	is.Equal(adminService.Methods[0].DeclaredIn, "AdminService")
+	is.Equal(adminService.Methods[1].Name, "Find")
+	is.Equal(adminService.Methods[1].InputObject.TypeName, "users.FindRequest")
+	is.Equal(adminService.Methods[1].DeclaredIn, "users.Finder")
+	is.Equal(adminService.Methods[2].Name, "GetUser")
+	is.Equal(adminService.Methods[2].Comment, "GetUser gets a user.")
+	is.Equal(adminService.Methods[2].DeclaredIn, "UserService")
+
+	auditedAdminService := def.Services[1]
+	is.Equal(auditedAdminService.Name, "AuditedAdminService")
+	is.Equal(auditedAdminService.Embeds, []string{"AdminService", "UserService"})
+	is.Equal(len(auditedAdminService.Methods), 3) // GetUser only appears once
+	// comments come from where the methods are declared
+	is.Equal(auditedAdminService.Methods[1].Comment, "Find finds users.")
+	is.Equal(auditedAdminService.Methods[2].Comment, "GetUser gets a user.")
+	// methods are declared in the innermost interface
+	is.Equal(auditedAdminService.Methods[0].DeclaredIn, "AdminService")
+	is.Equal(auditedAdminService.Methods[1].DeclaredIn, "users.Finder")
+	is.Equal(auditedAdminService.Methods[2].DeclaredIn, "UserService")
+
+	userService := def.Services[2]
+	is.Equal(userService.Name, "UserService")
+	is.Equal(len(userService.Embeds), 0)
+	is.Equal(len(userService.Methods), 1)
+	is.Equal(userService.Methods[0].DeclaredIn, "UserService")
+}
+
+func TestParseDuplicateServices(t *testing.T) {
+	is := is.New(t)
+	_, err := NewParser("./testdata/services/errors/duplicates/...").Parse()
+	is.True(err != nil)
+	is.True(strings.Contains(err.Error(), "b.go:4:6: duplicate service GreeterService (also declared at "))
+	is.True(strings.HasSuffix(err.Error(), "a.go:4:6)"))
+}
+
+func TestParseEmbeddedInterfaceErrors(t *testing.T) {
+	is := is.New(t)
+	_, err := NewParser("./testdata/services/errors/embedded").Parse()
+	is.True(err != nil)
+	is.True(strings.HasPrefix(err.Error(), "FileService: embedded interface Opener: "))
+	is.True(strings.Contains(err.Error(), "embedded.go:14:2: invalid method signature"))
+}
+
+func TestParsePointers(t *testing.T) {
+	is := is.New(t)
+	def, err := NewParser("./testdata/services/pointers").Parse()
+	is.NoErr(err)
+
+	obj, err := def.Object("UpdateRequest")
+	is.NoErr(err)
+	is.Equal(len(obj.Fields), 5)
+
+	members := obj.Fields[0].Type // []*User
+	is.Equal(members.TypeName, "User")
+	is.Equal(members.Multiple, true)
+	is.Equal(members.IsObject, true)
+	is.Equal(members.Nullable, false)
+	is.Equal(members.JSType, "object")
+	is.Equal(members.ElementType.TypeName, "User")
+	is.Equal(members.ElementType.IsObject, true)
+	is.Equal(members.ElementType.Nullable, true)
+
+	owners := obj.Fields[1].Type // *[]Owner
+	is.Equal(owners.TypeName, "Owner")
+	is.Equal(owners.Multiple, true)
+	is.Equal(owners.IsObject, true)
+	is.Equal(owners.Nullable, true)
+	is.Equal(owners.ElementType.Nullable, false)
+
+	leader := obj.Fields[2].Type // *Leader
+	is.Equal(leader.TypeName, "Leader")
+	is.Equal(leader.Multiple, false)
+	is.Equal(leader.IsObject, true)
+	is.Equal(leader.Nullable, true)
+
+	pages := obj.Fields[3].Type // []*services.Page
+	is.Equal(pages.TypeName, "services.Page")
+	is.Equal(pages.ObjectName, "Page")
+	is.Equal(pages.TypeID, "github.com/pacedotdev/oto/testdata/services.Page")
+	is.Equal(pages.Package, "github.com/pacedotdev/oto/testdata/services")
+	is.Equal(pages.Multiple, true)
+	is.Equal(pages.IsObject, true)
+	is.Equal(pages.ElementType.Nullable, true)
+	is.Equal(pages.ElementType.TypeName, "services.Page")
+	is.Equal(pages.ElementType.Package, "github.com/pacedotdev/oto/testdata/services")
+	is.Equal(def.Imports["github.com/pacedotdev/oto/testdata/services"], "services")
+
+	tags := obj.Fields[4].Type // *[]*string
+	is.Equal(tags.TypeName, "string")
+	is.Equal(tags.JSType, "string")
+	is.Equal(tags.Multiple, true)
+	is.Equal(tags.Nullable, true)
+	is.Equal(tags.ElementType.Nullable, true)
+
+	for _, name := range []string{"User", "Owner", "Leader", "Page"} {
+		_, err := def.Object(name)
+		is.NoErr(err) // object should be in the definition
+	}
+}
+
+func TestParseNestedContainers(t *testing.T) {
+	is := is.New(t)
+	def, err := NewParser("./testdata/services/containers").Parse()
+	is.NoErr(err)
+
+	getRequest, err := def.Object("GetRequest")
+	is.NoErr(err)
+	filters := getRequest.Fields[0].Type // []map[string]string
+	is.Equal(filters.Multiple, true)
+	is.Equal(filters.IsMap, true)
+	is.Equal(filters.TypeName, "map[string]string")
+	is.Equal(filters.ElementType.IsMap, true)
+	is.Equal(filters.ElementType.MapKeyType.TypeName, "string")
+	is.Equal(filters.ElementType.MapValueType.TypeName, "string")
+	is.Equal(filters.ElementType.MapValueType.JSType, "string")
+
+	grid := getRequest.Fields[1].Type // [][]string
+	is.Equal(grid.Multiple, true)
+	is.Equal(grid.TypeName, "[]string")
+	is.Equal(grid.JSType, "string")
+	is.Equal(grid.JSTypeUnknown, false)
+	is.Equal(grid.ElementType.Multiple, true)
+	is.Equal(grid.ElementType.JSType, "string")
+	is.Equal(grid.ElementType.ElementType.TypeName, "string")
+	timelines := getRequest.Fields[2].Type // [][]Event
+	is.Equal(timelines.JSType, "object")
+	is.Equal(timelines.JSTypeUnknown, false)
+	is.Equal(timelines.ElementType.IsObject, true)
+	is.Equal(timelines.ElementType.ElementType.TypeName, "Event")
+
+	// nested slices are fine in strict mode
+	parser := NewParser("./testdata/services/containers")
+	parser.Strict = true
+	parser.StrictChecks = StrictChecks{UnknownJSTypes: true}
+	_, err = parser.Parse()
+	is.NoErr(err)
+
+	typeScript, err := GenerateTypeScript(def)
+	is.NoErr(err)
+	is.True(strings.Contains(typeScript, "grid: string[][];"))
+	is.True(strings.Contains(typeScript, "timelines: Event[][];"))
+	spec, err := GenerateOpenAPI(def, nil)
+	is.NoErr(err)
+	gridSchema := spec["components"].(map[string]interface{})["schemas"].(map[string]interface{})["GetRequest"].(map[string]interface{})["properties"].(map[string]interface{})["grid"].(map[string]interface{})
+	is.Equal(gridSchema["items"], map[string]interface{}{
+		"type":  "array",
+		"items": map[string]interface{}{"type": "string"},
+	})
+	python, err := GeneratePython(def)
+	is.NoErr(err)
+	is.True(strings.Contains(python, "grid: List[List[str]]"))
+
+	getResponse, err := def.Object("GetResponse")
+	is.NoErr(err)
+	eventsByDay := getResponse.Fields[0].Type // map[string][]Event
+	is.Equal(eventsByDay.IsMap, true)
+	is.Equal(eventsByDay.Multiple, false)
+	is.Equal(eventsByDay.MapKeyType.JSType, "string")
+	is.Equal(eventsByDay.MapValueType.Multiple, true)
+	is.Equal(eventsByDay.MapValueType.TypeName, "Event")
+	is.Equal(eventsByDay.MapValueType.IsObject, true)
+	is.Equal(eventsByDay.MapValueType.ElementType.TypeName, "Event")
+
+	// map[string][]map[string]*Detail
+	grouped := getResponse.Fields[1].Type
+	is.Equal(grouped.IsMap, true)
+	is.Equal(grouped.MapValueType.Multiple, true)
+	is.Equal(grouped.MapValueType.IsMap, true)
+	inner := grouped.MapValueType.ElementType
+	is.Equal(inner.IsMap, true)
+	is.Equal(inner.MapKeyType.TypeName, "string")
+	is.Equal(inner.MapValueType.TypeName, "Detail")
+	is.Equal(inner.MapValueType.IsObject, true)
+	is.Equal(inner.MapValueType.Nullable, true)
+	is.Equal(inner.MapValueType.TypeID, "github.com/pacedotdev/oto/testdata/services/containers.Detail")
+
+	pages := getResponse.Fields[2].Type // map[int][]*services.Page
+	is.Equal(pages.MapKeyType.TypeName, "int")
+	is.Equal(pages.MapKeyType.JSType, "number")
+	is.Equal(pages.MapValueType.TypeName, "services.Page")
+	is.Equal(pages.MapValueType.IsObject, true)
+	is.Equal(pages.MapValueType.ElementType.Nullable, true)
+
+	// all structs reachable through the containers are objects
+	for _, name := range []string{"Event", "Detail", "Page"} {
+		_, err := def.Object(name)
+		is.NoErr(err)
+	}
+}
+
+func TestParseStrict(t *testing.T) {
+	is := is.New(t)
+
+	parser := NewParser("./testdata/services/pleasantries")
+	parser.ExcludeInterfaces = []string{"Ignorer"}
+	_, err := parser.Parse()
+	is.NoErr(err) // not strict
+
+	parser = NewParser("./testdata/services/pleasantries")
+	parser.ExcludeInterfaces = []string{"Ignorer"}
+	parser.Strict = true
+	_, err = parser.Parse()
+	is.True(err != nil)
+	is.True(strings.HasPrefix(err.Error(), "parse type of GetGreetingsRequest.Page: "))
+	is.True(strings.HasSuffix(err.Error(), "paging.go:6:2: Page.Cursor has no example (strict)"))
+
+	parser = NewParser("./testdata/services/pleasantries")
+	parser.Strict = true
+	parser.StrictChecks = StrictChecks{MethodComments: true}
+	_, err = parser.Parse()
+	is.True(err != nil)
+	is.True(strings.HasSuffix(err.Error(), "Ignorer.Ignore has no comment (strict)"))
+
+	parser = NewParser("./testdata/services/pleasantries")
+	parser.ExcludeInterfaces = []string{"Ignorer"}
+	parser.Strict = true
+	parser.StrictChecks = StrictChecks{MethodComments: true, MissingObjects: true}
+	_, err = parser.Parse()
+	is.NoErr(err)
+
+	parser = NewParser("./testdata/services/jstypes")
+	parser.Strict = true
+	parser.StrictChecks = StrictChecks{UnknownJSTypes: true}
+	_, err = parser.Parse()
+	is.True(err != nil)
+	is.True(strings.HasSuffix(err.Error(), "CheckRequest.Pair: unknown JSType for [2]string (strict)"))
+
+	parser = NewParser("./testdata/services/errors/strictmissing")
+	parser.Strict = true
+	parser.StrictChecks = StrictChecks{MissingObjects: true}
+	_, err = parser.Parse()
+	is.True(err != nil)
+	is.Equal(err.Error(), "LookupService.Lookup: string is not an object (strict)")
+}
+
+func TestParseCycles(t *testing.T) {
+	is := is.New(t)
+	def, err := NewParser("./testdata/services/cycles").Parse()
+	is.NoErr(err)
+
+	for name, circular := range map[string]bool{
+		"GetRequest":  false,
+		"GetResponse": false,
+		"Author":      true,
+		"Book":        true,
+		"Tree":        false,
+		"Node":        true,
+	} {
+		obj, err := def.Object(name)
+		is.NoErr(err)
+		is.Equal(obj.Circular, circular) // Circular
+	}
+	is.Equal(len(def.Objects), 6)
+	author, err := def.Object("Author")
+	is.NoErr(err)
+	is.Equal(author.Fields[0].Type.TypeName, "Book")
+	is.Equal(author.Fields[0].Type.IsObject, true)
+	node, err := def.Object("Node")
+	is.NoErr(err)
+	is.Equal(node.Fields[0].Type.TypeName, "Node")
+	is.Equal(node.Fields[0].Type.Multiple, true)
+}
+
+func TestParseCustomMarshalers(t *testing.T) {
+	is := is.New(t)
+	def, err := NewParser("./testdata/services/marshalers").Parse()
+	is.NoErr(err)
+
+	obj, err := def.Object("PaintRequest")
+	is.NoErr(err)
+	is.Equal(len(obj.Fields), 6)
+	for _, field := range obj.Fields {
+		is.Equal(field.Type.CustomMarshaler, true) // CustomMarshaler
+		is.Equal(field.Type.IsObject, false)
+	}
+	color := obj.Fields[0].Type
+	is.Equal(color.TypeName, "Color")
+	is.Equal(color.JSType, "any") // can't know
+	is.Equal(color.JSTypeUnknown, true)
+	colors := obj.Fields[1].Type
+	is.Equal(colors.Multiple, true)
+	is.Equal(colors.ElementType.CustomMarshaler, true)
+	is.Equal(obj.Fields[2].Type.JSType, "string") // Status with oto:jstype
+	is.Equal(obj.Fields[3].Type.JSType, "number") // Priority guessed from int
+	is.Equal(obj.Fields[4].Type.JSType, "string") // Level is a TextMarshaler
+	is.Equal(obj.Fields[5].Type.JSType, "string") // Version with oto:jstype
+	is.Equal(obj.Fields[5].Type.JSTypeUnknown, false)
+
+	// custom marshalers are not objects
+	for _, name := range []string{"Color", "Level", "Version"} {
+		_, err := def.Object(name)
+		is.Equal(err, errNotFound)
+	}
+
+	// the typemap takes precedence
+	parser := NewParser("./testdata/services/marshalers")
+	parser.TypeOverrides["github.com/pacedotdev/oto/testdata/services/marshalers.Color"] = TypeOverride{JSType: "string", Format: "color"}
+	def, err = parser.Parse()
+	is.NoErr(err)
+	obj, err = def.Object("PaintRequest")
+	is.NoErr(err)
+	is.Equal(obj.Fields[0].Type.JSType, "string")
+	is.Equal(obj.Fields[0].Type.Format, "color")
+	is.Equal(obj.Fields[0].Type.CustomMarshaler, false)
+}
+
+func TestParseVariadicMethodError(t *testing.T) {
+	is := is.New(t)
+	_, err := NewParser("./testdata/services/errors/variadic").Parse()
+	is.True(err != nil)
+	is.True(strings.HasSuffix(err.Error(), "variadic.go:6:2: SearchService.Search: variadic parameters are not supported: expected Method(MethodRequest) MethodResponse"))
+}
+
+func TestParseUnusedObjects(t *testing.T) {
+	is := is.New(t)
+
+	def, err := NewParser("./testdata/services/unused").Parse()
+	is.NoErr(err)
+	is.Equal(def.Warnings, []string{"object Orphan is not used by any service"})
+	is.Equal(findUnreferencedObjects(&def), []string{"Orphan"})
+	kept, err := def.Object("Kept")
+	is.NoErr(err)
+	is.Equal(kept.Used, true)
+	is.Equal(kept.Comment, "Kept is not used by any service, but is kept.")
+
+	parser := NewParser("./testdata/services/unused")
+	parser.FailOnUnused = true
+	_, err = parser.Parse()
+	is.True(err != nil)
+	is.Equal(err.Error(), "objects not used by any service: Orphan (add oto:used to their comments to allow this)")
+
+	parser = NewParser("./testdata/services/unused")
+	parser.Strict = true
+	parser.StrictChecks = StrictChecks{UnusedObjects: true}
+	_, err = parser.Parse()
+	is.True(err != nil)
+
+	def, err = NewParser("./testdata/services/pleasantries").Parse()
+	is.NoErr(err)
+	is.Equal(len(def.Warnings), 0)
+}
+
+func TestParseConstraintInterfaces(t *testing.T) {
+	is := is.New(t)
+
+	def, err := NewParser("./testdata/services/constraints").Parse()
+	is.NoErr(err)
+	is.Equal(len(def.Services), 1) // constraint interfaces are skipped
+	is.Equal(def.Services[0].Name, "CalculatorService")
+	is.Equal(len(def.Services[0].Methods), 1)
+}
+
+func TestParseContextParams(t *testing.T) {
+	is := is.New(t)
+
+	def, err := NewParser("./testdata/services/contexts").Parse()
+	is.NoErr(err)
+	is.Equal(len(def.Services), 1)
+	methods := def.Services[0].Methods
+	is.Equal(len(methods), 3)
+	is.Equal(methods[1].Name, "Search")
+	is.Equal(methods[1].HasContext, true)
+	is.Equal(methods[1].InputObject.TypeName, "SearchRequest")
+	is.Equal(methods[2].Name, "Suggest")
+	is.Equal(methods[2].HasContext, false)
+	is.Equal(methods[2].InputObject.TypeName, "SuggestRequest")
+	for _, object := range def.Objects {
+		is.True(object.Name != "Context") // the context is not an object
+	}
+}
+
+func TestParseErrorResults(t *testing.T) {
+	is := is.New(t)
+
+	def, err := NewParser("./testdata/services/contexts").Parse()
+	is.NoErr(err)
+	methods := def.Services[0].Methods
+	is.Equal(methods[0].Name, "Save")
+	is.Equal(methods[0].ReturnsError, true)
+	is.Equal(methods[0].HasContext, true)
+	is.Equal(methods[0].OutputObject.TypeName, "SaveResponse")
+	is.Equal(methods[1].Name, "Search")
+	is.Equal(methods[1].ReturnsError, false)
+	is.Equal(methods[1].OutputObject.TypeName, "SearchResponse")
+
+	_, err = NewParser("./testdata/services/errors/results").Parse()
+	is.True(err != nil)
+	is.True(strings.HasSuffix(err.Error(), "results.go:6:2: invalid method signature: expected Method(MethodRequest) MethodResponse"))
+}
+
+func TestParseMultiplePackages(t *testing.T) {
+	is := is.New(t)
+
+	def, err := NewParser("./testdata/services/pleasantries", "./testdata/services/contexts").Parse()
+	is.NoErr(err)
+	is.Equal(def.PackageName, "pleasantries")
+	var comments []string
+	for _, service := range def.Services {
+		comments = append(comments, service.Comment)
+	}
+	is.Equal(comments, []string{
+		"GreeterService is a polite API.\nYou will love it.",
+		"Ignorer gets ignored by the tooling.",
+		"SearchService searches for things.",
+		"Welcomer welcomes people.",
+	})
+	search, err := def.Object("SearchRequest")
+	is.NoErr(err)
+	is.Equal(search.Comment, "SearchRequest is the request for SearchService.Search.")
+	welcome, err := def.Object("WelcomeRequest")
+	is.NoErr(err)
+	is.Equal(welcome.Fields[0].Comment, "To is the address of the person to send the message to.")
+
+	def, err = NewParser("./testdata/services/contexts", "./testdata/services/pleasantries").Parse()
+	is.NoErr(err)
+	is.Equal(def.PackageName, "contexts")
+}
+
+func TestParseMethodsWithoutPayloads(t *testing.T) {
+	is := is.New(t)
+
+	parser := NewParser("./testdata/services/payloads")
+	parser.Strict = true
+	parser.StrictChecks = StrictChecks{MissingObjects: true}
+	def, err := parser.Parse()
+	is.NoErr(err)
+	methods := def.Services[0].Methods
+	is.Equal(len(methods), 4)
+	is.Equal(methods[0].Name, "Clear")
+	is.Equal(methods[0].HasInput, false)
+	is.Equal(methods[0].HasOutput, false)
+	is.Equal(methods[0].HasContext, true)
+	is.Equal(methods[0].ReturnsError, true)
+	is.Equal(methods[1].Name, "Ping")
+	is.Equal(methods[1].HasInput, false)
+	is.Equal(methods[1].HasOutput, false)
+	is.Equal(methods[1].InputObject, FieldType{})
+	is.Equal(methods[1].OutputObject, FieldType{})
+	is.Equal(methods[2].Name, "Reset")
+	is.Equal(methods[2].HasInput, true)
+	is.Equal(methods[2].HasOutput, false)
+	is.Equal(methods[2].InputObject.TypeName, "ResetRequest")
+	is.Equal(methods[3].Name, "Status")
+	is.Equal(methods[3].HasInput, false)
+	is.Equal(methods[3].HasOutput, true)
+	is.Equal(methods[3].OutputObject.TypeName, "StatusResponse")
+	status, err := def.Object("StatusResponse")
+	is.NoErr(err)
+	is.Equal(status.Fields[len(status.Fields)-1].Name, "Error")
+	reset, err := def.Object("ResetRequest")
+	is.NoErr(err)
+	is.Equal(len(reset.Fields), 1)
+}
+
+func TestParseSynthesizeRequests(t *testing.T) {
+	is := is.New(t)
+
+	_, err := NewParser("./testdata/services/synthesized").Parse()
+	is.True(err != nil) // multiple parameters need SynthesizeRequests
+	is.True(strings.HasSuffix(err.Error(), "synthesized.go:8:2: invalid method signature: expected Method(MethodRequest) MethodResponse"))
+
+	parser := NewParser("./testdata/services/synthesized")
+	parser.SynthesizeRequests = true
+	def, err := parser.Parse()
+	is.NoErr(err)
+	get := def.Services[0].Methods[1]
+	is.Equal(get.Name, "Get")
+	is.Equal(get.HasContext, true)
+	is.Equal(get.HasInput, true)
+	is.Equal(get.InputObject.TypeName, "GetRequest")
+	is.Equal(get.InputObject.IsObject, true)
+	is.Equal(get.InputObject.TypeID, "github.com/pacedotdev/oto/testdata/services/synthesized.GetRequest")
+	getRequest, err := def.Object("GetRequest")
+	is.NoErr(err)
+	is.Equal(getRequest.TypeID, get.InputObject.TypeID)
+	is.Equal(len(getRequest.Fields), 4)
+	is.Equal(getRequest.Fields[0].Name, "Id")
+	is.Equal(getRequest.Fields[0].NameLowerCamel, "id")
+	is.Equal(getRequest.Fields[0].Type.JSType, "string")
+	is.Equal(getRequest.Fields[1].Name, "IncludeDeleted")
+	is.Equal(getRequest.Fields[1].NameLowerCamel, "includeDeleted")
+	is.Equal(getRequest.Fields[1].Type.JSType, "boolean")
+	is.Equal(getRequest.Fields[2].Name, "Tags")
+	is.Equal(getRequest.Fields[2].Type.Multiple, true)
+	is.Equal(getRequest.Fields[2].Comment, "")
+	is.Equal(getRequest.Fields[3].Name, "Ünits") // not just the first byte
+	is.Equal(getRequest.Fields[3].NameLowerCamel, "ünits")
+	is.Equal(def.Services[0].Methods[0].InputObject.TypeName, "DeleteRequest")
+	// the fields are at their parameters
+	var buf bytes.Buffer
+	WriteLintIssues(&buf, []LintIssue{{Severity: "error", Path: "GetRequest.IncludeDeleted", Message: "has no comment"}}, []*Parser{parser})
+	is.True(strings.HasSuffix(buf.String(), "synthesized.go:8:38: error: GetRequest.IncludeDeleted: has no comment\n"))
+
+	parser = NewParser("./testdata/services/errors/synthesized")
+	parser.SynthesizeRequests = true
+	_, err = parser.Parse()
+	is.True(err != nil)
+	is.True(strings.HasSuffix(err.Error(), "synthesized.go:6:2: ThingService.Get: cannot make request object GetRequest because the name is already used"))
+}
+
+func TestParseHTTPMethods(t *testing.T) {
+	is := is.New(t)
+
+	def, err := NewParser("./testdata/services/routes").Parse()
+	is.NoErr(err)
+	methods := def.Services[1].Methods
+	is.Equal(methods[0].Name, "Create")
+	is.Equal(methods[0].HTTPMethod, "POST") // default
+	is.Equal(methods[0].HTTPPath, "")
+	is.Equal(methods[1].Name, "Delete")
+	is.Equal(methods[1].HTTPMethod, "DELETE")
+	is.Equal(methods[1].HTTPPath, "/users/:id")
+	is.Equal(methods[1].Comment, "Delete deletes a user.")
+	is.Equal(methods[2].Name, "Fetch")
+	is.Equal(methods[2].HTTPMethod, "POST") // unknown verbs are warnings
+	is.Equal(methods[2].Comment, "Fetch fetches a user.")
+	is.Equal(methods[3].Name, "Get")
+	is.Equal(methods[3].HTTPMethod, "GET")
+	is.Equal(methods[3].Comment, "Get gets a user.")
+	is.Equal(len(def.Warnings), 2)
+	is.True(strings.HasSuffix(def.Warnings[0], `routes.go:12:2: UserService.Fetch: unknown HTTP method "FETCH" in oto:method (using POST)`))
+}
+
+func TestParsePaths(t *testing.T) {
+	is := is.New(t)
+
+	def, err := NewParser("./testdata/services/routes").Parse()
+	is.NoErr(err)
+	methods := def.Services[1].Methods
+	is.Equal(methods[0].Name, "Create")
+	is.Equal(methods[0].Path, "/UserService/Create") // default
+	is.Equal(len(methods[0].PathParams()), 0)
+	is.Equal(methods[1].Name, "Delete")
+	is.Equal(methods[1].Path, "/users/:id") // from oto:route
+	is.Equal(methods[1].PathParams(), []string{"id"})
+	is.Equal(methods[4].Name, "Update")
+	is.Equal(methods[4].Path, "/users/{id}/{version}")
+	is.Equal(methods[4].Comment, "Update updates a user.")
+	is.Equal(methods[4].PathParams(), []string{"id", "version"})
+	is.Equal(def.Warnings[1], "UserService.Update: path parameter version is not a field of the input object")
+}
+
+func TestParseRoutePrefix(t *testing.T) {
+	is := is.New(t)
+
+	def, err := NewParser("./testdata/services/routes").Parse()
+	is.NoErr(err)
+	teams := def.Services[0]
+	is.Equal(teams.Name, "TeamService")
+	is.Equal(teams.RoutePrefix, "/api/v2")
+	is.Equal(teams.Comment, "TeamService manages teams.")
+	is.Equal(teams.Methods[0].Name, "Create")
+	is.Equal(teams.Methods[0].Path, "/api/v2/TeamService/Create")
+	is.Equal(teams.Methods[1].Name, "Get")
+	is.Equal(teams.Methods[1].Path, "/api/v2/teams/:id")
+	is.Equal(teams.Methods[1].HTTPPath, "/teams/:id")
+	is.Equal(def.Services[1].RoutePrefix, "")
+}
+
+func TestParseFieldLocations(t *testing.T) {
+	is := is.New(t)
+
+	def, err := NewParser("./testdata/services/locations").Parse()
+	is.NoErr(err)
+	is.Equal(len(def.Warnings), 0)
+	getRequest, err := def.Object("GetRequest")
+	is.NoErr(err)
+	is.Equal(getRequest.Fields[0].In, "path")
+	is.Equal(getRequest.Fields[1].In, "header")
+	is.Equal(getRequest.Fields[1].Comment, "Token is the auth token.")
+	is.Equal(getRequest.Fields[2].In, "query") // GET default
+	updateRequest, err := def.Object("UpdateRequest")
+	is.NoErr(err)
+	is.Equal(updateRequest.Fields[0].In, "path")
+	is.Equal(updateRequest.Fields[0].OmitEmpty, true)
+	is.Equal(updateRequest.Fields[0].Required, true) // path parameters are required
+	is.Equal(updateRequest.Fields[1].In, "query")
+	is.Equal(updateRequest.Fields[2].In, "cookie")
+	is.Equal(updateRequest.Fields[3].In, "") // body
+
+	_, err = NewParser("./testdata/services/errors/location").Parse()
+	is.True(err != nil)
+	is.True(strings.HasSuffix(err.Error(), `location.go:11:2: GetRequest.OrderID: unknown location "form" (expected body, query, header, path or cookie)`))
+}
+
+func TestParseSortFields(t *testing.T) {
+	is := is.New(t)
+
+	parser := NewParser("./testdata/services/pleasantries")
+	parser.ExcludeInterfaces = []string{"Ignorer"}
+	parser.AddErrorField = false
+	unsorted, err := parser.Parse()
+	is.NoErr(err)
+	parser = NewParser("./testdata/services/pleasantries")
+	parser.ExcludeInterfaces = []string{"Ignorer"}
+	parser.AddErrorField = false
+	parser.SortFields = true
+	def, err := parser.Parse()
+	is.NoErr(err)
+	is.Equal(len(def.Objects), len(unsorted.Objects))
+	var reordered bool
+	for i, object := range def.Objects {
+		is.Equal(object.Name, unsorted.Objects[i].Name) // objects keep their order
+		is.Equal(len(object.Fields), len(unsorted.Objects[i].Fields))
+		for j := range object.Fields {
+			if j > 0 {
+				is.True(object.Fields[j-1].Name <= object.Fields[j].Name)
+			}
+			if object.Fields[j].Name != unsorted.Objects[i].Fields[j].Name {
+				reordered = true
+			}
+		}
+	}
+	is.True(reordered) // some fields were not declared in order
+}
+
+func TestParseExcludeInterfacesPatterns(t *testing.T) {
+	is := is.New(t)
+
+	parser := NewParser("./testdata/services/globs")
+	parser.ExcludeInterfaces = []string{"Internal*"}
+	def, err := parser.Parse()
+	is.NoErr(err)
+	is.Equal(len(def.Services), 1)
+	is.Equal(def.Services[0].Name, "InternationalService")
+
+	// plain names are matched exactly
+	parser = NewParser("./testdata/services/globs")
+	parser.ExcludeInterfaces = []string{"InternalAdminService", "Internal"}
+	def, err = parser.Parse()
+	is.NoErr(err)
+	is.Equal(len(def.Services), 2)
+	is.Equal(def.Services[0].Name, "InternalAuditService")
+
+	parser = NewParser("./testdata/services/globs")
+	parser.ExcludeInterfaces = []string{"Internal[A-"}
+	_, err = parser.Parse()
+	is.True(err != nil)
+	is.Equal(err.Error(), `exclude interfaces: "Internal[A-": syntax error in pattern`)
+}
+
+func TestParseExcludeInterfacesSharedObjects(t *testing.T) {
+	is := is.New(t)
+	objectNames := func(def Definition) []string {
+		var names []string
+		for _, object := range def.Objects {
+			names = append(names, object.Name)
+		}
+		return names
+	}
+
+	// AccountResponse is shared with BillingService
+	parser := NewParser("./testdata/services/include")
+	parser.ExcludeInterfaces = []string{"AccountService"}
+	def, err := parser.Parse()
+	is.NoErr(err)
+	is.Equal(len(def.Services), 2)
+	is.Equal(objectNames(def), []string{"AccountResponse", "ChargeRequest", "GetUserRequest", "UserResponse"})
+
+	// AccountResponse is still used by the UserResponse object
+	parser = NewParser("./testdata/services/include")
+	parser.ExcludeInterfaces = []string{"AccountService", "BillingService"}
+	def, err = parser.Parse()
+	is.NoErr(err)
+	is.Equal(len(def.Services), 1)
+	is.Equal(objectNames(def), []string{"AccountResponse", "GetUserRequest", "UserResponse"})
+	is.Equal(len(def.Warnings), 0)
+}
+
+func TestParseExcludeInterfacesPrunesObjects(t *testing.T) {
+	is := is.New(t)
+
+	parser := NewParser("./testdata/services/prune")
+	parser.ExcludeInterfaces = []string{"AdminService"}
+	def, err := parser.Parse()
+	is.NoErr(err)
+	var names []string
+	for _, object := range def.Objects {
+		names = append(names, object.Name)
+	}
+	// objects only reachable from AdminService are removed (at any
+	// depth), Item is kept because PublicService uses it, and
+	// Unrelated was never used so it is kept (with a warning)
+	is.Equal(names, []string{"Item", "GetRequest", "GetResponse", "Unrelated"})
+	is.Equal(def.Warnings, []string{"object Unrelated is not used by any service"})
+}
+
+func TestParseSkip(t *testing.T) {
+	is := is.New(t)
+
+	def, err := NewParser("./testdata/services/skip").Parse()
+	is.NoErr(err)
+	is.Equal(len(def.Services), 1)
+	is.Equal(def.Services[0].Name, "GreeterService")
+	var names []string
+	for _, object := range def.Objects {
+		names = append(names, object.Name)
+	}
+	// the objects of AdminService are removed (at any depth), like
+	// for ExcludeInterfaces, and Cache is never an object
+	is.Equal(names, []string{"GreetRequest", "GreetResponse"})
+	is.Equal(len(def.Warnings), 0)
+
+	// fields cannot refer to skipped objects
+	_, err = NewParser("./testdata/services/errors/skipfield").Parse()
+	is.True(err != nil)
+	is.True(strings.HasSuffix(err.Error(), "skipfield.go:12:2: Cache: Cache has the oto:skip comment line, so it cannot be the type of a field"))
+}
+
+func TestParseRename(t *testing.T) {
+	is := is.New(t)
+
+	def, err := NewParser("./testdata/services/rename").Parse()
+	is.NoErr(err)
+	service := def.Services[0]
+	is.Equal(service.Name, "Proxies")
+	is.Equal(service.GoName, "ProxyService")
+	is.Equal(service.Comment, "ProxyService manages proxies.")
+	method := service.Methods[0]
+	is.Equal(method.Name, "GetHttpsProxy")
+	is.Equal(method.GoName, "GetHTTPSProxy")
+	is.Equal(method.Comment, "GetHTTPSProxy gets the HTTPS proxy.")
+	is.Equal(method.Path, "/Proxies/GetHttpsProxy")
+	is.Equal(method.InputObject.ObjectName, "GetHttpsProxyRequest")
+	is.Equal(method.InputObject.TypeName, "GetHTTPSProxyRequest")
+	is.Equal(method.InputObject.TypeID, "github.com/pacedotdev/oto/testdata/services/rename.GetHTTPSProxyRequest")
+	input, err := def.Object("GetHttpsProxyRequest")
+	is.NoErr(err)
+	is.Equal(input.GoName, "GetHTTPSProxyRequest")
+	is.Equal(input.TypeID, "github.com/pacedotdev/oto/testdata/services/rename.GetHTTPSProxyRequest")
+	is.Equal(input.Fields[0].Comment, "Region is where the proxy is.")
+	output, err := def.Object("GetHttpsProxyResponse")
+	is.NoErr(err)
+	is.Equal(output.Fields[0].Type.ObjectName, "HttpsProxy")
+	is.Equal(output.Fields[1].Name, "Error") // still an output object
+	proxy, err := def.Object("HttpsProxy")
+	is.NoErr(err)
+	is.Equal(proxy.GoName, "HTTPSProxy")
+	is.Equal(proxy.Comment, "HTTPSProxy is a proxy.")
+	is.Equal(proxy.Fields[1].Type.ObjectName, "HttpsProxy")
+	is.Equal(proxy.Circular, true) // found by its new name
+	var proxies int
+	for _, object := range def.Objects {
+		if object.GoName == "HTTPSProxy" {
+			proxies++
+		}
+	}
+	is.Equal(proxies, 1)
+
+	_, err = NewParser("./testdata/services/errors/rename").Parse()
+	is.True(err != nil)
+	is.True(strings.Contains(err.Error(), "rename.go:16:6: oto:rename: GetRequest is already declared at "))
+
+	_, err = NewParser("./testdata/services/errors/renamemethod").Parse()
+	is.True(err != nil)
+	is.True(strings.HasSuffix(err.Error(), "renamemethod.go:9:2: ProxyService.Get: oto:rename: Get is already the name of Find"))
+
+	_, err = parseRename(`"9lives"`)
+	is.Equal(err.Error(), `oto:rename: "9lives" is not a valid name`)
+	_, err = parseRename("Name")
+	is.Equal(err.Error(), `oto:rename: expected a quoted name, like "Name", not "Name"`)
+}
+
+func TestParseInputOutputObjects(t *testing.T) {
+	is := is.New(t)
+
+	def, err := NewParser("./testdata/services/inout").Parse()
+	is.NoErr(err)
+	for _, test := range []struct {
+		name              string
+		isInput, isOutput bool
+	}{
+		{name: "GetRequest", isInput: true},
+		{name: "Item", isInput: true, isOutput: true},
+		{name: "Tag"}, // only used by Item
+		{name: "PutResponse", isOutput: true},
+	} {
+		obj, err := def.Object(test.name)
+		is.NoErr(err)
+		is.Equal(obj.IsInput, test.isInput)   // IsInput
+		is.Equal(obj.IsOutput, test.isOutput) // IsOutput
+	}
+}
+
+func TestParseIncludeInterfaces(t *testing.T) {
+	is := is.New(t)
+
+	parser := NewParser("./testdata/services/include")
+	parser.IncludeInterfaces = []string{"BillingService", "UserService"}
+	def, err := parser.Parse()
+	is.NoErr(err)
+	is.Equal(len(def.Services), 2)
+	is.Equal(def.Services[0].Name, "BillingService")
+	is.Equal(def.Services[1].Name, "UserService")
+	var names []string
+	for _, object := range def.Objects {
+		names = append(names, object.Name)
+	}
+	// AccountResponse is shared with BillingService
+	is.Equal(names, []string{"AccountResponse", "ChargeRequest", "GetUserRequest", "UserResponse"})
+	is.Equal(len(def.Warnings), 0)
+}
+
+func TestParseIndex(t *testing.T) {
+	is := is.New(t)
+
+	def, err := NewParser("./testdata/services/order").Parse()
+	is.NoErr(err)
+	users := def.Services[1]
+	is.Equal(users.Name, "UserService")
+	is.Equal(users.Methods[0].Name, "Create")
+	is.Equal(users.Methods[0].Index, 0)
+	is.Equal(users.Methods[1].Name, "Delete")
+	is.Equal(users.Methods[1].Index, 2)
+	is.Equal(users.Methods[2].Name, "Update")
+	is.Equal(users.Methods[2].Index, 1)
+	userResponse, err := def.Object("UserResponse")
+	is.NoErr(err)
+	is.Equal(userResponse.Fields[0].Index, 0)
+	is.Equal(userResponse.Fields[1].Name, "Error")
+	is.Equal(userResponse.Fields[1].Index, 1)
+
+	// sorting fields keeps the declared index
+	parser := NewParser("./testdata/services/pleasantries")
+	parser.SortFields = true
+	def, err = parser.Parse()
+	is.NoErr(err)
+	for _, object := range def.Objects {
+		seen := make(map[int]bool)
+		for _, field := range object.Fields {
+			is.True(field.Index >= 0 && field.Index < len(object.Fields))
+			is.True(!seen[field.Index]) // indexes are unique
+			seen[field.Index] = true
+		}
+	}
+}
+
+func TestParseNameVariants(t *testing.T) {
+	is := is.New(t)
+
+	def, err := NewParser("./testdata/services/tags").Parse()
+	is.NoErr(err)
+	method := def.Services[0].Methods[0]
+	is.Equal(method.NameSnake, "create")
+	is.Equal(method.NameKebab, "create")
+	is.Equal(method.InputObject.ObjectNameSnake, "create_request")
+	is.Equal(method.InputObject.ObjectNameKebab, "create-request")
+	obj, err := def.Object("CreateRequest")
+	is.NoErr(err)
+	is.Equal(obj.NameSnake, "create_request")
+	is.Equal(obj.NameKebab, "create-request")
+	is.Equal(obj.Fields[0].Name, "UserID")
+	is.Equal(obj.Fields[0].NameSnake, "user_id")
+	is.Equal(obj.Fields[0].NameKebab, "user-id")
+	response, err := def.Object("CreateResponse")
+	is.NoErr(err)
+	is.Equal(response.Fields[0].NameSnake, "error")
+
+	def, err = NewParser("./testdata/services/rename").Parse()
+	is.NoErr(err)
+	for _, object := range def.Objects {
+		is.Equal(object.NameSnake, snakeDown(object.Name)) // renamed objects use the new name
+	}
+}
+
+func TestParseFieldOrderIsStable(t *testing.T) {
+	is := is.New(t)
+
+	// fieldOrder gets the names of the fields of every object,
+	// with their indexes.
+	fieldOrder := func(sortFields bool) map[string][]string {
+		parser := NewParser("./testdata/services/tags")
+		parser.SortFields = sortFields
+		def, err := parser.Parse()
+		is.NoErr(err)
+		order := make(map[string][]string)
+		for _, object := range def.Objects {
+			for _, field := range object.Fields {
+				order[object.Name] = append(order[object.Name], fmt.Sprintf("%d:%s", field.Index, field.Name))
+			}
+		}
+		return order
+	}
+	declared := fieldOrder(false)
+	for run := 0; run < 5; run++ {
+		is.Equal(fieldOrder(false), declared) // same order every run
+	}
+	is.Equal(declared["CreateRequest"], []string{"0:UserID", "1:ID", "2:Nickname", "3:Email", "4:Dash", "5:ExternalID"})
+	for _, fields := range declared {
+		for i, field := range fields {
+			is.True(strings.HasPrefix(field, fmt.Sprintf("%d:", i))) // Index is the position
+		}
+	}
+
+	// sorting reorders the fields, but keeps the indexes
+	sorted := fieldOrder(true)
+	is.Equal(sorted["CreateRequest"], []string{"4:Dash", "3:Email", "5:ExternalID", "1:ID", "2:Nickname", "0:UserID"})
+	for name, fields := range sorted {
+		is.Equal(len(fields), len(declared[name]))
+		for _, field := range fields {
+			is.True(isInSlice(declared[name], field))
+		}
+	}
+}
+
+func TestParsePreserveOrder(t *testing.T) {
+	is := is.New(t)
+
+	def, err := NewParser("./testdata/services/order").Parse()
+	is.NoErr(err)
+	is.Equal(def.Services[0].Name, "AccountService") // sorted by default
+	is.Equal(def.Services[1].Methods[0].Name, "Create")
+	is.Equal(def.Services[1].Methods[1].Name, "Delete")
+
+	parser := NewParser("./testdata/services/order")
+	parser.PreserveOrder = true
+	def, err = parser.Parse()
+	is.NoErr(err)
+	var names []string
+	for _, service := range def.Services {
+		names = append(names, service.Name)
+		for _, method := range service.Methods {
+			names = append(names, service.Name+"."+method.Name)
+		}
+	}
+	is.Equal(names, []string{
+		"UserService",
+		"UserService.Create",
+		"UserService.Update",
+		"UserService.Delete",
+		"AccountService",
+		"AccountService.Open",
+	})
+	names = nil
+	for _, object := range def.Objects {
+		names = append(names, object.Name)
+	}
+	is.Equal(names, []string{"UserResponse", "UpdateRequest", "CreateRequest", "DeleteRequest"})
+
+	// SortObjects takes precedence
+	parser = NewParser("./testdata/services/order")
+	parser.PreserveOrder = true
+	parser.SortObjects = true
+	def, err = parser.Parse()
+	is.NoErr(err)
+	is.Equal(def.Services[0].Name, "UserService")
+	is.Equal(def.Objects[0].Name, "CreateRequest")
+}
+
+func TestParseSortObjects(t *testing.T) {
+	is := is.New(t)
+
+	parser := NewParser("./testdata/services/pleasantries")
+	parser.ExcludeInterfaces = []string{"Ignorer"}
+	unsorted, err := parser.Parse()
+	is.NoErr(err)
+	parser = NewParser("./testdata/services/pleasantries")
+	parser.ExcludeInterfaces = []string{"Ignorer"}
+	parser.SortObjects = true
+	def, err := parser.Parse()
+	is.NoErr(err)
+	is.Equal(len(def.Objects), len(unsorted.Objects))
+	var reordered bool
+	for i, object := range def.Objects {
+		if i > 0 {
+			is.True(def.Objects[i-1].Name <= object.Name)
+		}
+		if object.Name != unsorted.Objects[i].Name {
+			reordered = true
+		}
+	}
+	is.True(reordered) // some objects were not found in order
+	greetResponse, err := def.Object("GreetResponse")
+	is.NoErr(err)
+	is.Equal(greetResponse.Fields[len(greetResponse.Fields)-1].Name, "Error") // sorted after addOutputFields
+}
+
+func TestParseMatchInterfaces(t *testing.T) {
+	is := is.New(t)
+
+	// UserRepository is not a valid service
+	_, err := NewParser("./testdata/services/match").Parse()
+	is.True(err != nil)
+
+	parser := NewParser("./testdata/services/match")
+	parser.MatchInterfaces = regexp.MustCompile("Service$")
+	def, err := parser.Parse()
+	is.NoErr(err)
+	is.Equal(len(def.Services), 1)
+	is.Equal(def.Services[0].Name, "UserService")
+	var names []string
+	for _, object := range def.Objects {
+		names = append(names, object.Name)
+	}
+	is.Equal(names, []string{"GetRequest", "GetResponse"}) // no User
+	is.Equal(len(def.Warnings), 0)
+}
+
+func TestParseIgnoredMethods(t *testing.T) {
+	is := is.New(t)
+
+	def, err := NewParser("./testdata/services/ignore").Parse()
+	is.NoErr(err)
+	is.Equal(len(def.Services), 1)
+	is.Equal(len(def.Services[0].Methods), 1)
+	is.Equal(def.Services[0].Methods[0].Name, "Get")
+	var names []string
+	for _, object := range def.Objects {
+		names = append(names, object.Name)
+	}
+	// GetRequest is still used by Get
+	is.Equal(names, []string{"GetRequest", "GetResponse"})
+	is.Equal(len(def.Warnings), 0) // no unused objects
+}
+
+func TestParseRateLimits(t *testing.T) {
+	is := is.New(t)
+
+	def, err := NewParser("./testdata/services/ratelimits").Parse()
+	is.NoErr(err)
+	is.Equal(def.Services[0].Name, "PingService")
+	is.Equal(def.Services[0].Methods[0].RateLimit, (*RateLimit)(nil)) // no limit
+	search := def.Services[1]
+	is.Equal(search.Name, "SearchService")
+	is.Equal(search.DefaultRateLimit, &RateLimit{Requests: 100, Per: "minute"})
+	is.Equal(search.Comment, "SearchService searches things.")
+	is.Equal(search.Methods[0].Name, "Export")
+	is.Equal(search.Methods[0].RateLimit, &RateLimit{Requests: 5, Per: "hour"})
+	is.Equal(search.Methods[0].Comment, "Export exports the results.")
+	is.Equal(search.Methods[1].Name, "Search")
+	is.Equal(search.Methods[1].RateLimit, &RateLimit{Requests: 100, Per: "minute"}) // from the service
+
+	for _, value := range []string{"", "100", "100 minute", "-1 per minute", "0 per second", "ten per day", "100 per fortnight"} {
+		if _, err := parseRateLimit(value); err == nil {
+			t.Errorf("%q: expected error", value)
+		}
+	}
+	_, err = NewParser("./testdata/services/errors/ratelimit").Parse()
+	is.True(err != nil)
+	is.True(strings.HasSuffix(err.Error(), `ratelimit.go:7:2: oto:rate-limit: unknown period "fortnight" (expected second, minute, hour or day)`))
+}
+
+func TestParseTimeouts(t *testing.T) {
+	is := is.New(t)
+
+	def, err := NewParser("./testdata/services/timeouts").Parse()
+	is.NoErr(err)
+	is.Equal(def.Services[0].Name, "PingService")
+	is.Equal(def.Services[0].DefaultTimeout, "")
+	is.Equal(def.Services[0].Methods[0].Timeout, "") // no timeout
+	reports := def.Services[1]
+	is.Equal(reports.Name, "ReportService")
+	is.Equal(reports.DefaultTimeout, "30s")
+	is.Equal(reports.Comment, "ReportService makes reports.")
+	is.Equal(reports.Methods[0].Name, "Get")
+	is.Equal(reports.Methods[0].Timeout, "30s") // from the service
+	is.Equal(reports.Methods[1].Name, "Run")
+	is.Equal(reports.Methods[1].Timeout, "5m")
+	is.Equal(reports.Methods[1].Comment, "Run runs a report, which can take a while.")
+
+	_, err = NewParser("./testdata/services/errors/timeout").Parse()
+	is.True(err != nil)
+	is.True(strings.HasSuffix(err.Error(), `timeout.go:7:2: oto:timeout: invalid duration "5 minutes" (expected something like 30s or 5m)`))
+}
+
+func TestParseMaxRecursionDepth(t *testing.T) {
+	is := is.New(t)
+
+	_, err := NewParser("./testdata/services/deep").Parse()
+	is.True(err != nil)
+	is.True(strings.HasSuffix(err.Error(), "deep.go:114:2: max recursion depth exceeded"))
+
+	parser := NewParser("./testdata/services/deep")
+	parser.MaxRecursionDepth = 30
+	def, err := parser.Parse()
+	is.NoErr(err)
+	is.Equal(len(def.Objects), 27)
+
+	parser = NewParser("./testdata/services/deep")
+	parser.MaxRecursionDepth = 0 // no limit
+	_, err = parser.Parse()
+	is.NoErr(err)
+}
+
+func TestParseEnums(t *testing.T) {
+	is := is.New(t)
+
+	def, err := NewParser("./testdata/services/enums").Parse()
+	is.NoErr(err)
+	is.Equal(len(def.Enums), 2) // Label has no values
+	priority, err := def.Enum("Priority")
+	is.NoErr(err)
+	is.Equal(priority.TypeID, "github.com/pacedotdev/oto/testdata/services/enums.Priority")
+	is.Equal(priority.JSType, "number")
+	is.Equal(priority.Values, []EnumValue{
+		{Name: "Low", Value: int64(0)},
+		{Name: "Medium", Value: int64(1)},
+		{Name: "High", Value: int64(2)},
+	})
+	status, err := def.Enum("Status")
+	is.NoErr(err)
+	is.Equal(status.JSType, "string")
+	is.Equal(status.Comment, "Status is the status of a task.")
+	is.Equal(status.Values, []EnumValue{
+		{Name: "Active", Value: "active", Comment: "Active tasks are being worked on."},
+		{Name: "Inactive", Value: "inactive", Comment: "Inactive tasks are paused."},
+		{Name: "Done", Value: "done", Comment: "Done tasks are finished."},
+	})
+
+	obj, err := def.Object("UpdateRequest")
+	is.NoErr(err)
+	is.Equal(obj.Fields[0].Type.IsEnum, true)
+	is.Equal(obj.Fields[0].Type.EnumName, "Status")
+	is.Equal(obj.Fields[1].Type.IsEnum, true)
+	is.Equal(obj.Fields[1].Type.EnumName, "Status")
+	is.Equal(obj.Fields[1].Type.Multiple, true)
+	is.Equal(obj.Fields[2].Type.EnumName, "Priority")
+	is.Equal(obj.Fields[2].Type.Nullable, true)
+	is.Equal(obj.Fields[3].Type.IsEnum, false) // Label has no values
+	is.Equal(obj.Fields[3].Type.EnumName, "")
+
+	// enums of removed objects are removed too
+	parser := NewParser("./testdata/services/enums")
+	parser.ExcludeInterfaces = []string{"TaskService"}
+	def, err = parser.Parse()
+	is.NoErr(err)
+	is.Equal(len(def.Enums), 0)
+}
+
+func TestParseJSONTags(t *testing.T) {
+	is := is.New(t)
+
+	def, err := NewParser("./testdata/services/tags").Parse()
+	is.NoErr(err)
+	obj, err := def.Object("CreateRequest")
+	is.NoErr(err)
+	is.Equal(obj.Fields[0].Name, "UserID")
+	is.Equal(obj.Fields[0].NameLowerCamel, "userID")
+	is.Equal(obj.Fields[0].JSONName, "user_id")
+	is.Equal(obj.Fields[1].JSONName, "id")       // options are not part of the name
+	is.Equal(obj.Fields[2].JSONName, "nickname") // no name in the tag
+	is.Equal(obj.Fields[3].JSONName, "email")    // no tag
+	is.Equal(obj.Fields[0].OmitEmpty, false)
+	is.Equal(obj.Fields[1].OmitEmpty, true)
+	is.Equal(obj.Fields[2].OmitEmpty, true)
+	is.Equal(obj.Fields[3].OmitEmpty, false)
+	is.Equal(len(obj.Fields), 6) // json:"-" fields are skipped
+	is.Equal(obj.Fields[4].Name, "Dash")
+	is.Equal(obj.Fields[4].JSONName, "-") // json:"-," is named "-"
+	is.Equal(obj.Fields[4].Index, 4)
+	is.Equal(obj.Fields[5].JSONName, "external-id")
+	response, err := def.Object("CreateResponse")
+	is.NoErr(err)
+	is.Equal(response.Fields[0].JSONName, "error")
+}
+
+func TestParseOneOf(t *testing.T) {
+	is := is.New(t)
+
+	def, err := NewParser("./testdata/services/oneof").Parse()
+	is.NoErr(err)
+	obj, err := def.Object("PayRequest")
+	is.NoErr(err)
+	is.Equal(obj.Fields[0].Type.OneOf, []string{"Card", "BankTransfer"})
+	is.Equal(obj.Fields[0].Discriminator, "type")
+	is.Equal(obj.Fields[0].Comment, "Method is how to pay.")
+	is.Equal(obj.Fields[1].Type.OneOf, []string{"Card", "Voucher"})
+	is.Equal(obj.Fields[1].Type.Multiple, true)
+	is.Equal(obj.Fields[1].Discriminator, "")
+	// the oneof objects are used, so there are no warnings about them
+	is.Equal(def.Warnings, []string{"PayRequest.Fallbacks: oto:oneof object Voucher not found"})
+
+	parser := NewParser("./testdata/services/oneof")
+	parser.Strict = true
+	parser.StrictChecks = StrictChecks{MissingObjects: true}
+	_, err = parser.Parse()
+	is.True(err != nil)
+	is.Equal(err.Error(), "PayRequest.Fallbacks: oto:oneof object Voucher not found (strict)")
+
+	_, err = NewParser("./testdata/services/errors/discriminator").Parse()
+	is.True(err != nil)
+	is.True(strings.HasSuffix(err.Error(), "discriminator.go:13:2: oto:discriminator: the field has no oto:oneof line"))
+}
+
+func TestParseDefaults(t *testing.T) {
+	is := is.New(t)
+
+	def, err := NewParser("./testdata/services/defaults").Parse()
+	is.NoErr(err)
+	obj, err := def.Object("SearchRequest")
+	is.NoErr(err)
+	is.Equal(obj.Fields[0].HasDefault, false) // Query
+	is.Equal(obj.Fields[0].Default, nil)
+	is.Equal(obj.Fields[1].HasDefault, true) // Limit
+	is.Equal(obj.Fields[1].Default, float64(20))
+	is.Equal(obj.Fields[1].Example, float64(50))
+	is.Equal(obj.Fields[1].Comment, "Limit is the most results to return.")
+	is.Equal(obj.Fields[2].Default, "relevance") // Sort
+	is.Equal(obj.Fields[3].HasDefault, true)     // Cursor
+	is.Equal(obj.Fields[3].Default, nil)
+	is.Equal(obj.Fields[4].HasDefault, false) // Filter
+	is.Equal(len(def.Warnings), 1)
+	is.True(strings.HasSuffix(def.Warnings[0], `defaults.go:25:2: SearchRequest.Filter: oto:default: invalid JSON value "{not json}"`))
+
+	schema, err := GenerateJSONSchema(def)
+	is.NoErr(err)
+	properties := schema["$defs"].(map[string]interface{})["SearchRequest"].(map[string]interface{})["properties"].(map[string]interface{})
+	is.Equal(properties["limit"].(map[string]interface{})["default"], float64(20))
+	_, hasDefault := properties["query"].(map[string]interface{})["default"]
+	is.True(!hasDefault)
+	_, hasDefault = properties["cursor"].(map[string]interface{})["default"]
+	is.True(hasDefault) // null
+
+	parser := NewParser("./testdata/services/defaults")
+	parser.Strict = true
+	parser.StrictChecks = StrictChecks{}
+	_, err = parser.Parse()
+	is.True(err != nil)
+	is.True(strings.Contains(err.Error(), `defaults.go:25:2: SearchRequest.Filter: oto:default: invalid JSON value "{not json}"`))
+
+	_, err = NewParser("./testdata/services/errors/defaults").Parse()
+	is.True(err != nil)
+	is.True(strings.HasSuffix(err.Error(), "defaults.go:13:2: SearchRequest.Limit: default: expected number, got string"))
+}
+
+func TestParseDefaultLines(t *testing.T) {
+	is := is.New(t)
+
+	def, err := NewParser("./testdata/services/defaults").Parse()
+	is.NoErr(err)
+	obj, err := def.Object("SearchRequest")
+	is.NoErr(err)
+	is.Equal(obj.Fields[5].Name, "Tags")
+	is.Equal(obj.Fields[5].HasDefault, true)
+	is.Equal(obj.Fields[5].Default, []interface{}{"new"})
+	is.Equal(obj.Fields[5].Example, []interface{}{"new", "sale"})
+	is.Equal(obj.Fields[5].Comment, "Tags are the tags to match.")
+	is.Equal(obj.Fields[6].HasDefault, true) // Exact
+	is.Equal(obj.Fields[6].Default, false)
+
+	spec, err := GenerateOpenAPI(def, nil)
+	is.NoErr(err)
+	properties := spec["components"].(map[string]interface{})["schemas"].(map[string]interface{})["SearchRequest"].(map[string]interface{})["properties"].(map[string]interface{})
+	is.Equal(properties["tags"].(map[string]interface{})["default"], []interface{}{"new"})
+	is.Equal(properties["limit"].(map[string]interface{})["default"], float64(20))
+
+	s, err := GenerateTypeScript(def)
+	is.NoErr(err)
+	is.True(strings.Contains(s, "\t * Tags are the tags to match.\n\t * @default [\"new\"]\n\t */\n\ttags: string[];"))
+}
+
+func TestParseFieldEnums(t *testing.T) {
+	is := is.New(t)
+
+	def, err := NewParser("./testdata/services/fieldenums").Parse()
+	is.NoErr(err)
+	obj, err := def.Object("ListRequest")
+	is.NoErr(err)
+	is.Equal(obj.Fields[0].Enum, []interface{}{"asc", "desc"})
+	is.Equal(obj.Fields[0].Comment, "Order is the order of the results.")
+	is.Equal(obj.Fields[1].Enum, []interface{}{float64(10), float64(20), float64(50)})
+	is.Equal(obj.Fields[2].Enum, nil)
+
+	_, err = NewParser("./testdata/services/errors/fieldenums").Parse()
+	is.True(err != nil)
+	is.True(strings.HasSuffix(err.Error(), "fieldenums.go:13:2: ListRequest.Order: enum: value 1: expected string, got number"))
+
+	s, err := GenerateTypeScript(def)
+	is.NoErr(err)
+	is.True(strings.Contains(s, "\torder: \"asc\" | \"desc\";"))
+	is.True(strings.Contains(s, "\tsizes: (10 | 20 | 50)[];"))
+	checkTypeScript(t, s)
+
+	spec, err := GenerateOpenAPI(def, nil)
+	is.NoErr(err)
+	properties := spec["components"].(map[string]interface{})["schemas"].(map[string]interface{})["ListRequest"].(map[string]interface{})["properties"].(map[string]interface{})
+	is.Equal(properties["order"].(map[string]interface{})["enum"], []interface{}{"asc", "desc"})
+	sizes := properties["sizes"].(map[string]interface{})
+	is.Equal(sizes["items"].(map[string]interface{})["enum"], []interface{}{float64(10), float64(20), float64(50)})
+}
+
+func TestParseConstraintCommentLines(t *testing.T) {
+	is := is.New(t)
+	float := func(n float64) *float64 { return &n }
+	length := func(n int) *int { return &n }
+
+	def, err := NewParser("./testdata/services/limits").Parse()
+	is.NoErr(err)
+	obj, err := def.Object("SignupRequest")
+	is.NoErr(err)
+	is.Equal(*obj.Fields[0].Constraints, FieldConstraints{MinLength: length(3), MaxLength: length(20), Pattern: "^[a-z]+$"})
+	is.Equal(obj.Fields[0].Comment, "Username is the name to sign in with.")
+	is.Equal(obj.Fields[1].Type.Format, "email")
+	is.Equal(obj.Fields[1].Comment, "Email is the email address.")
+	is.Equal(*obj.Fields[2].Constraints, FieldConstraints{Min: float(18)})
+	is.Equal(*obj.Fields[3].Constraints, FieldConstraints{Min: float(1), Max: float(99.5)}) // the line wins
+	is.Equal(*obj.Fields[4].Constraints, FieldConstraints{MaxLength: length(5)})
+
+	_, err = NewParser("./testdata/services/errors/limits").Parse()
+	is.True(err != nil)
+	is.True(strings.HasSuffix(err.Error(), "limits.go:14:2: SignupRequest.Age: min 18 is greater than max 16"))
+}
+
+func TestCheckValueType(t *testing.T) {
+	is := is.New(t)
+
+	is.NoErr(checkValueType("a", FieldType{JSType: "string"}))
+	is.NoErr(checkValueType(float64(2), FieldType{JSType: "number", TypeName: "int"}))
+	is.NoErr(checkValueType(nil, FieldType{JSType: "string", Nullable: true}))
+	is.NoErr(checkValueType([]interface{}{true}, FieldType{JSType: "boolean", Multiple: true}))
+	is.NoErr(checkValueType(map[string]interface{}{}, FieldType{JSType: "object", IsObject: true}))
+	is.NoErr(checkValueType(float64(1), FieldType{JSType: "any"}))
+	is.Equal(checkValueType(nil, FieldType{JSType: "string"}).Error(), "expected string, got null")
+	is.Equal(checkValueType(2.5, FieldType{JSType: "number", TypeName: "int"}).Error(), "expected an integer, got 2.5")
+	is.Equal(checkValueType("a", FieldType{JSType: "string", Multiple: true}).Error(), "expected an array, got string")
+	is.Equal(checkValueType([]interface{}{"a", 1.0}, FieldType{JSType: "string", Multiple: true}).Error(), "item 1: expected string, got number")
+}
+
+func TestParseSkipUnexportedFields(t *testing.T) {
+	is := is.New(t)
+
+	_, err := NewParser("./testdata/services/unexported").Parse()
+	is.True(err != nil)
+	is.True(strings.HasSuffix(err.Error(), "unexported.go:19:2: mu must be exported"))
+
+	parser := NewParser("./testdata/services/unexported")
+	parser.SkipUnexportedFields = true
+	def, err := parser.Parse()
+	is.NoErr(err)
+	obj, err := def.Object("GetResponse")
+	is.NoErr(err)
+	is.Equal(len(obj.Fields), 2) // Value and Error
+	is.Equal(obj.Fields[0].Name, "Value")
+	is.Equal(obj.Fields[1].Name, "Error")
+	is.Equal(obj.Fields[1].Index, 1)
+}
+
+func TestParseConstraints(t *testing.T) {
+	is := is.New(t)
+
+	def, err := NewParser("./testdata/services/validation").Parse()
+	is.NoErr(err)
+	obj, err := def.Object("SignupRequest")
+	is.NoErr(err)
+	fields := make(map[string]Field)
+	for _, field := range obj.Fields {
+		fields[field.Name] = field
+	}
+	is.Equal(*fields["Name"].Constraints.MinLength, 1)
+	is.Equal(*fields["Name"].Constraints.MaxLength, 140)
+	is.Equal(fields["Email"].Constraints.Email, true)
+	is.Equal(*fields["Age"].Constraints.Min, float64(18))
+	is.Equal(*fields["Age"].Constraints.Max, float64(130))
+	is.Equal(fields["Color"].Constraints.Enum, []interface{}{"red", "green"})
+	is.Equal(*fields["Tags"].Constraints.MaxLength, 5)
+	is.Equal(fields["Tags"].Constraints.MinLength, (*int)(nil)) // after dive
+	is.Equal(fields["Notes"].Constraints, (*FieldConstraints)(nil))
+	is.Equal(fields["Handle"].Constraints, (*FieldConstraints)(nil))
+
+	schema, err := GenerateJSONSchema(def)
+	is.NoErr(err)
+	properties := schema["$defs"].(map[string]interface{})["SignupRequest"].(map[string]interface{})["properties"].(map[string]interface{})
+	property := func(name string) map[string]interface{} {
+		return properties[name].(map[string]interface{})
+	}
+	is.Equal(property("name")["minLength"], 1)
+	is.Equal(property("name")["maxLength"], 140)
+	is.Equal(property("email")["format"], "email")
+	is.Equal(property("age")["minimum"], float64(18))
+	is.Equal(property("color")["enum"], []interface{}{"red", "green"})
+	is.Equal(property("tags")["maxItems"], 5)
+}
+
+func TestParseFormats(t *testing.T) {
+	is := is.New(t)
+
+	def, err := NewParser("./testdata/services/formats").Parse()
+	is.NoErr(err)
+	obj, err := def.Object("CreateRequest")
+	is.NoErr(err)
+	is.Equal(obj.Fields[0].Type.Format, "email")
+	is.Equal(obj.Fields[0].Comment, "Email is the email address.")
+	is.Equal(obj.Fields[1].Type.Format, "password")
+	is.Equal(obj.Fields[2].Type.Format, "uri")  // the tag takes precedence
+	is.Equal(obj.Fields[3].Type.Format, "date") // instead of date-time
+	is.Equal(obj.Fields[4].Type.Format, "color")
+	is.Equal(len(def.Warnings), 1)
+	is.True(strings.Contains(def.Warnings[0], `formats.go:26:2: CreateRequest.Color: oto:format: unknown format "color"`))
+
+	parser := NewParser("./testdata/services/formats")
+	parser.Strict = true
+	parser.StrictChecks = StrictChecks{}
+	_, err = parser.Parse()
+	is.True(err != nil)
+	is.True(strings.Contains(err.Error(), `formats.go:26:2: CreateRequest.Color: oto:format: unknown format "color"`))
+}
+
+func TestParseRequired(t *testing.T) {
+	is := is.New(t)
+
+	def, err := NewParser("./testdata/services/required").Parse()
+	is.NoErr(err)
+	obj, err := def.Object("UpdateRequest")
+	is.NoErr(err)
+	required := make(map[string]bool)
+	for _, field := range obj.Fields {
+		required[field.Name] = field.Required
+	}
+	is.Equal(required, map[string]bool{
+		"Name":     true,  // not omitempty
+		"Nickname": false, // omitempty
+		"Email":    true,  // validate tag
+		"Team":     true,  // binding tag
+		"Bio":      false, // nullable
+		"Avatar":   true,  // comment line
+		"Age":      false, // comment line
+		"Tags":     false, // required is for the elements
+	})
+	is.Equal(obj.Fields[5].Comment, "Avatar is the URL of the picture.")
+	response, err := def.Object("UpdateResponse")
+	is.NoErr(err)
+	is.Equal(response.Fields[0].Name, "Error")
+	is.Equal(response.Fields[0].Required, false)
+
+	spec, err := GenerateOpenAPI(def, nil)
+	is.NoErr(err)
+	schemas := spec["components"].(map[string]interface{})["schemas"].(map[string]interface{})
+	is.Equal(schemas["UpdateRequest"].(map[string]interface{})["required"], []interface{}{"name", "email", "team", "avatar"})
+	_, ok := schemas["UpdateResponse"].(map[string]interface{})["required"]
+	is.True(!ok)
+
+	s, err := GenerateTypeScript(def)
+	is.NoErr(err)
+	is.True(strings.Contains(s, "\temail: string;"))
+	is.True(strings.Contains(s, "\tnickname?: string;"))
+}
+
+func TestParseSensitive(t *testing.T) {
+	is := is.New(t)
+
+	def, err := NewParser("./testdata/services/sensitive").Parse()
+	is.NoErr(err)
+	obj, err := def.Object("CreateRequest")
+	is.NoErr(err)
+	is.Equal(obj.Fields[0].Sensitive, false)
+	is.Equal(obj.Fields[1].Sensitive, true)
+	is.Equal(obj.Fields[1].Comment, "Password is the password.")
+	is.Equal(obj.Fields[2].Sensitive, true) // tag
+	var names []string
+	for _, ref := range def.SensitiveFields() {
+		names = append(names, ref.ObjectName+"."+ref.Field.Name)
+	}
+	is.Equal(names, []string{"CreateRequest.Password", "CreateRequest.SSN", "User.Token"})
+	is.Equal(len(def.Warnings), 0)
+
+	parser := NewParser("./testdata/services/sensitive")
+	parser.Strict = true
+	parser.StrictChecks = StrictChecks{}
+	def, err = parser.Parse()
+	is.NoErr(err)
+	is.Equal(def.Warnings, []string{"User.Token is sensitive, but it is in a response"})
+}
+
+func TestParseReadWriteOnly(t *testing.T) {
+	is := is.New(t)
+
+	def, err := NewParser("./testdata/services/readwrite").Parse()
+	is.NoErr(err)
+	obj, err := def.Object("Account")
+	is.NoErr(err)
+	is.Equal(obj.Fields[0].ReadOnly, true)
+	is.Equal(obj.Fields[0].Comment, "ID is set by the server.")
+	is.Equal(obj.Fields[1].ReadOnly, false)
+	is.Equal(obj.Fields[1].WriteOnly, false)
+	is.Equal(obj.Fields[2].WriteOnly, true)
+	is.Equal(obj.Fields[2].Comment, "Password is the password.")
+	is.Equal(obj.Fields[3].ReadOnly, true) // tag
+	is.Equal(len(def.Warnings), 0)
+
+	parser := NewParser("./testdata/services/readwrite")
+	parser.Strict = true
+	parser.StrictChecks = StrictChecks{}
+	def, err = parser.Parse()
+	is.NoErr(err)
+	// Password is sensitive, but writeonly, so it is not a warning
+	is.Equal(def.Warnings, []string{
+		"Account.ID is readonly, but it is in a request",
+		"Account.Password is writeonly, but it is in a response",
+		"Account.CreatedAt is readonly, but it is in a request",
+	})
+
+	_, err = NewParser("./testdata/services/errors/readwrite").Parse()
+	is.True(err != nil)
+	is.True(strings.HasSuffix(err.Error(), "readwrite.go:13:2: CreateRequest.Token: cannot be both readonly and writeonly"))
+}
+
+func TestParseVersions(t *testing.T) {
+	is := is.New(t)
+
+	def, err := NewParser("./testdata/services/versions").Parse()
+	is.NoErr(err)
+	invoices := def.Services[0]
+	is.Equal(invoices.Name, "InvoiceService")
+	is.Equal(invoices.Version, "1")
+	is.Equal(invoices.RoutePrefix, "/billing") // oto:prefix wins
+	is.Equal(invoices.Comment, "InvoiceService sends invoices.")
+	payments := def.Services[1]
+	is.Equal(payments.Name, "PaymentService")
+	is.Equal(payments.Version, "2")
+	is.Equal(payments.RoutePrefix, "") // the version is not a prefix
+	is.Equal(payments.Comment, "PaymentService takes payments.")
+	is.Equal(payments.Methods[0].Path, "/PaymentService/Charge")
+	refunds := def.Services[2]
+	is.Equal(refunds.Version, "")
+	is.Equal(refunds.RoutePrefix, "")
+
+	spec, err := GenerateOpenAPI(def, nil)
+	is.NoErr(err)
+	_, ok := spec["paths"].(map[string]interface{})["/PaymentService/Charge"]
+	is.True(ok)
+	_, ok = spec["paths"].(map[string]interface{})["/billing/InvoiceService/Send"]
+	is.True(ok)
+
+	_, err = NewParser("./testdata/services/errors/version").Parse()
+	is.True(err != nil)
+	is.True(strings.HasSuffix(err.Error(), "version.go:5:6: oto:version: missing version"))
+}
+
+func TestParseDefinitionVersion(t *testing.T) {
+	is := is.New(t)
+
+	def, err := NewParser("./testdata/services/apiversion").Parse()
+	is.NoErr(err)
+	is.Equal(def.Version, "1.2.3")
+
+	def, err = NewParser("./testdata/services/apiversionconst").Parse()
+	is.NoErr(err)
+	is.Equal(def.Version, "2.0.0")
+
+	def, err = NewParser("./testdata/services/pleasantries").Parse()
+	is.NoErr(err)
+	is.Equal(def.Version, "")
+
+	parser := NewParser("./testdata/services/apiversion")
+	parser.VersionOverride = "1.3.0-rc.1"
+	def, err = parser.Parse()
+	is.NoErr(err)
+	is.Equal(def.Version, "1.3.0-rc.1")
+
+	spec, err := GenerateOpenAPI(def, nil)
+	is.NoErr(err)
+	is.Equal(spec["info"].(map[string]interface{})["version"], "1.3.0-rc.1")
+	s, err := GenerateTypeScript(def)
+	is.NoErr(err)
+	is.True(strings.HasPrefix(s, "// Code generated by oto; DO NOT EDIT.\n// Version: 1.3.0-rc.1\n\n"))
+}
+
+func TestParseStreams(t *testing.T) {
+	is := is.New(t)
+
+	def, err := NewParser("./testdata/services/streams").Parse()
+	is.NoErr(err)
+	methods := def.Services[0].Methods
+	is.Equal(methods[0].Name, "Chat")
+	is.Equal(methods[0].Stream, "bidi")
+	is.Equal(methods[1].Name, "Count")
+	is.Equal(methods[1].Stream, "")
+	is.Equal(methods[2].Name, "Tail")
+	is.Equal(methods[2].Stream, "server")
+	is.Equal(methods[2].Streaming, true)
+	is.Equal(methods[2].Comment, "Tail streams log lines, which can have errors.")
+	is.Equal(methods[3].Name, "Upload")
+	is.Equal(methods[3].Stream, "client")
+	is.Equal(methods[4].Name, "Watch")
+	is.Equal(methods[4].Stream, "server")
+	is.Equal(methods[4].Streaming, true)
+	is.Equal(methods[4].Comment, "Watch streams events to the client.")
+	is.Equal(methods[0].Streaming, true)  // bidi
+	is.Equal(methods[1].Streaming, false) // unary
+	is.Equal(methods[3].Streaming, false) // client
+	fieldNames := func(objectName string) []string {
+		object, err := def.Object(objectName)
+		is.NoErr(err)
+		var names []string
+		for _, field := range object.Fields {
+			names = append(names, field.Name)
+		}
+		return names
+	}
+	is.Equal(fieldNames("Event"), []string{"Message"}) // no Error field
+	is.Equal(fieldNames("LogLine"), []string{"Text", "Error"})
+	is.Equal(fieldNames("UploadResponse"), []string{"Count", "Error"})
+
+	_, err = NewParser("./testdata/services/errors/stream").Parse()
+	is.True(err != nil)
+	is.True(strings.HasSuffix(err.Error(), "stream.go:7:2: EventService.Watch: streamed output must be a struct"))
+}
+
+func TestParseFiles(t *testing.T) {
+	is := is.New(t)
+
+	def, err := NewParser("./testdata/services/files").Parse()
+	is.NoErr(err)
+	upload, err := def.Object("UploadRequest")
+	is.NoErr(err)
+	is.Equal(upload.Fields[0].Type.IsFile, false)
+	is.Equal(upload.Fields[1].Type.IsFile, true)
+	is.Equal(upload.Fields[1].Type.TypeName, "multipart.FileHeader")
+	is.Equal(upload.Fields[1].Type.JSType, "string")
+	is.Equal(upload.Fields[1].Type.Format, "binary")
+	_, err = def.Object("FileHeader")
+	is.True(err != nil) // files are not objects
+	importRequest, err := def.Object("ImportRequest")
+	is.NoErr(err)
+	is.Equal(importRequest.Fields[0].Type.IsFile, true)
+	attach, err := def.Object("AttachRequest")
+	is.NoErr(err)
+	is.Equal(attach.Fields[0].Type.IsFile, true)
+	is.Equal(attach.Fields[0].Comment, "Content is the base64 file content.")
+
+	multipart := make(map[string]bool)
+	for _, method := range def.Services[0].Methods {
+		multipart[method.Name] = method.IsMultipart
+	}
+	is.Equal(multipart, map[string]bool{
+		"Attach": true,
+		"Import": true,
+		"Rename": false,
+		"Upload": true,
+	})
+}
+
+func TestParseRouteErrors(t *testing.T) {
+	is := is.New(t)
+
+	_, err := NewParser("./testdata/services/errors/route").Parse()
+	is.True(err != nil)
+	is.True(strings.HasSuffix(err.Error(), `route.go:7:2: oto:route: unknown HTTP method "FETCH"`))
+}
+
+func TestExtractDirective(t *testing.T) {
+	is := is.New(t)
+
+	value, found, comment := extractDirective("This is a comment\noto:format email\nMore comment", "oto:format")
+	is.True(found)
+	is.Equal(value, "email")
+	is.Equal(comment, "This is a comment\nMore comment")
+
+	value, found, comment = extractDirective("This is a comment\noto:formatting", "oto:format")
+	is.True(!found)
+	is.Equal(value, "")
+	is.Equal(comment, "This is a comment\noto:formatting")
+}
+
+func TestParseInterfaceFieldErrors(t *testing.T) {
+	is := is.New(t)
+
+	parser := NewParser("./testdata/services/errors/stdlibinterface")
+	_, err := parser.Parse()
+	is.True(err != nil)
+	is.True(strings.Contains(err.Error(), "UploadRequest.Body"))
+	is.True(strings.Contains(err.Error(), "stdlibinterface.go:14"))
+	is.True(strings.Contains(err.Error(), "interface type io.Writer is not supported"))
+
+	parser = NewParser("./testdata/services/errors/localinterface")
+	parser.ExcludeInterfaces = []string{"Notifier"}
+	_, err = parser.Parse()
+	is.True(err != nil)
+	is.True(strings.Contains(err.Error(), "NotifyRequest.Via"))
+	is.True(strings.Contains(err.Error(), "localinterface.go:12"))
+	is.True(strings.Contains(err.Error(), "localinterface.Notifier is not supported"))
+}
+
+func TestParseFallbackJSTypes(t *testing.T) {
+	is := is.New(t)
+	parser := NewParser("./testdata/services/jstypes")
+	def, err := parser.Parse()
+	is.NoErr(err)
+
+	obj, err := def.Object("CheckRequest")
+	is.NoErr(err)
+	for _, field := range obj.Fields {
+		switch field.Name {
+		case "Byte", "Rune", "Uintptr":
+			is.Equal(field.Type.JSType, "number")
+			is.Equal(field.Type.JSTypeUnknown, false)
+			is.Equal(field.Type.UnderlyingTypeName, "") // not a named type
+		case "Score":
+			is.Equal(field.Type.JSType, "number")
+			is.Equal(field.Type.JSTypeUnknown, false)
+			is.Equal(field.Type.UnderlyingTypeName, "float64")
+		case "UserID":
+			is.Equal(field.Type.TypeName, "UserID")
+			is.Equal(field.Type.UnderlyingTypeName, "string")
+			is.Equal(field.Type.JSType, "string")
+			is.Equal(field.Type.JSTypeUnknown, false)
+		case "Any":
+			is.Equal(field.Type.JSType, "any")
+			is.Equal(field.Type.JSTypeUnknown, false)
+		case "Lookup":
+			is.Equal(field.Type.JSType, "object")
+			is.Equal(field.Type.IsMap, true)
+			is.Equal(field.Type.MapValueType.JSType, "string")
+		case "Pair":
+			is.Equal(field.Type.JSType, "any")
+			is.Equal(field.Type.JSTypeUnknown, true)
+		default:
+			t.Errorf("unexpected field: %s", field.Name)
+		}
+	}
+}
+
+func TestParseUnsupportedFieldTypes(t *testing.T) {
+	for _, tc := range []struct {
+		pattern  string
+		typeName string
+	}{
+		{"./testdata/services/errors/chanfield", "chan string"},
+		{"./testdata/services/errors/funcfield", "func()"},
+		{"./testdata/services/errors/unsafefield", "unsafe.Pointer"},
+		{"./testdata/services/errors/complexfield", "complex128"},
+	} {
+		t.Run(tc.typeName, func(t *testing.T) {
+			is := is.New(t)
+			parser := NewParser(tc.pattern)
+			_, err := parser.Parse()
+			is.True(err != nil)
+			is.True(strings.Contains(err.Error(), "StreamRequest.Bad"))
+			is.True(strings.Contains(err.Error(), "type "+tc.typeName+" is not supported"))
+		})
+	}
+}
+
+func TestParseScalarTypes(t *testing.T) {
+	is := is.New(t)
+	parser := NewParser("./testdata/services/scalars")
+	parser.TypeOverrides["github.com/pacedotdev/oto/testdata/services/scalars.Money"] = TypeOverride{
+		JSType: "string",
+		Format: "money",
+	}
+	def, err := parser.Parse()
+	is.NoErr(err)
+
+	obj, err := def.Object("CreateRequest")
+	is.NoErr(err)
+	is.Equal(obj.Fields[0].Type.TypeName, "time.Time")
+	is.Equal(obj.Fields[0].Type.TypeID, "time.Time")
+	is.Equal(obj.Fields[0].Type.JSType, "string")
+	is.Equal(obj.Fields[0].Type.Format, "date-time")
+	is.Equal(obj.Fields[0].Type.IsObject, false)
+	is.Equal(obj.Fields[1].Type.JSType, "string")
+	is.Equal(obj.Fields[1].Type.Format, "date-time")
+	is.Equal(obj.Fields[1].Type.Multiple, true)
+	is.Equal(obj.Fields[2].Type.JSType, "any")
+	is.Equal(obj.Fields[2].Type.JSTypeUnknown, false)
+	is.Equal(obj.Fields[3].Type.JSType, "string")
+	is.Equal(obj.Fields[3].Type.Format, "money")
+	is.Equal(obj.Fields[4].Type.TypeName, "time.Duration")
+	is.Equal(obj.Fields[4].Type.JSType, "string")
+	is.Equal(obj.Fields[4].Type.Format, "duration")
+	is.Equal(def.Imports["time"], "time")
+
+	for _, object := range def.Objects {
+		switch object.Name {
+		case "Time", "RawMessage", "Money", "Duration":
+			t.Errorf("unexpected object: %s", object.Name)
+		}
+	}
+}
+
+func TestParseSQLNullTypes(t *testing.T) {
+	is := is.New(t)
+	parser := NewParser("./testdata/services/sqlnull")
+	def, err := parser.Parse()
+	is.NoErr(err)
+
+	obj, err := def.Object("FindResponse")
+	is.NoErr(err)
+	is.Equal(obj.Fields[0].Type.TypeName, "sql.NullString")
+	is.Equal(obj.Fields[0].Type.JSType, "string")
+	is.Equal(obj.Fields[0].Type.Nullable, true)
+	is.Equal(obj.Fields[0].Type.IsObject, false)
+	is.Equal(obj.Fields[1].Type.TypeName, "sql.NullInt64")
+	is.Equal(obj.Fields[1].Type.JSType, "number")
+	is.Equal(obj.Fields[1].Type.Nullable, true)
+	is.Equal(obj.Fields[2].Type.JSType, "string")
+	is.Equal(obj.Fields[2].Type.Format, "date-time")
+	is.Equal(def.Imports["database/sql"], "sql")
+	_, err = def.Object("NullString")
+	is.Equal(err, errNotFound)
+
+	// with sql.Null* types as objects
+	parser = NewParser("./testdata/services/sqlnull")
+	for typeID := range SQLNullTypeOverrides() {
+		delete(parser.TypeOverrides, typeID)
+	}
+	def, err = parser.Parse()
+	is.NoErr(err)
+	obj, err = def.Object("FindResponse")
+	is.NoErr(err)
+	is.Equal(obj.Fields[0].Type.IsObject, true)
+	is.Equal(obj.Fields[0].Type.Nullable, false)
+	nullString, err := def.Object("NullString")
+	is.NoErr(err)
+	is.Equal(len(nullString.Fields), 2)
+}
+
+func TestParseBasicJSTypes(t *testing.T) {
+	is := is.New(t)
+	parser := NewParser("./testdata/services/basictypes")
+	def, err := parser.Parse()
+	is.NoErr(err)
+	obj, err := def.Object("BasicRequest")
+	is.NoErr(err)
+
+	expectedJSTypes := map[string]string{
+		"Bool":      "boolean",
+		"String":    "string",
+		"Int":       "number",
+		"Int8":      "number",
+		"Int16":     "number",
+		"Int32":     "number",
+		"Int64":     "number",
+		"Uint":      "number",
+		"Uint8":     "number",
+		"Uint16":    "number",
+		"Uint32":    "number",
+		"Uint64":    "number",
+		"Uintptr":   "number",
+		"Float32":   "number",
+		"Float64":   "number",
+		"Byte":      "number",
+		"Rune":      "number",
+		"Any":       "any",
+		"Interface": "any",
+		"Bytes":     "string",
+		"Uint8s":    "string",
+		"Ints":      "number",
+	}
+	is.Equal(len(obj.Fields), len(expectedJSTypes))
+	for _, field := range obj.Fields {
+		expected, ok := expectedJSTypes[field.Name]
+		if !ok {
+			t.Errorf("unexpected field %s", field.Name)
+			continue
+		}
+		if field.Type.JSType != expected {
+			t.Errorf("%s (%s): expected JSType %q but got %q", field.Name, field.Type.TypeName, expected, field.Type.JSType)
+		}
+		if field.Type.JSTypeUnknown {
+			t.Errorf("%s (%s): unexpected JSTypeUnknown", field.Name, field.Type.TypeName)
+		}
+		if field.Type.Multiple != (field.Type.ElementType != nil) {
+			t.Errorf("%s (%s): ElementType should be set for slices only", field.Name, field.Type.TypeName)
+		}
+	}
+	for _, i := range []int{19, 20} {
+		bytesField := obj.Fields[i]
+		is.True(bytesField.Name == "Bytes" || bytesField.Name == "Uint8s")
+		is.Equal(bytesField.Type.IsBytes, true)
+		is.Equal(bytesField.Type.Multiple, false) // a single base64 string
+		is.Equal(bytesField.Type.TypeName, "[]byte")
+		is.Equal(bytesField.Type.Format, "byte")
+	}
+	intsField := obj.Fields[21]
+	is.Equal(intsField.Name, "Ints")
+	is.Equal(intsField.Type.ElementType.JSType, "number")
+	is.Equal(intsField.Type.ElementType.TypeName, "int")
+}
+
+func TestParseDeprecated(t *testing.T) {
+	is := is.New(t)
+
+	def, err := NewParser("./testdata/services/deprecated").Parse()
+	is.NoErr(err)
+	accounts := def.Services[0]
+	is.Equal(accounts.Name, "AccountService")
+	is.Equal(accounts.Deprecated, false)
+	is.Equal(accounts.Methods[0].Name, "Find")
+	is.Equal(accounts.Methods[0].Deprecated, true)
+	is.Equal(accounts.Methods[0].DeprecationMessage, "use Get, which is faster.")
+	is.True(strings.Contains(accounts.Methods[0].Comment, "Deprecated: use Get")) // stays in the comment
+	is.Equal(accounts.Methods[1].Deprecated, false)
+	legacy := def.Services[1]
+	is.Equal(legacy.Deprecated, true)
+	is.Equal(legacy.DeprecationMessage, "use AccountService instead.")
+
+	getRequest, err := def.Object("GetRequest")
+	is.NoErr(err)
+	is.Equal(getRequest.Deprecated, false)
+	is.Equal(getRequest.Fields[0].Deprecated, false)
+	is.Equal(getRequest.Fields[1].Deprecated, true)
+	is.Equal(getRequest.Fields[1].DeprecationMessage, "use ID.")
+	is.Equal(getRequest.Fields[2].Deprecated, true)
+	is.Equal(getRequest.Fields[2].DeprecationMessage, "use Email.") // not the next paragraph
+	is.Equal(getRequest.Fields[2].Example, "555-0100")
+	getResponse, err := def.Object("GetResponse")
+	is.NoErr(err)
+	is.Equal(getResponse.Deprecated, true)
+	is.Equal(getResponse.DeprecationMessage, "") // not the next paragraph
+	is.Equal(getResponse.Example, map[string]interface{}{"name": "Mat"})
+}
+
+func TestDeprecation(t *testing.T) {
+	is := is.New(t)
+
+	deprecated, message := deprecation("Name is old.\n\nDeprecated: use Other.\n\nThis paragraph is more docs.")
+	is.True(deprecated)
+	is.Equal(message, "use Other.")
+	deprecated, message = deprecation("Name is old.\nDeprecated: use Other,\nwhich is new.\nexample: {\n\"a\": 1\n}")
+	is.True(deprecated)
+	is.Equal(message, "use Other, which is new.") // comment lines end it
+	deprecated, _ = deprecation("Name is not deprecated.")
+	is.True(!deprecated)
+}
+
+func TestIsStdlibPackage(t *testing.T) {
+	is := is.New(t)
+
+	is.True(isStdlibPackage(nil)) // built-in types, like error
+	is.True(isStdlibPackage(types.NewPackage("context", "context")))
+	is.True(isStdlibPackage(types.NewPackage("net/http", "http")))
+	is.True(!isStdlibPackage(types.NewPackage("github.com/pacedotdev/oto", "oto")))
+	is.True(!isStdlibPackage(types.NewPackage("example.com", "example")))
+}
@@ -0,0 +1,290 @@
+package oto
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// ProtoOptions are options for GenerateProto.
+type ProtoOptions struct {
+	// Package is the protobuf package name.
+	// If empty, the PackageName of the Definition is used.
+	Package string
+	// Lock holds previously assigned field numbers, and is updated
+	// with any newly assigned ones. It should be saved alongside the
+	// .proto file so the numbers remain stable.
+	Lock ProtoLock
+}
+
+// ProtoLock holds assigned protobuf field numbers, keyed by
+// Object TypeID, then by field name.
+type ProtoLock map[string]map[string]int
+
+// ReadProtoLock reads a .proto.lock file. If the file does not
+// exist, an empty ProtoLock is returned.
+func ReadProtoLock(path string) (ProtoLock, error) {
+	lock := make(ProtoLock)
+	b, err := ioutil.ReadFile(path)
+	if os.IsNotExist(err) {
+		return lock, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(b, &lock); err != nil {
+		return nil, errors.Wrap(err, "parse proto lock")
+	}
+	return lock, nil
+}
+
+// WriteProtoLock writes the ProtoLock to a .proto.lock file.
+func WriteProtoLock(path string, lock ProtoLock) error {
+	b, err := json.MarshalIndent(lock, "", "\t")
+	if err != nil {
+		return err
+	}
+	return ioutil.WriteFile(path, append(b, '\n'), 0644)
+}
+
+// GenerateProto generates a proto3 file for the Services and Objects
+// in the Definition.
+// Field numbers are assigned alphabetically by field name, and are
+// reused from options.Lock where present.
+func GenerateProto(def Definition, options ProtoOptions) (string, error) {
+	if options.Lock == nil {
+		options.Lock = make(ProtoLock)
+	}
+	packageName := options.Package
+	if packageName == "" {
+		packageName = def.PackageName
+	}
+	imports := make(map[string]struct{})
+	var body bytes.Buffer
+	for _, service := range def.Services {
+		writeProtoComment(&body, "", service.Comment)
+		fmt.Fprintf(&body, "service %s {\n", service.Name)
+		for _, method := range service.Methods {
+			writeProtoComment(&body, "\t", method.Comment)
+			input, output := "google.protobuf.Empty", "google.protobuf.Empty"
+			if method.HasInput {
+				input = method.InputObject.ObjectName
+			}
+			if method.HasOutput {
+				output = method.OutputObject.ObjectName
+			}
+			if !method.HasInput || !method.HasOutput {
+				imports["google/protobuf/empty.proto"] = struct{}{}
+			}
+			fmt.Fprintf(&body, "\trpc %s(%s) returns (%s);\n", method.Name, input, output)
+		}
+		body.WriteString("}\n\n")
+	}
+	for _, object := range def.Objects {
+		numbers := assignProtoFieldNumbers(options.Lock, object)
+		writeProtoComment(&body, "", object.Comment)
+		fmt.Fprintf(&body, "message %s {\n", object.Name)
+		for _, field := range object.Fields {
+			typ, err := protoFieldType(field.Type, imports)
+			if err != nil {
+				return "", errors.Wrapf(err, "%s.%s", object.Name, field.Name)
+			}
+			writeProtoComment(&body, "\t", field.Comment)
+			fmt.Fprintf(&body, "\t%s %s = %d [json_name = %q];\n",
+				typ,
+				snakeDown(field.Name),
+				numbers[field.Name],
+				field.JSONName,
+			)
+		}
+		writeProtoReserved(&body, object, numbers)
+		body.WriteString("}\n\n")
+	}
+	var buf bytes.Buffer
+	buf.WriteString("// Code generated by oto; DO NOT EDIT.\n\n")
+	buf.WriteString("syntax = \"proto3\";\n\n")
+	fmt.Fprintf(&buf, "package %s;\n\n", packageName)
+	if len(imports) > 0 {
+		importPaths := make([]string, 0, len(imports))
+		for importPath := range imports {
+			importPaths = append(importPaths, importPath)
+		}
+		sort.Strings(importPaths)
+		for _, importPath := range importPaths {
+			fmt.Fprintf(&buf, "import %q;\n", importPath)
+		}
+		buf.WriteString("\n")
+	}
+	buf.Write(body.Bytes())
+	return strings.TrimSpace(buf.String()) + "\n", nil
+}
+
+// assignProtoFieldNumbers gets the field numbers for the fields of
+// the Object, adding any new ones to the lock.
+// New fields are numbered alphabetically, after the highest number
+// ever used by the Object, so numbers are never reused.
+func assignProtoFieldNumbers(lock ProtoLock, object Object) map[string]int {
+	numbers := lock[object.TypeID]
+	if numbers == nil {
+		numbers = make(map[string]int)
+		lock[object.TypeID] = numbers
+	}
+	var highest int
+	for _, number := range numbers {
+		if number > highest {
+			highest = number
+		}
+	}
+	names := make([]string, 0, len(object.Fields))
+	for _, field := range object.Fields {
+		names = append(names, field.Name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		if _, ok := numbers[name]; ok {
+			continue
+		}
+		highest++
+		numbers[name] = highest
+	}
+	return numbers
+}
+
+// writeProtoReserved writes reserved statements for the locked
+// field numbers that are no longer used by the Object.
+func writeProtoReserved(buf *bytes.Buffer, object Object, numbers map[string]int) {
+	used := make(map[string]struct{}, len(object.Fields))
+	for _, field := range object.Fields {
+		used[field.Name] = struct{}{}
+	}
+	var removed []string
+	for name := range numbers {
+		if _, ok := used[name]; !ok {
+			removed = append(removed, name)
+		}
+	}
+	sort.Slice(removed, func(i, j int) bool {
+		return numbers[removed[i]] < numbers[removed[j]]
+	})
+	for _, name := range removed {
+		fmt.Fprintf(buf, "\treserved %d;\n", numbers[name])
+		fmt.Fprintf(buf, "\treserved %q;\n", snakeDown(name))
+	}
+}
+
+// protoFieldType gets the protobuf type for the FieldType, including
+// the repeated or optional label.
+// Any well-known types that are used are added to imports.
+func protoFieldType(ftype FieldType, imports map[string]struct{}) (string, error) {
+	if ftype.IsBytes {
+		return "bytes", nil
+	}
+	if ftype.Multiple && ftype.ElementType != nil && (ftype.ElementType.Multiple || ftype.ElementType.IsMap) {
+		return "", errors.New("repeated fields cannot contain lists or maps")
+	}
+	if ftype.IsMap {
+		if ftype.Multiple {
+			return "", errors.New("repeated fields cannot contain lists or maps")
+		}
+		if ftype.MapKeyType == nil || ftype.MapValueType == nil {
+			return "", errors.New("missing map key or value type")
+		}
+		keyType, err := protoMapKeyType(*ftype.MapKeyType)
+		if err != nil {
+			return "", err
+		}
+		valueType := *ftype.MapValueType
+		if valueType.IsBytes {
+			return "map<" + keyType + ", bytes>", nil
+		}
+		if valueType.IsMap || valueType.Multiple {
+			return "", errors.New("map values cannot be lists or maps")
+		}
+		return "map<" + keyType + ", " + protoType(valueType, imports) + ">", nil
+	}
+	typ := protoType(ftype, imports)
+	switch {
+	case ftype.Multiple:
+		return "repeated " + typ, nil
+	case ftype.Nullable && !ftype.IsObject && !strings.HasPrefix(typ, "google.protobuf."):
+		return "optional " + typ, nil
+	}
+	return typ, nil
+}
+
+// protoType gets the protobuf type for a single value of the FieldType.
+func protoType(ftype FieldType, imports map[string]struct{}) string {
+	if ftype.IsObject {
+		return ftype.ObjectName
+	}
+	if typ, ok := protoScalarType(ftype.TypeName); ok {
+		return typ
+	}
+	if ftype.TypeName == "time.Time" {
+		imports["google/protobuf/timestamp.proto"] = struct{}{}
+		return "google.protobuf.Timestamp"
+	}
+	switch ftype.JSType {
+	case "string":
+		return "string"
+	case "boolean":
+		return "bool"
+	case "number":
+		return "double"
+	case "object":
+		imports["google/protobuf/struct.proto"] = struct{}{}
+		return "google.protobuf.Struct"
+	}
+	imports["google/protobuf/struct.proto"] = struct{}{}
+	return "google.protobuf.Value"
+}
+
+// protoScalarType gets the protobuf scalar type for the Go
+// built-in type.
+func protoScalarType(typeName string) (string, bool) {
+	switch typeName {
+	case "string":
+		return "string", true
+	case "bool":
+		return "bool", true
+	case "int8", "int16", "int32", "rune":
+		return "int32", true
+	case "int", "int64":
+		return "int64", true
+	case "uint8", "byte", "uint16", "uint32":
+		return "uint32", true
+	case "uint", "uint64", "uintptr":
+		return "uint64", true
+	case "float32":
+		return "float", true
+	case "float64":
+		return "double", true
+	}
+	return "", false
+}
+
+// protoMapKeyType gets the protobuf map key type.
+// Only strings, bools and integers may be used as keys.
+func protoMapKeyType(ftype FieldType) (string, error) {
+	keyType, ok := protoScalarType(ftype.TypeName)
+	if ftype.Multiple || !ok || keyType == "float" || keyType == "double" {
+		return "", errors.Errorf("map key type %s is not supported", ftype.TypeName)
+	}
+	return keyType, nil
+}
+
+// writeProtoComment writes the comment as // lines.
+func writeProtoComment(buf *bytes.Buffer, indent, comment string) {
+	if comment == "" {
+		return
+	}
+	for _, line := range strings.Split(comment, "\n") {
+		fmt.Fprintf(buf, "%s// %s\n", indent, line)
+	}
+}
@@ -0,0 +1,174 @@
+package oto
+
+import (
+	"context"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/bufbuild/protocompile"
+	"github.com/bufbuild/protocompile/linker"
+	"github.com/matryer/is"
+)
+
+func TestGenerateProto(t *testing.T) {
+	is := is.New(t)
+	def, err := NewParser("./testdata/services/protobuf").Parse()
+	is.NoErr(err)
+
+	s, err := GenerateProto(def, ProtoOptions{})
+	is.NoErr(err)
+	file := compileProto(t, s)
+	is.Equal(string(file.Package()), "protobuf")
+
+	service := file.Services().ByName("StoreService")
+	is.True(service != nil)
+	put := service.Methods().ByName("Put")
+	is.Equal(string(put.Input().Name()), "PutRequest")
+	is.Equal(string(put.Output().Name()), "PutResponse")
+
+	putRequest := file.Messages().ByName("PutRequest").Fields()
+	// numbered alphabetically
+	is.Equal(int(putRequest.ByName("data").Number()), 1)
+	is.Equal(int(putRequest.ByName("expires").Number()), 2)
+	is.Equal(int(putRequest.ByName("key").Number()), 3)
+	is.Equal(int(putRequest.ByName("versions").Number()), 9)
+	is.Equal(putRequest.ByName("data").Kind().String(), "bytes")
+	is.Equal(putRequest.ByName("labels").IsMap(), true)
+	is.Equal(putRequest.ByName("versions").MapKey().Kind().String(), "int64")
+	is.Equal(string(putRequest.ByName("versions").MapValue().Message().Name()), "Version")
+	is.Equal(string(putRequest.ByName("expires").Message().FullName()), "google.protobuf.Timestamp")
+	is.Equal(putRequest.ByName("priority").HasPresence(), true)
+	is.Equal(putRequest.ByName("priority").Kind().String(), "int32")
+	is.Equal(putRequest.ByName("ratio").Kind().String(), "float")
+	is.Equal(string(putRequest.ByName("value").Message().FullName()), "google.protobuf.Value")
+	is.Equal(putRequest.ByName("tags").IsList(), true)
+	is.Equal(putRequest.ByName("key").JSONName(), "key")
+
+	version := file.Messages().ByName("Version").Fields()
+	is.Equal(version.ByName("number").Kind().String(), "uint64")
+	is.True(strings.Contains(s, "// Key is the key.\n"))
+}
+
+func TestGenerateProtoWithoutPayloads(t *testing.T) {
+	is := is.New(t)
+	def, err := NewParser("./testdata/services/payloads").Parse()
+	is.NoErr(err)
+
+	s, err := GenerateProto(def, ProtoOptions{})
+	is.NoErr(err)
+	file := compileProto(t, s)
+	service := file.Services().ByName("HealthService")
+	ping := service.Methods().ByName("Ping")
+	is.Equal(string(ping.Input().FullName()), "google.protobuf.Empty")
+	is.Equal(string(ping.Output().FullName()), "google.protobuf.Empty")
+	status := service.Methods().ByName("Status")
+	is.Equal(string(status.Input().FullName()), "google.protobuf.Empty")
+	is.Equal(string(status.Output().Name()), "StatusResponse")
+}
+
+func TestGenerateProtoJSONNames(t *testing.T) {
+	is := is.New(t)
+	def, err := NewParser("./testdata/services/tags").Parse()
+	is.NoErr(err)
+
+	s, err := GenerateProto(def, ProtoOptions{})
+	is.NoErr(err)
+	file := compileProto(t, s)
+	createRequest := file.Messages().ByName("CreateRequest").Fields()
+	is.Equal(createRequest.ByName("user_id").JSONName(), "user_id") // from the json tag
+	is.Equal(createRequest.ByName("nickname").JSONName(), "nickname")
+	is.Equal(createRequest.ByName("external_id").JSONName(), "external-id")
+}
+
+func TestGenerateProtoLock(t *testing.T) {
+	is := is.New(t)
+	parser := NewParser("./testdata/services/pleasantries")
+	parser.ExcludeInterfaces = []string{"Ignorer"}
+	def, err := parser.Parse()
+	is.NoErr(err)
+	greetRequest, err := def.Object("GreetRequest")
+	is.NoErr(err)
+	welcomeRequest, err := def.Object("WelcomeRequest")
+	is.NoErr(err)
+
+	lock := ProtoLock{
+		greetRequest.TypeID: {
+			"OldName": 1,
+		},
+		welcomeRequest.TypeID: {
+			"To":   1,
+			"Name": 2,
+		},
+	}
+	s, err := GenerateProto(def, ProtoOptions{Lock: lock})
+	is.NoErr(err)
+	file := compileProto(t, s)
+
+	greetFields := file.Messages().ByName("GreetRequest").Fields()
+	is.Equal(int(greetFields.ByName("names").Number()), 2)
+	is.Equal(file.Messages().ByName("GreetRequest").ReservedRanges().Has(1), true)
+	is.Equal(file.Messages().ByName("GreetRequest").ReservedNames().Has("old_name"), true)
+	welcomeFields := file.Messages().ByName("WelcomeRequest").Fields()
+	is.Equal(int(welcomeFields.ByName("to").Number()), 1)
+	is.Equal(int(welcomeFields.ByName("name").Number()), 2)
+	// new fields come after the locked ones
+	is.Equal(int(welcomeFields.ByName("new_customer").Number()), 3)
+	is.Equal(int(welcomeFields.ByName("times").Number()), 4)
+	is.Equal(lock[welcomeRequest.TypeID]["Times"], 4)
+
+	// the lock is saved and reused
+	lockFile := filepath.Join(t.TempDir(), "api.proto.lock")
+	is.NoErr(WriteProtoLock(lockFile, lock))
+	readLock, err := ReadProtoLock(lockFile)
+	is.NoErr(err)
+	is.Equal(readLock, lock)
+	s2, err := GenerateProto(def, ProtoOptions{Lock: readLock})
+	is.NoErr(err)
+	is.Equal(s2, s)
+
+	missingLock, err := ReadProtoLock(filepath.Join(t.TempDir(), "missing.proto.lock"))
+	is.NoErr(err)
+	is.Equal(len(missingLock), 0)
+}
+
+func TestGenerateProtoUnsupportedMapKey(t *testing.T) {
+	is := is.New(t)
+	def := Definition{
+		PackageName: "bad",
+		Objects: []Object{{
+			Name:   "Thing",
+			TypeID: "bad.Thing",
+			Fields: []Field{{
+				Name: "Lookup",
+				Type: FieldType{
+					TypeName:     "map[float64]string",
+					IsMap:        true,
+					MapKeyType:   &FieldType{TypeName: "float64", JSType: "number"},
+					MapValueType: &FieldType{TypeName: "string", JSType: "string"},
+				},
+			}},
+		}},
+	}
+	_, err := GenerateProto(def, ProtoOptions{})
+	is.True(err != nil)
+	is.Equal(err.Error(), "Thing.Lookup: map key type float64 is not supported")
+}
+
+// compileProto compiles the proto source, failing the test if
+// it is invalid.
+func compileProto(t *testing.T, s string) linker.File {
+	t.Helper()
+	compiler := protocompile.Compiler{
+		Resolver: protocompile.WithStandardImports(&protocompile.SourceResolver{
+			Accessor: protocompile.SourceAccessorFromMap(map[string]string{
+				"api.proto": s,
+			}),
+		}),
+	}
+	files, err := compiler.Compile(context.Background(), "api.proto")
+	if err != nil {
+		t.Fatalf("%s\n%s", err, s)
+	}
+	return files[0]
+}
@@ -0,0 +1,215 @@
+package oto
+
+import (
+	"bytes"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// GeneratePython generates Python (3.9+) dataclasses for the Objects
+// in the Definition, and abstract base classes for the Services.
+// Names are converted to snake_case.
+func GeneratePython(def Definition) (string, error) {
+	var buf bytes.Buffer
+	buf.WriteString(`# Code generated by oto; DO NOT EDIT.
+
+from __future__ import annotations
+
+import datetime
+from abc import ABC, abstractmethod
+from dataclasses import dataclass, field
+from typing import Any, Dict, List, Optional
+`)
+	for _, object := range def.Objects {
+		buf.WriteString("\n\n@dataclass\n")
+		fmt.Fprintf(&buf, "class %s:\n", object.Name)
+		writePythonDocstring(&buf, "    ", object.Comment)
+		// fields with defaults must come after
+		// fields without them
+		var required, optional []Field
+		for _, field := range object.Fields {
+			if field.Required && !field.HasDefault {
+				required = append(required, field)
+				continue
+			}
+			optional = append(optional, field)
+		}
+		for _, field := range required {
+			fmt.Fprintf(&buf, "    %s: %s\n", pythonName(field.Name), pythonType(field.Type))
+		}
+		for _, field := range optional {
+			typ := pythonType(field.Type)
+			if field.HasDefault && field.Default != nil {
+				fmt.Fprintf(&buf, "    %s: %s = %s\n", pythonName(field.Name), typ, pythonDefault(field.Default))
+				continue
+			}
+			if !strings.HasPrefix(typ, "Optional[") {
+				typ = "Optional[" + typ + "]"
+			}
+			fmt.Fprintf(&buf, "    %s: %s = None\n", pythonName(field.Name), typ)
+		}
+		if object.Comment == "" && len(object.Fields) == 0 {
+			buf.WriteString("    pass\n")
+		}
+	}
+	for _, service := range def.Services {
+		fmt.Fprintf(&buf, "\n\nclass %s(ABC):\n", service.Name)
+		writePythonDocstring(&buf, "    ", service.Comment)
+		for i, method := range service.Methods {
+			if i > 0 || service.Comment != "" {
+				buf.WriteString("\n")
+			}
+			buf.WriteString("    @abstractmethod\n")
+			params := "self"
+			if method.HasInput {
+				params += fmt.Sprintf(", %s: %s", pythonName(method.InputObject.ObjectName), method.InputObject.ObjectName)
+			}
+			result := "None"
+			if method.HasOutput {
+				result = method.OutputObject.ObjectName
+			}
+			fmt.Fprintf(&buf, "    def %s(%s) -> %s:\n", pythonName(method.Name), params, result)
+			writePythonDocstring(&buf, "        ", method.Comment)
+			buf.WriteString("        ...\n")
+		}
+		if service.Comment == "" && len(service.Methods) == 0 {
+			buf.WriteString("    pass\n")
+		}
+	}
+	return buf.String(), nil
+}
+
+// pythonType gets the Python type hint for the FieldType.
+func pythonType(ftype FieldType) string {
+	if ftype.IsBytes {
+		return "bytes"
+	}
+	var typ string
+	switch {
+	case ftype.IsObject:
+		typ = ftype.ObjectName
+	case ftype.IsMap:
+		valueType := "Any"
+		if ftype.MapValueType != nil {
+			valueType = pythonType(*ftype.MapValueType)
+		}
+		typ = "Dict[str, " + valueType + "]"
+	case ftype.JSType == "string" && ftype.Format == "date-time":
+		typ = "datetime.datetime"
+	case ftype.JSType == "string":
+		typ = "str"
+	case ftype.JSType == "boolean":
+		typ = "bool"
+	case ftype.JSType == "number" && isIntegerTypeName(ftype.TypeName):
+		typ = "int"
+	case ftype.JSType == "number":
+		typ = "float"
+	case ftype.JSType == "object":
+		typ = "Dict[str, Any]"
+	default:
+		typ = "Any"
+	}
+	if isNestedSlice(ftype) {
+		typ = pythonType(*ftype.ElementType)
+	}
+	if ftype.Multiple {
+		typ = "List[" + typ + "]"
+	}
+	if ftype.Nullable && typ != "Any" {
+		typ = "Optional[" + typ + "]"
+	}
+	return typ
+}
+
+// pythonName gets a snake_case Python identifier for
+// the name.
+// pythonDefault gets the dataclass field for a default value, using
+// a default_factory for lists and dicts, which cannot be shared.
+func pythonDefault(value interface{}) string {
+	switch value := value.(type) {
+	case []interface{}:
+		if len(value) == 0 {
+			return "field(default_factory=list)"
+		}
+		return "field(default_factory=lambda: " + pythonLiteral(value) + ")"
+	case map[string]interface{}:
+		if len(value) == 0 {
+			return "field(default_factory=dict)"
+		}
+		return "field(default_factory=lambda: " + pythonLiteral(value) + ")"
+	}
+	return "field(default=" + pythonLiteral(value) + ")"
+}
+
+// pythonLiteral gets the Python literal for a JSON value.
+func pythonLiteral(value interface{}) string {
+	switch value := value.(type) {
+	case nil:
+		return "None"
+	case bool:
+		if value {
+			return "True"
+		}
+		return "False"
+	case float64:
+		return strconv.FormatFloat(value, 'f', -1, 64)
+	case string:
+		return strconv.Quote(value)
+	case []interface{}:
+		items := make([]string, 0, len(value))
+		for _, item := range value {
+			items = append(items, pythonLiteral(item))
+		}
+		return "[" + strings.Join(items, ", ") + "]"
+	case map[string]interface{}:
+		keys := make([]string, 0, len(value))
+		for key := range value {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		items := make([]string, 0, len(value))
+		for _, key := range keys {
+			items = append(items, strconv.Quote(key)+": "+pythonLiteral(value[key]))
+		}
+		return "{" + strings.Join(items, ", ") + "}"
+	}
+	return "None"
+}
+
+func pythonName(name string) string {
+	name = snakeDown(name)
+	if pythonKeywords[name] {
+		name += "_"
+	}
+	return name
+}
+
+var pythonKeywords = map[string]bool{
+	"and": true, "as": true, "assert": true, "async": true, "await": true,
+	"break": true, "class": true, "continue": true, "def": true, "del": true,
+	"elif": true, "else": true, "except": true, "finally": true, "for": true,
+	"from": true, "global": true, "if": true, "import": true, "in": true,
+	"is": true, "lambda": true, "nonlocal": true, "not": true, "or": true,
+	"pass": true, "raise": true, "return": true, "try": true, "while": true,
+	"with": true, "yield": true, "self": true,
+}
+
+func writePythonDocstring(buf *bytes.Buffer, indent, comment string) {
+	if comment == "" {
+		return
+	}
+	comment = strings.Replace(comment, `\`, `\\`, -1)
+	comment = strings.Replace(comment, `"""`, `\"\"\"`, -1)
+	lines := strings.Split(comment, "\n")
+	if len(lines) == 1 {
+		buf.WriteString(indent + `"""` + comment + `"""` + "\n")
+		return
+	}
+	buf.WriteString(indent + `"""` + lines[0] + "\n")
+	for _, line := range lines[1:] {
+		buf.WriteString(strings.TrimRight(indent+line, " ") + "\n")
+	}
+	buf.WriteString(indent + `"""` + "\n")
+}
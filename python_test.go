@@ -0,0 +1,118 @@
+package oto
+
+import (
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/matryer/is"
+)
+
+func TestGeneratePython(t *testing.T) {
+	is := is.New(t)
+	parser := NewParser("./testdata/services/pleasantries")
+	parser.ExcludeInterfaces = []string{"Ignorer"}
+	def, err := parser.Parse()
+	is.NoErr(err)
+
+	s, err := GeneratePython(def)
+	is.NoErr(err)
+	for _, should := range []string{
+		"from __future__ import annotations",
+		"@dataclass\nclass GetGreetingsResponse:\n",
+		"    greetings: List[Greeting]\n    error: Optional[str] = None\n",
+		"    times: int\n",
+		"    new_customer: bool\n",
+		"class GreeterService(ABC):\n",
+		"    @abstractmethod\n    def greet(self, greet_request: GreetRequest) -> GreetResponse:\n",
+	} {
+		if !strings.Contains(s, should) {
+			t.Errorf("missing: %q", should)
+		}
+	}
+	checkPython(t, s)
+}
+
+func TestGeneratePythonTypes(t *testing.T) {
+	is := is.New(t)
+	parser := NewParser("./testdata/services/scalars")
+	parser.TypeOverrides["github.com/pacedotdev/oto/testdata/services/scalars.Money"] = TypeOverride{JSType: "string"}
+	def, err := parser.Parse()
+	is.NoErr(err)
+	s, err := GeneratePython(def)
+	is.NoErr(err)
+	is.True(strings.Contains(s, "    starts_at: datetime.datetime\n"))
+	is.True(strings.Contains(s, "    reminders: List[datetime.datetime]\n"))
+	checkPython(t, s)
+
+	parser = NewParser("./testdata/services/basictypes")
+	def, err = parser.Parse()
+	is.NoErr(err)
+	s, err = GeneratePython(def)
+	is.NoErr(err)
+	is.True(strings.Contains(s, "    bytes: bytes\n"))
+	is.True(strings.Contains(s, "    float_32: float\n"))
+	is.True(strings.Contains(s, "    any: Any\n"))
+	checkPython(t, s)
+}
+
+func TestGeneratePythonDefaults(t *testing.T) {
+	is := is.New(t)
+	def, err := NewParser("./testdata/services/defaults").Parse()
+	is.NoErr(err)
+	s, err := GeneratePython(def)
+	is.NoErr(err)
+	is.True(strings.Contains(s, "from dataclasses import dataclass, field\n"))
+	is.True(strings.Contains(s, "    query: str\n"))
+	is.True(strings.Contains(s, "    limit: int = field(default=20)\n"))
+	is.True(strings.Contains(s, "    sort: str = field(default=\"relevance\")\n"))
+	is.True(strings.Contains(s, "    cursor: Optional[str] = None\n")) // null
+	is.True(strings.Contains(s, "    filter: str\n"))                  // invalid, so no default
+	is.True(strings.Contains(s, "    tags: List[str] = field(default_factory=lambda: [\"new\"])\n"))
+	is.True(strings.Contains(s, "    exact: bool = field(default=False)\n"))
+	checkPython(t, s)
+}
+
+func TestPythonDefault(t *testing.T) {
+	is := is.New(t)
+	is.Equal(pythonDefault(1.5), "field(default=1.5)")
+	is.Equal(pythonDefault(true), "field(default=True)")
+	is.Equal(pythonDefault("a \"b\""), `field(default="a \"b\"")`)
+	is.Equal(pythonDefault([]interface{}{}), "field(default_factory=list)")
+	is.Equal(pythonDefault(map[string]interface{}{}), "field(default_factory=dict)")
+	is.Equal(pythonDefault(map[string]interface{}{"b": nil, "a": []interface{}{1.0, "x"}}), `field(default_factory=lambda: {"a": [1, "x"], "b": None})`)
+}
+
+// checkPython runs the source with python3 (and type checks it
+// with mypy) if they are installed.
+func checkPython(t *testing.T, src string) {
+	python, err := exec.LookPath("python3")
+	if err != nil {
+		t.Log("python3 not installed, skipping check")
+		return
+	}
+	dir, err := ioutil.TempDir("", "oto-python")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	filename := filepath.Join(dir, "oto_gen.py")
+	if err := ioutil.WriteFile(filename, []byte(src), 0644); err != nil {
+		t.Fatal(err)
+	}
+	out, err := exec.Command(python, filename).CombinedOutput()
+	if err != nil {
+		t.Errorf("python3: %s\n%s\n%s", err, out, src)
+	}
+	mypy, err := exec.LookPath("mypy")
+	if err != nil {
+		return
+	}
+	out, err = exec.Command(mypy, "--strict", filename).CombinedOutput()
+	if err != nil {
+		t.Errorf("mypy: %s\n%s", err, out)
+	}
+}
@@ -1,4 +1,4 @@
-package main
+package oto
 
 import (
 	"bytes"
@@ -6,6 +6,8 @@ import (
 	"go/doc"
 	"html/template"
 	"strings"
+	"unicode"
+	"unicode/utf8"
 
 	"github.com/fatih/structtag"
 	"github.com/gobuffalo/plush"
@@ -15,10 +17,12 @@ import (
 
 var defaultRuleset = inflect.NewDefaultRuleset()
 
-// render renders the template using the Definition.
-func render(template string, def Definition, params map[string]interface{}) (string, error) {
+// Render renders the template using the Definition.
+func Render(template string, def Definition, params map[string]interface{}) (string, error) {
 	ctx := plush.NewContext()
 	ctx.Set("camelize_down", camelizeDown)
+	ctx.Set("snake_down", snakeDown)
+	ctx.Set("kebab_down", kebabDown)
 	ctx.Set("def", def)
 	ctx.Set("params", params)
 	ctx.Set("json", toJSONHelper)
@@ -70,7 +74,51 @@ func camelizeDown(word string) string {
 		}
 	}
 	word = strings.Join(words, "")
-	return strings.ToLower(word[:1]) + word[1:]
+	first, size := utf8.DecodeRuneInString(word)
+	return string(unicode.ToLower(first)) + word[size:]
+}
+
+// snakeDown converts a name or other string into a lowercase
+// snake case version. "ModelID" becomes "model_id".
+func snakeDown(word string) string {
+	return strings.Join(nameWords(word), "_")
+}
+
+// kebabDown converts a name or other string into a lowercase
+// kebab case version. "ModelID" becomes "model-id".
+func kebabDown(word string) string {
+	return strings.Join(nameWords(word), "-")
+}
+
+// nameWords splits a name into lowercase words for snakeDown and
+// kebabDown. Acronyms are one word, even when they are plural
+// ("UserIDs" is "user", "ids"), digits are words of their own
+// ("Float32" is "float", "32") and other characters (like _ and -)
+// separate words.
+func nameWords(name string) []string {
+	split := Split(name)
+	words := make([]string, 0, len(split))
+	for i := 0; i < len(split); i++ {
+		word := split[i]
+		if i+1 < len(split) && isPluralAcronymEnd(word, split[i+1]) {
+			// Split makes "IDs" into "I", "Ds"
+			word += split[i+1]
+			i++
+		}
+		if first, _ := utf8.DecodeRuneInString(word); unicode.IsLetter(first) || unicode.IsDigit(first) {
+			words = append(words, strings.ToLower(word))
+		}
+	}
+	return words
+}
+
+// isPluralAcronymEnd gets whether next is the end of a plural
+// acronym that starts with word, like "I" and "Ds" for "IDs".
+func isPluralAcronymEnd(word, next string) bool {
+	if len(next) != 2 || next[1] != 's' || !unicode.IsUpper(rune(next[0])) {
+		return false
+	}
+	return word != "" && strings.ToUpper(word) == word
 }
 
 // formatTags formats a list of struct tag strings into one.
@@ -1,6 +1,8 @@
-package main
+package oto
 
 import (
+	"go/format"
+	"io/ioutil"
 	"strings"
 	"testing"
 
@@ -17,7 +19,7 @@ func TestRender(t *testing.T) {
 	}
 	template := `// <%= params["Description"] %>
 package <%= def.PackageName %>`
-	s, err := render(template, def, params)
+	s, err := Render(template, def, params)
 	is.NoErr(err)
 	for _, should := range []string{
 		"// Package services contains services.",
@@ -30,12 +32,98 @@ package <%= def.PackageName %>`
 	}
 }
 
+func TestRenderOtoHTTPTemplatesNoInput(t *testing.T) {
+	is := is.New(t)
+	def, err := NewParser("./testdata/services/payloads").Parse()
+	is.NoErr(err)
+	for template, shoulds := range map[string][]string{
+		"server.go.plush": {
+			"Status(context.Context) (*StatusResponse, error)",
+			// no request to decode
+			"handleStatus(w http.ResponseWriter, r *http.Request) {\n\tresponse, err := s.healthService.Status(r.Context())",
+		},
+		"client.go.plush": {
+			"func (s *HealthService) Status(ctx context.Context) (*StatusResponse, error) {\n\t// Status takes no request, so there is no body\n",
+		},
+		"client.js.plush": {
+			"async status() {\n\t\tconst response = await fetch('/oto/HealthService.Status', {\n\t\t\tmethod: 'POST',\n\t\t\theaders: {\n\t\t\t\t'Accept': 'application/json',\n\t\t\t},\n\t\t})",
+		},
+		"client.ts.plush": {
+			"async status(): Promise<StatusResponse> {",
+			"'HealthService.Status', {\n\t\t\tmethod: 'POST',\n\t\t\theaders: headers,\n\t\t})",
+		},
+	} {
+		b, err := ioutil.ReadFile("./otohttp/templates/" + template)
+		is.NoErr(err)
+		s, err := Render(string(b), def, nil)
+		is.NoErr(err)
+		for _, should := range shoulds {
+			if !strings.Contains(s, should) {
+				t.Errorf("%s: missing: %s", template, should)
+			}
+		}
+	}
+}
+
+func TestRenderOtoHTTPTemplatesNoOutput(t *testing.T) {
+	is := is.New(t)
+	def, err := NewParser("./testdata/services/payloads").Parse()
+	is.NoErr(err)
+	for template, shoulds := range map[string][]string{
+		"server.go.plush": {
+			"Reset(context.Context, ResetRequest) error",
+			"err := s.healthService.Reset(r.Context(), request)",
+			"w.WriteHeader(http.StatusNoContent)",
+		},
+		"client.go.plush": {
+			"func (s *HealthService) Reset(ctx context.Context, r ResetRequest) error {",
+			"if resp.StatusCode == http.StatusNoContent {\n\t\treturn nil\n\t}",
+		},
+		"client.js.plush": {
+			"if (response.status === 204) {",
+		},
+		"client.ts.plush": {
+			"async reset(resetRequest: ResetRequest = null): Promise<void> {",
+			"async status(): Promise<StatusResponse> {",
+		},
+	} {
+		b, err := ioutil.ReadFile("./otohttp/templates/" + template)
+		is.NoErr(err)
+		s, err := Render(string(b), def, nil)
+		is.NoErr(err)
+		for _, should := range shoulds {
+			if !strings.Contains(s, should) {
+				t.Errorf("%s: missing: %s", template, should)
+			}
+		}
+		if strings.HasSuffix(template, ".go.plush") {
+			_, err := format.Source([]byte(s))
+			is.NoErr(err) // generated Go code should parse
+		}
+	}
+}
+
+func TestRenderOtoHTTPTemplatesJSONNames(t *testing.T) {
+	is := is.New(t)
+	def, err := NewParser("./testdata/services/tags").Parse()
+	is.NoErr(err)
+	b, err := ioutil.ReadFile("./otohttp/templates/client.ts.plush")
+	is.NoErr(err)
+	s, err := Render(string(b), def, nil)
+	is.NoErr(err)
+	// json:"external-id" is not an identifier, so it is quoted
+	is.True(strings.Contains(s, `this["external-id"] = data["external-id"];`))
+	is.True(strings.Contains(s, "\t\"external-id\": "))
+	is.True(strings.Contains(s, `this["user_id"] = data["user_id"];`))
+}
+
 func TestCamelizeDown(t *testing.T) {
 	for in, expected := range map[string]string{
 		"CamelsAreGreat": "camelsAreGreat",
 		"ID":             "id",
 		"HTML":           "html",
 		"PreviewHTML":    "previewHTML",
+		"Ünits":          "ünits",
 	} {
 		actual := camelizeDown(in)
 		if actual != expected {
@@ -44,6 +132,30 @@ func TestCamelizeDown(t *testing.T) {
 	}
 }
 
+func TestSnakeDown(t *testing.T) {
+	for in, expected := range map[string]string{
+		"CamelsAreGreat": "camels_are_great",
+		"ID":             "id",
+		"UserID":         "user_id",
+		"PreviewHTML":    "preview_html",
+		"HTMLParser":     "html_parser",
+		"greet":          "greet",
+		"UserIDs":        "user_ids",
+		"URLs":           "urls",
+		"ImageURL":       "image_url",
+		"Float32":        "float_32",
+		"GL11Version":    "gl_11_version",
+		"already_snake":  "already_snake",
+		"Has-Dash":       "has_dash",
+		"As":             "as",
+	} {
+		actual := snakeDown(in)
+		if actual != expected {
+			t.Errorf("%s expected: %q but got %q", in, expected, actual)
+		}
+	}
+}
+
 func TestFormatTags(t *testing.T) {
 	is := is.New(t)
 
@@ -66,3 +178,21 @@ func TestFormatTags(t *testing.T) {
 	is.Equal(trimBackticks(string(tagStr)), `json:"field,omitempty" monkey:"true"`)
 
 }
+
+func TestKebabDown(t *testing.T) {
+	for in, expected := range map[string]string{
+		"CamelsAreGreat": "camels-are-great",
+		"ID":             "id",
+		"UserID":         "user-id",
+		"UserIDs":        "user-ids",
+		"HTTPSProxy":     "https-proxy",
+		"GetGreetings":   "get-greetings",
+		"Line2":          "line-2",
+		"snake_case":     "snake-case",
+	} {
+		actual := kebabDown(in)
+		if actual != expected {
+			t.Errorf("%s expected: %q but got %q", in, expected, actual)
+		}
+	}
+}
@@ -1,4 +1,4 @@
-package main
+package oto
 
 /*
 	from https://github.com/fatih/camelcase
@@ -0,0 +1,20 @@
+// Package apiversion is the API for widgets.
+//
+// oto:version 1.2.3
+package apiversion
+
+// WidgetService manages widgets.
+type WidgetService interface {
+	// Get gets a widget.
+	Get(GetRequest) GetResponse
+}
+
+// GetRequest is the request for WidgetService.Get.
+type GetRequest struct {
+	ID string
+}
+
+// GetResponse is the response for WidgetService.Get.
+type GetResponse struct {
+	Name string
+}
@@ -0,0 +1,20 @@
+package apiversionconst
+
+// Version is the version of the API.
+const Version = "2.0.0"
+
+// WidgetService manages widgets.
+type WidgetService interface {
+	// Get gets a widget.
+	Get(GetRequest) GetResponse
+}
+
+// GetRequest is the request for WidgetService.Get.
+type GetRequest struct {
+	ID string
+}
+
+// GetResponse is the response for WidgetService.Get.
+type GetResponse struct {
+	Name string
+}
@@ -0,0 +1,31 @@
+package auth
+
+// AccountService manages accounts.
+// oto:auth apikey header X-API-Key
+type AccountService interface {
+	// Get gets an account.
+	Get(GetRequest) GetResponse
+	// Health checks the service is up.
+	// oto:auth none
+	Health(GetRequest) GetResponse
+	// Delete deletes an account.
+	// oto:auth oauth2 scopes:accounts.read,accounts.write
+	Delete(GetRequest) GetResponse
+}
+
+// ProfileService manages profiles.
+// oto:auth bearer
+type ProfileService interface {
+	// Update updates a profile.
+	Update(GetRequest) GetResponse
+}
+
+// GetRequest is the request for AccountService.Get.
+type GetRequest struct {
+	ID string
+}
+
+// GetResponse is the response for AccountService.Get.
+type GetResponse struct {
+	Name string
+}
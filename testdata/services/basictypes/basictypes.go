@@ -0,0 +1,36 @@
+package basictypes
+
+// BasicService uses all the basic types.
+type BasicService interface {
+	// Basic does basic things.
+	Basic(BasicRequest) BasicResponse
+}
+
+// BasicRequest is the request object for BasicService.Basic.
+type BasicRequest struct {
+	Bool      bool
+	String    string
+	Int       int
+	Int8      int8
+	Int16     int16
+	Int32     int32
+	Int64     int64
+	Uint      uint
+	Uint8     uint8
+	Uint16    uint16
+	Uint32    uint32
+	Uint64    uint64
+	Uintptr   uintptr
+	Float32   float32
+	Float64   float64
+	Byte      byte
+	Rune      rune
+	Any       any
+	Interface interface{}
+	Bytes     []byte
+	Uint8s    []uint8
+	Ints      []int
+}
+
+// BasicResponse is the response object for BasicService.Basic.
+type BasicResponse struct{}
@@ -0,0 +1,38 @@
+package constraints
+
+// Number is a constraint for generic code, not a service.
+type Number interface {
+	~int | ~int64 | ~float64
+}
+
+// Key is a constraint that embeds comparable.
+type Key interface {
+	comparable
+	String() string
+}
+
+// Sum adds up the numbers.
+func Sum[T Number](numbers ...T) T {
+	var total T
+	for _, n := range numbers {
+		total += n
+	}
+	return total
+}
+
+// CalculatorService does sums.
+type CalculatorService interface {
+	// Add adds two numbers.
+	Add(AddRequest) AddResponse
+}
+
+// AddRequest is the request for CalculatorService.Add.
+type AddRequest struct {
+	A int
+	B int
+}
+
+// AddResponse is the response for CalculatorService.Add.
+type AddResponse struct {
+	Sum int
+}
@@ -0,0 +1,39 @@
+package containers
+
+import "github.com/pacedotdev/oto/testdata/services"
+
+// TimelineService manages timelines.
+type TimelineService interface {
+	// Get gets a timeline.
+	Get(GetRequest) GetResponse
+}
+
+// GetRequest is the request object for TimelineService.Get.
+type GetRequest struct {
+	// Filters are filters.
+	Filters []map[string]string
+	// Grid is a grid of cells.
+	Grid [][]string
+	// Timelines are lists of events.
+	Timelines [][]Event
+}
+
+// GetResponse is the response object for TimelineService.Get.
+type GetResponse struct {
+	// EventsByDay are the events for each day.
+	EventsByDay map[string][]Event
+	// Grouped are events grouped by user then kind.
+	Grouped map[string][]map[string]*Detail
+	// Pages are pages by day.
+	Pages map[int][]*services.Page
+}
+
+// Event is an event.
+type Event struct {
+	Name string
+}
+
+// Detail is a detail.
+type Detail struct {
+	Text string
+}
@@ -0,0 +1,43 @@
+package contexts
+
+import "context"
+
+// SearchService searches for things.
+type SearchService interface {
+	// Search takes a context.
+	Search(ctx context.Context, r SearchRequest) SearchResponse
+	// Suggest does not take a context.
+	Suggest(SuggestRequest) SuggestResponse
+	// Save returns an error.
+	Save(ctx context.Context, r SaveRequest) (SaveResponse, error)
+}
+
+// SearchRequest is the request for SearchService.Search.
+type SearchRequest struct {
+	Query string
+}
+
+// SearchResponse is the response for SearchService.Search.
+type SearchResponse struct {
+	Results []string
+}
+
+// SuggestRequest is the request for SearchService.Suggest.
+type SuggestRequest struct {
+	Prefix string
+}
+
+// SuggestResponse is the response for SearchService.Suggest.
+type SuggestResponse struct {
+	Suggestions []string
+}
+
+// SaveRequest is the request for SearchService.Save.
+type SaveRequest struct {
+	Query string
+}
+
+// SaveResponse is the response for SearchService.Save.
+type SaveResponse struct {
+	ID string
+}
@@ -0,0 +1,38 @@
+package cycles
+
+// TreeService manages trees.
+type TreeService interface {
+	// Get gets a tree.
+	Get(GetRequest) GetResponse
+}
+
+// GetRequest is the request object for TreeService.Get.
+type GetRequest struct {
+	// Author refers to Book, which refers back to Author.
+	Author *Author
+}
+
+// GetResponse is the response object for TreeService.Get.
+type GetResponse struct {
+	Tree Tree
+}
+
+// Author wrote a book.
+type Author struct {
+	Book *Book
+}
+
+// Book was written by an author.
+type Book struct {
+	Author *Author
+}
+
+// Tree is a tree of nodes.
+type Tree struct {
+	Root Node
+}
+
+// Node is a node in a tree.
+type Node struct {
+	Children []Node
+}
@@ -0,0 +1,140 @@
+package deep
+
+// DeepService has deeply nested objects.
+type DeepService interface {
+	// Get gets the deepest level.
+	Get(DeepRequest) DeepResponse
+}
+
+// DeepRequest is the request for DeepService.Get.
+type DeepRequest struct {
+	Level Level01
+}
+
+// DeepResponse is the response for DeepService.Get.
+type DeepResponse struct{}
+
+// Level01 is nested 1 levels deep.
+type Level01 struct {
+	Next Level02
+}
+
+// Level02 is nested 2 levels deep.
+type Level02 struct {
+	Next Level03
+}
+
+// Level03 is nested 3 levels deep.
+type Level03 struct {
+	Next Level04
+}
+
+// Level04 is nested 4 levels deep.
+type Level04 struct {
+	Next Level05
+}
+
+// Level05 is nested 5 levels deep.
+type Level05 struct {
+	Next Level06
+}
+
+// Level06 is nested 6 levels deep.
+type Level06 struct {
+	Next Level07
+}
+
+// Level07 is nested 7 levels deep.
+type Level07 struct {
+	Next Level08
+}
+
+// Level08 is nested 8 levels deep.
+type Level08 struct {
+	Next Level09
+}
+
+// Level09 is nested 9 levels deep.
+type Level09 struct {
+	Next Level10
+}
+
+// Level10 is nested 10 levels deep.
+type Level10 struct {
+	Next Level11
+}
+
+// Level11 is nested 11 levels deep.
+type Level11 struct {
+	Next Level12
+}
+
+// Level12 is nested 12 levels deep.
+type Level12 struct {
+	Next Level13
+}
+
+// Level13 is nested 13 levels deep.
+type Level13 struct {
+	Next Level14
+}
+
+// Level14 is nested 14 levels deep.
+type Level14 struct {
+	Next Level15
+}
+
+// Level15 is nested 15 levels deep.
+type Level15 struct {
+	Next Level16
+}
+
+// Level16 is nested 16 levels deep.
+type Level16 struct {
+	Next Level17
+}
+
+// Level17 is nested 17 levels deep.
+type Level17 struct {
+	Next Level18
+}
+
+// Level18 is nested 18 levels deep.
+type Level18 struct {
+	Next Level19
+}
+
+// Level19 is nested 19 levels deep.
+type Level19 struct {
+	Next Level20
+}
+
+// Level20 is nested 20 levels deep.
+type Level20 struct {
+	Next Level21
+}
+
+// Level21 is nested 21 levels deep.
+type Level21 struct {
+	Next Level22
+}
+
+// Level22 is nested 22 levels deep.
+type Level22 struct {
+	Next Level23
+}
+
+// Level23 is nested 23 levels deep.
+type Level23 struct {
+	Next Level24
+}
+
+// Level24 is nested 24 levels deep.
+type Level24 struct {
+	Next Level25
+}
+
+// Level25 is nested 25 levels deep.
+type Level25 struct {
+	Value string
+}
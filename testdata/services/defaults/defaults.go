@@ -0,0 +1,36 @@
+package defaults
+
+// SearchService searches things.
+type SearchService interface {
+	// Search searches for things.
+	Search(SearchRequest) SearchResponse
+}
+
+// SearchRequest is the request for SearchService.Search.
+type SearchRequest struct {
+	// Query is what to search for.
+	Query string
+	// Limit is the most results to return.
+	// oto:default 20
+	// example: 50
+	Limit int
+	// Sort is how to sort the results.
+	// oto:default "relevance"
+	Sort string
+	// Cursor is where to start from.
+	// oto:default null
+	Cursor *string
+	// Filter is what to filter by.
+	// oto:default {not json}
+	Filter string
+	// Tags are the tags to match.
+	// default: ["new"]
+	// example: ["new", "sale"]
+	Tags []string
+	// Exact is whether to match the query exactly.
+	// default: false
+	Exact bool
+}
+
+// SearchResponse is the response for SearchService.Search.
+type SearchResponse struct{}
@@ -0,0 +1,45 @@
+package deprecated
+
+// LegacyService is the old API.
+//
+// Deprecated: use AccountService instead.
+type LegacyService interface {
+	// Get gets an account.
+	Get(GetRequest) GetResponse
+}
+
+// AccountService manages accounts.
+type AccountService interface {
+	// Find finds an account.
+	//
+	// Deprecated: use Get, which
+	// is faster.
+	Find(GetRequest) GetResponse
+	// Get gets an account.
+	Get(GetRequest) GetResponse
+}
+
+// GetRequest is the request for AccountService.Get.
+type GetRequest struct {
+	ID string
+	// Email is the email address.
+	// Deprecated: use ID.
+	Email string
+	// Phone is the phone number.
+	//
+	// Deprecated: use Email.
+	//
+	// This paragraph is more docs.
+	// example: "555-0100"
+	Phone string
+}
+
+// GetResponse is the response for AccountService.Get.
+//
+// Deprecated:
+//
+// This paragraph is more docs.
+// example: {"name": "Mat"}
+type GetResponse struct {
+	Name string
+}
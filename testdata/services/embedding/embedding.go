@@ -0,0 +1,48 @@
+package embedding
+
+import (
+	"fmt"
+
+	"github.com/pacedotdev/oto/testdata/services/embedding/users"
+)
+
+// UserService manages users.
+type UserService interface {
+	// GetUser gets a user.
+	GetUser(GetUserRequest) GetUserResponse
+}
+
+// AdminService is for administrators.
+type AdminService interface {
+	UserService
+	users.Finder
+	// fmt.Stringer is ignored, its String method
+	// is not an RPC method.
+	fmt.Stringer
+	// Ban bans a user.
+	Ban(BanRequest) BanResponse
+}
+
+// AuditedAdminService embeds UserService twice, via AdminService.
+type AuditedAdminService interface {
+	AdminService
+	UserService
+}
+
+// GetUserRequest is the request object for UserService.GetUser.
+type GetUserRequest struct {
+	ID string
+}
+
+// GetUserResponse is the response object for UserService.GetUser.
+type GetUserResponse struct {
+	Name string
+}
+
+// BanRequest is the request object for AdminService.Ban.
+type BanRequest struct {
+	ID string
+}
+
+// BanResponse is the response object for AdminService.Ban.
+type BanResponse struct{}
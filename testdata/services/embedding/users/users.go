@@ -0,0 +1,17 @@
+package users
+
+// Finder finds users.
+type Finder interface {
+	// Find finds users.
+	Find(FindRequest) FindResponse
+}
+
+// FindRequest is the request object for Finder.Find.
+type FindRequest struct {
+	Query string
+}
+
+// FindResponse is the response object for Finder.Find.
+type FindResponse struct {
+	IDs []string
+}
@@ -0,0 +1,41 @@
+package enums
+
+// TaskService manages tasks.
+type TaskService interface {
+	// Update updates a task.
+	Update(UpdateRequest) UpdateResponse
+}
+
+// Status is the status of a task.
+type Status string
+
+const (
+	// Active tasks are being worked on.
+	Active   Status = "active"
+	Inactive Status = "inactive" // Inactive tasks are paused.
+	// Done tasks are finished.
+	Done Status = "done"
+)
+
+// Priority is how urgent a task is.
+type Priority int
+
+const (
+	Low Priority = iota
+	Medium
+	High
+)
+
+// Label is a label on a task, which can be anything.
+type Label string
+
+// UpdateRequest is the request for TaskService.Update.
+type UpdateRequest struct {
+	Status   Status
+	Previous []Status
+	Priority *Priority
+	Labels   []Label
+}
+
+// UpdateResponse is the response for TaskService.Update.
+type UpdateResponse struct{}
@@ -0,0 +1,14 @@
+package auth
+
+// AccountService has an invalid oto:auth line.
+// oto:auth apikey body X-API-Key
+type AccountService interface {
+	// Get gets an account.
+	Get(GetRequest) GetResponse
+}
+
+// GetRequest is the request for AccountService.Get.
+type GetRequest struct{}
+
+// GetResponse is the response for AccountService.Get.
+type GetResponse struct{}
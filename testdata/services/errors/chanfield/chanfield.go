@@ -0,0 +1,16 @@
+package chanfield
+
+// StreamService streams things.
+type StreamService interface {
+	// Stream streams.
+	Stream(StreamRequest) StreamResponse
+}
+
+// StreamRequest is the request object for StreamService.Stream.
+type StreamRequest struct {
+	// Bad cannot be serialised.
+	Bad chan string
+}
+
+// StreamResponse is the response object for StreamService.Stream.
+type StreamResponse struct{}
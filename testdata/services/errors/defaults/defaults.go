@@ -0,0 +1,17 @@
+package defaults
+
+// SearchService searches things.
+type SearchService interface {
+	// Search searches for things.
+	Search(SearchRequest) SearchResponse
+}
+
+// SearchRequest is the request for SearchService.Search.
+type SearchRequest struct {
+	// Limit is the most results to return.
+	// default: "twenty"
+	Limit int
+}
+
+// SearchResponse is the response for SearchService.Search.
+type SearchResponse struct{}
@@ -0,0 +1,17 @@
+package discriminator
+
+// PaymentService takes payments.
+type PaymentService interface {
+	// Pay makes a payment.
+	Pay(PayRequest) PayResponse
+}
+
+// PayRequest is the request for PaymentService.Pay.
+type PayRequest struct {
+	// Method is how to pay.
+	// oto:discriminator type
+	Method interface{}
+}
+
+// PayResponse is the response for PaymentService.Pay.
+type PayResponse struct{}
@@ -0,0 +1,13 @@
+package a
+
+// GreeterService greets people.
+type GreeterService interface {
+	// Greet makes a greeting.
+	Greet(GreetRequest) GreetResponse
+}
+
+// GreetRequest is the request for GreeterService.Greet.
+type GreetRequest struct{}
+
+// GreetResponse is the response for GreeterService.Greet.
+type GreetResponse struct{}
@@ -0,0 +1,15 @@
+package embedded
+
+import "io"
+
+// FileService embeds io.Closer, which is ignored, and Opener,
+// which is invalid.
+type FileService interface {
+	io.Closer
+	Opener
+}
+
+// Opener opens things.
+type Opener interface {
+	Open(name string, flags int) error
+}
@@ -0,0 +1,19 @@
+package examplefile
+
+// UserService manages users.
+type UserService interface {
+	// Create creates a user.
+	Create(CreateRequest) CreateResponse
+}
+
+// CreateRequest is the request for UserService.Create.
+type CreateRequest struct {
+	// Name is the name of the user.
+	// example-file: missing.json
+	Name string
+}
+
+// CreateResponse is the response for UserService.Create.
+type CreateResponse struct {
+	ID string
+}
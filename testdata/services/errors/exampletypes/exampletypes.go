@@ -0,0 +1,20 @@
+package exampletypes
+
+// OrderService takes orders.
+type OrderService interface {
+	// Place places an order.
+	Place(PlaceRequest) PlaceResponse
+}
+
+// PlaceRequest is the request for OrderService.Place.
+type PlaceRequest struct {
+	// SKU is the product.
+	// example: "ABC"
+	SKU string
+	// Quantity is how many to order.
+	// example: "two"
+	Quantity int
+}
+
+// PlaceResponse is the response for OrderService.Place.
+type PlaceResponse struct{}
@@ -0,0 +1,19 @@
+package fieldenums
+
+// ListService lists things.
+type ListService interface {
+	// List lists things.
+	List(ListRequest) ListResponse
+}
+
+// ListRequest is the request for ListService.List.
+type ListRequest struct {
+	// Order is the order of the results.
+	// enum: "asc", 1
+	Order string
+}
+
+// ListResponse is the response for ListService.List.
+type ListResponse struct {
+	Items []string
+}
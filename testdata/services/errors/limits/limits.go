@@ -0,0 +1,18 @@
+package limits
+
+// SignupService signs people up.
+type SignupService interface {
+	// Signup signs someone up.
+	Signup(SignupRequest) SignupResponse
+}
+
+// SignupRequest is the request for SignupService.Signup.
+type SignupRequest struct {
+	// Age is how old they are.
+	// min: 18
+	// max: 16
+	Age int
+}
+
+// SignupResponse is the response for SignupService.Signup.
+type SignupResponse struct{}
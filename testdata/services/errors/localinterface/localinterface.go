@@ -0,0 +1,16 @@
+package localinterface
+
+// Notifier sends notifications.
+type Notifier interface {
+	// Notify sends a notification.
+	Notify(NotifyRequest) NotifyResponse
+}
+
+// NotifyRequest is the request object for Notifier.Notify.
+type NotifyRequest struct {
+	// Via is the notifier to use.
+	Via Notifier
+}
+
+// NotifyResponse is the response object for Notifier.Notify.
+type NotifyResponse struct{}
@@ -0,0 +1,15 @@
+package location
+
+// OrderService manages orders.
+type OrderService interface {
+	// Get gets an order.
+	Get(GetRequest) GetResponse
+}
+
+// GetRequest is the request for OrderService.Get.
+type GetRequest struct {
+	OrderID string `oto:"in=form"`
+}
+
+// GetResponse is the response for OrderService.Get.
+type GetResponse struct{}
@@ -0,0 +1,19 @@
+package multilineexamples
+
+// OrderService takes orders.
+type OrderService interface {
+	// Place places an order.
+	Place(PlaceRequest) PlaceResponse
+}
+
+// PlaceRequest is the request for OrderService.Place.
+type PlaceRequest struct {
+	// Lines are the things being ordered.
+	// example: [
+	//   {"sku": "ABC", "quantity": 2},
+	// ]
+	Lines []string
+}
+
+// PlaceResponse is the response for OrderService.Place.
+type PlaceResponse struct{}
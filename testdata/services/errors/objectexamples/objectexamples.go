@@ -0,0 +1,18 @@
+package objectexamples
+
+// ContactService contacts people.
+type ContactService interface {
+	// Send sends a message.
+	Send(SendRequest) SendResponse
+}
+
+// SendRequest is the request for ContactService.Send.
+// example: ["mat@example.com"]
+type SendRequest struct {
+	Email string
+}
+
+// SendResponse is the response for ContactService.Send.
+type SendResponse struct {
+	Sent bool
+}
@@ -0,0 +1,14 @@
+package ratelimit
+
+// SearchService searches things.
+type SearchService interface {
+	// Search searches.
+	// oto:rate-limit 100 per fortnight
+	Search(SearchRequest) SearchResponse
+}
+
+// SearchRequest is the request for SearchService.Search.
+type SearchRequest struct{}
+
+// SearchResponse is the response for SearchService.Search.
+type SearchResponse struct{}
@@ -0,0 +1,18 @@
+package readwrite
+
+// AccountService manages accounts.
+type AccountService interface {
+	// Create creates an account.
+	Create(CreateRequest) CreateResponse
+}
+
+// CreateRequest is the request for AccountService.Create.
+type CreateRequest struct {
+	// Token is the token.
+	// oto:readonly
+	Token string `oto:"writeonly"`
+}
+
+// CreateResponse is the response for AccountService.Create.
+type CreateResponse struct {
+}
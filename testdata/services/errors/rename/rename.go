@@ -0,0 +1,18 @@
+package rename
+
+// ProxyService manages proxies.
+type ProxyService interface {
+	// Get gets a proxy.
+	Get(GetRequest) GetResponse
+}
+
+// GetRequest is the request for ProxyService.Get.
+type GetRequest struct {
+	Region string
+}
+
+// GetResponse is the response for ProxyService.Get.
+// oto:rename "GetRequest"
+type GetResponse struct {
+	URL string
+}
@@ -0,0 +1,20 @@
+package renamemethod
+
+// ProxyService manages proxies.
+type ProxyService interface {
+	// Find finds a proxy.
+	// oto:rename "Get"
+	Find(GetRequest) GetResponse
+	// Get gets a proxy.
+	Get(GetRequest) GetResponse
+}
+
+// GetRequest is the request for ProxyService.Get.
+type GetRequest struct {
+	Region string
+}
+
+// GetResponse is the response for ProxyService.Get.
+type GetResponse struct {
+	URL string
+}
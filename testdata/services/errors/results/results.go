@@ -0,0 +1,15 @@
+package results
+
+// SearchService searches.
+type SearchService interface {
+	// Search returns a string instead of an error.
+	Search(SearchRequest) (SearchResponse, string)
+}
+
+// SearchRequest is the request object for SearchService.Search.
+type SearchRequest struct {
+	Query string
+}
+
+// SearchResponse is the response object for SearchService.Search.
+type SearchResponse struct{}
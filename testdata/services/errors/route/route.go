@@ -0,0 +1,14 @@
+package route
+
+// UserService manages users.
+type UserService interface {
+	// Get gets a user.
+	// oto:route FETCH /users/:id
+	Get(GetRequest) GetResponse
+}
+
+// GetRequest is the request for UserService.Get.
+type GetRequest struct{}
+
+// GetResponse is the response for UserService.Get.
+type GetResponse struct{}
@@ -0,0 +1,14 @@
+package scopes
+
+// BillingService manages billing.
+type BillingService interface {
+	// Refund refunds an invoice.
+	// oto:scopes admin,,billing:write
+	Refund(RefundRequest) RefundResponse
+}
+
+// RefundRequest is the request for BillingService.Refund.
+type RefundRequest struct{}
+
+// RefundResponse is the response for BillingService.Refund.
+type RefundResponse struct{}
@@ -0,0 +1,24 @@
+package skipfield
+
+// GreeterService makes greetings.
+type GreeterService interface {
+	// Greet makes a greeting.
+	Greet(GreetRequest) GreetResponse
+}
+
+// GreetRequest is the request for GreeterService.Greet.
+type GreetRequest struct {
+	Name  string
+	Cache *Cache
+}
+
+// GreetResponse is the response for GreeterService.Greet.
+type GreetResponse struct {
+	Greeting string
+}
+
+// Cache holds greetings in memory.
+// oto:skip
+type Cache struct {
+	Greetings map[string]string
+}
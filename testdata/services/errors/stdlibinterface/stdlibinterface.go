@@ -0,0 +1,18 @@
+package stdlibinterface
+
+import "io"
+
+// UploadService uploads files.
+type UploadService interface {
+	// Upload uploads a file.
+	Upload(UploadRequest) UploadResponse
+}
+
+// UploadRequest is the request object for UploadService.Upload.
+type UploadRequest struct {
+	// Body is where the file content goes.
+	Body io.Writer
+}
+
+// UploadResponse is the response object for UploadService.Upload.
+type UploadResponse struct{}
@@ -0,0 +1,13 @@
+package stream
+
+// EventService streams events.
+type EventService interface {
+	// Watch streams strings, which are not structs.
+	// oto:stream
+	Watch(WatchRequest) string
+}
+
+// WatchRequest is the request for EventService.Watch.
+type WatchRequest struct {
+	Topic string
+}
@@ -0,0 +1,14 @@
+package strictmissing
+
+// LookupService looks things up.
+type LookupService interface {
+	// Lookup looks up a name.
+	Lookup(string) LookupResponse
+}
+
+// LookupResponse is the response object for LookupService.Lookup.
+type LookupResponse struct {
+	// Value is the value.
+	// example: "value"
+	Value string
+}
@@ -0,0 +1,15 @@
+package synthesized
+
+// ThingService manages things.
+type ThingService interface {
+	// Get gets a thing, but GetRequest already exists.
+	Get(id string, includeDeleted bool) GetResponse
+}
+
+// GetRequest is already declared.
+type GetRequest struct {
+	ID string
+}
+
+// GetResponse is the response for ThingService.Get.
+type GetResponse struct{}
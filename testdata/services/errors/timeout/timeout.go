@@ -0,0 +1,14 @@
+package timeout
+
+// ReportService makes reports.
+type ReportService interface {
+	// Run runs a report.
+	// oto:timeout 5 minutes
+	Run(ReportRequest) ReportResponse
+}
+
+// ReportRequest is the request for ReportService.Run.
+type ReportRequest struct{}
+
+// ReportResponse is the response for ReportService.Run.
+type ReportResponse struct{}
@@ -0,0 +1,18 @@
+package unsafefield
+
+import "unsafe"
+
+// StreamService streams things.
+type StreamService interface {
+	// Stream streams.
+	Stream(StreamRequest) StreamResponse
+}
+
+// StreamRequest is the request object for StreamService.Stream.
+type StreamRequest struct {
+	// Bad cannot be serialised.
+	Bad unsafe.Pointer
+}
+
+// StreamResponse is the response object for StreamService.Stream.
+type StreamResponse struct{}
@@ -0,0 +1,15 @@
+package variadic
+
+// SearchService searches.
+type SearchService interface {
+	// Search searches.
+	Search(q ...SearchRequest) SearchResponse
+}
+
+// SearchRequest is the request object for SearchService.Search.
+type SearchRequest struct {
+	Query string
+}
+
+// SearchResponse is the response object for SearchService.Search.
+type SearchResponse struct{}
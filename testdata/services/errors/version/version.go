@@ -0,0 +1,14 @@
+package version
+
+// PaymentService takes payments.
+// oto:version
+type PaymentService interface {
+	// Charge charges a card.
+	Charge(ChargeRequest) ChargeResponse
+}
+
+// ChargeRequest is the request for PaymentService.Charge.
+type ChargeRequest struct{}
+
+// ChargeResponse is the response for PaymentService.Charge.
+type ChargeResponse struct{}
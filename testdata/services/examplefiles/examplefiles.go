@@ -0,0 +1,23 @@
+package examplefiles
+
+// UserService manages users.
+type UserService interface {
+	// Create creates a user.
+	Create(CreateRequest) CreateResponse
+}
+
+// CreateRequest is the request for UserService.Create.
+// example-file: examples/create_request.json
+type CreateRequest struct {
+	// Name is the name of the user.
+	// example: "Mat"
+	Name string `json:"name"`
+	// Roles are what the user can do.
+	// example-file: examples/roles.json
+	Roles []string `json:"roles"`
+}
+
+// CreateResponse is the response for UserService.Create.
+type CreateResponse struct {
+	ID string `json:"id"`
+}
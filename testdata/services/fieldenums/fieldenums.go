@@ -0,0 +1,24 @@
+package fieldenums
+
+// ListService lists things.
+type ListService interface {
+	// List lists things.
+	List(ListRequest) ListResponse
+}
+
+// ListRequest is the request for ListService.List.
+type ListRequest struct {
+	// Order is the order of the results.
+	// enum: "asc","desc"
+	Order string
+	// Sizes are the page sizes to try.
+	// enum: [10, 20, 50]
+	Sizes []int
+	// Query is what to search for.
+	Query string
+}
+
+// ListResponse is the response for ListService.List.
+type ListResponse struct {
+	Items []string
+}
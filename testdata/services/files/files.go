@@ -0,0 +1,46 @@
+package files
+
+import (
+	"io"
+	"mime/multipart"
+)
+
+// FileService stores files.
+type FileService interface {
+	// Upload uploads a file.
+	Upload(UploadRequest) UploadResponse
+	// Import imports a file.
+	Import(ImportRequest) UploadResponse
+	// Attach attaches a file.
+	Attach(AttachRequest) UploadResponse
+	// Rename renames a file.
+	Rename(RenameRequest) UploadResponse
+}
+
+// UploadRequest is the request for FileService.Upload.
+type UploadRequest struct {
+	Name string
+	File *multipart.FileHeader
+}
+
+// ImportRequest is the request for FileService.Import.
+type ImportRequest struct {
+	Data io.Reader
+}
+
+// AttachRequest is the request for FileService.Attach.
+type AttachRequest struct {
+	// Content is the base64 file content.
+	// oto:file
+	Content string
+}
+
+// RenameRequest is the request for FileService.Rename.
+type RenameRequest struct {
+	Name string
+}
+
+// UploadResponse is the response for FileService.Upload.
+type UploadResponse struct {
+	ID string
+}
@@ -0,0 +1,30 @@
+package formats
+
+import "time"
+
+// AccountService manages accounts.
+type AccountService interface {
+	// Create creates an account.
+	Create(CreateRequest) CreateResponse
+}
+
+// CreateRequest is the request for AccountService.Create.
+type CreateRequest struct {
+	// Email is the email address.
+	// oto:format email
+	Email string
+	// Password is the password.
+	Password string `oto:"format=password"`
+	// Website is the website.
+	// oto:format hostname
+	Website string `oto:"format=uri"`
+	// Birthday is the date of birth.
+	// oto:format date
+	Birthday time.Time
+	// Color is a color.
+	// oto:format color
+	Color string
+}
+
+// CreateResponse is the response for AccountService.Create.
+type CreateResponse struct{}
@@ -0,0 +1,29 @@
+package globs
+
+// InternalAdminService is for internal use.
+type InternalAdminService interface {
+	// Reset resets everything.
+	Reset(Request) Response
+}
+
+// InternalAuditService is for internal use.
+type InternalAuditService interface {
+	// Log logs an event.
+	Log(Request) Response
+}
+
+// InternationalService is public.
+type InternationalService interface {
+	// Translate translates some text.
+	Translate(Request) Response
+}
+
+// Request is a request.
+type Request struct {
+	Text string
+}
+
+// Response is a response.
+type Response struct {
+	Text string
+}
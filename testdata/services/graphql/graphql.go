@@ -0,0 +1,54 @@
+package graphql
+
+// SearchService searches things.
+type SearchService interface {
+	// Search finds matching items.
+	// oto:graphql-query
+	Search(SearchRequest) SearchResponse
+	// GetStats gets stats, but also resets them.
+	// oto:graphql-mutation
+	GetStats(GetStatsRequest) GetStatsResponse
+}
+
+// SearchRequest is the request object for SearchService.Search.
+type SearchRequest struct {
+	// Query is the search query.
+	Query string
+	// Filter limits the results.
+	Filter Filter
+}
+
+// SearchResponse is the response object for SearchService.Search.
+type SearchResponse struct {
+	// Items are the matching items.
+	Items []Item
+	// Filter is the filter that was used.
+	Filter Filter
+	// Facets are the counts of items by facet.
+	Facets map[string]int
+}
+
+// Filter limits results.
+type Filter struct {
+	// Tags are tags to match.
+	Tags []string
+	// MinScore is the minimum score.
+	MinScore float64
+}
+
+// Item is a search result.
+type Item struct {
+	// ID is the identifier.
+	ID int
+	// Title is the title.
+	Title string
+}
+
+// GetStatsRequest is the request object for SearchService.GetStats.
+type GetStatsRequest struct{}
+
+// GetStatsResponse is the response object for SearchService.GetStats.
+type GetStatsResponse struct {
+	// Searches is the number of searches.
+	Searches int64
+}
@@ -0,0 +1,39 @@
+package ignore
+
+// UserService manages users.
+type UserService interface {
+	// Get gets a user.
+	Get(GetRequest) GetResponse
+	// Purge is for internal use.
+	// oto:ignore
+	Purge(PurgeRequest) PurgeResponse
+	// Refresh is for internal use, but shares the request with Get.
+	// oto:ignore
+	Refresh(GetRequest) PurgeResponse
+}
+
+// GetRequest is the request for UserService.Get.
+type GetRequest struct {
+	ID string
+}
+
+// GetResponse is the response for UserService.Get.
+type GetResponse struct {
+	Name string
+}
+
+// PurgeRequest is the request for UserService.Purge.
+type PurgeRequest struct {
+	Before string
+	Filter PurgeFilter
+}
+
+// PurgeFilter is only used by PurgeRequest.
+type PurgeFilter struct {
+	Deleted bool
+}
+
+// PurgeResponse is the response for UserService.Purge.
+type PurgeResponse struct {
+	Count int
+}
@@ -0,0 +1,47 @@
+package include
+
+// AccountService manages accounts.
+type AccountService interface {
+	// Get gets an account.
+	Get(GetAccountRequest) AccountResponse
+}
+
+// BillingService manages billing.
+type BillingService interface {
+	// Charge charges an account.
+	Charge(ChargeRequest) AccountResponse
+}
+
+// UserService manages users.
+type UserService interface {
+	// Get gets a user.
+	Get(GetUserRequest) UserResponse
+}
+
+// GetAccountRequest is the request for AccountService.Get.
+type GetAccountRequest struct {
+	ID string
+}
+
+// ChargeRequest is the request for BillingService.Charge.
+type ChargeRequest struct {
+	AccountID string
+	Amount    int
+}
+
+// AccountResponse is the response for AccountService.Get and
+// BillingService.Charge.
+type AccountResponse struct {
+	Balance int
+}
+
+// GetUserRequest is the request for UserService.Get.
+type GetUserRequest struct {
+	ID string
+}
+
+// UserResponse is the response for UserService.Get.
+type UserResponse struct {
+	Name    string
+	Account *AccountResponse
+}
@@ -0,0 +1,30 @@
+package inout
+
+// ItemService stores items.
+type ItemService interface {
+	// Get gets an item.
+	Get(GetRequest) Item
+	// Put stores an item.
+	Put(Item) PutResponse
+}
+
+// GetRequest is the request for ItemService.Get.
+type GetRequest struct {
+	ID string
+}
+
+// Item is an item.
+type Item struct {
+	ID   string
+	Tags []Tag
+}
+
+// Tag is a tag on an item.
+type Tag struct {
+	Name string
+}
+
+// PutResponse is the response for ItemService.Put.
+type PutResponse struct {
+	Version int
+}
@@ -0,0 +1,24 @@
+package int64s
+
+// IDService looks up things by ID.
+type IDService interface {
+	// Lookup looks up things.
+	Lookup(LookupRequest) LookupResponse
+}
+
+// LookupRequest is the request object for IDService.Lookup.
+type LookupRequest struct {
+	ID       int64
+	Unsigned uint64
+	IDs      []int64
+	Counts   map[string]int64
+	Small    int32
+	// Token is a token.
+	// oto:int64-as-string
+	Token int64
+}
+
+// LookupResponse is the response object for IDService.Lookup.
+type LookupResponse struct {
+	Total int64
+}
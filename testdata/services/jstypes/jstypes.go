@@ -0,0 +1,28 @@
+package jstypes
+
+// TypesService uses lots of types.
+type TypesService interface {
+	// Check checks types.
+	Check(CheckRequest) CheckResponse
+}
+
+// UserID is the ID of a user.
+type UserID string
+
+// Score is a score.
+type Score float64
+
+// CheckRequest is the request object for TypesService.Check.
+type CheckRequest struct {
+	Byte    byte
+	Rune    rune
+	Uintptr uintptr
+	UserID  UserID
+	Score   Score
+	Any     interface{}
+	Lookup  map[int]string
+	Pair    [2]string
+}
+
+// CheckResponse is the response object for TypesService.Check.
+type CheckResponse struct{}
@@ -0,0 +1,31 @@
+package limits
+
+// SignupService signs people up.
+type SignupService interface {
+	// Signup signs someone up.
+	Signup(SignupRequest) SignupResponse
+}
+
+// SignupRequest is the request for SignupService.Signup.
+type SignupRequest struct {
+	// Username is the name to sign in with.
+	// min: 3
+	// max: 20
+	// pattern: ^[a-z]+$
+	Username string
+	// Email is the email address.
+	// format: email
+	Email string
+	// Age is how old they are.
+	// min: 18
+	Age int
+	// Score is the starting score.
+	// max: 99.5
+	Score float64 `validate:"min=1,max=100"`
+	// Tags are labels for the account.
+	// max: 5
+	Tags []string
+}
+
+// SignupResponse is the response for SignupService.Signup.
+type SignupResponse struct{}
@@ -0,0 +1,39 @@
+package locations
+
+// OrderService manages orders.
+type OrderService interface {
+	// Get gets an order.
+	// oto:route GET /orders/{orderID}
+	Get(GetRequest) GetResponse
+	// Update updates an order.
+	// oto:path /orders/{orderID}
+	Update(UpdateRequest) UpdateResponse
+}
+
+// GetRequest is the request for OrderService.Get.
+type GetRequest struct {
+	// OrderID is the ID of the order.
+	OrderID string
+	// Token is the auth token.
+	// oto:in header
+	Token string
+	// Expand includes the items.
+	Expand bool
+}
+
+// GetResponse is the response for OrderService.Get.
+type GetResponse struct {
+	Status string
+}
+
+// UpdateRequest is the request for OrderService.Update.
+type UpdateRequest struct {
+	// OrderID is required, because it is in the path.
+	OrderID string `json:",omitempty"`
+	Version int    `oto:"in=query"`
+	Session string `oto:"in=cookie"`
+	Status  string
+}
+
+// UpdateResponse is the response for OrderService.Update.
+type UpdateResponse struct{}
@@ -0,0 +1,70 @@
+package marshalers
+
+import "strconv"
+
+// PaintService paints things.
+type PaintService interface {
+	// Paint paints a thing.
+	Paint(PaintRequest) PaintResponse
+}
+
+// PaintRequest is the request object for PaintService.Paint.
+type PaintRequest struct {
+	Color    Color
+	Colors   []*Color
+	Status   Status
+	Priority Priority
+	Level    Level
+	Version  Version
+}
+
+// PaintResponse is the response object for PaintService.Paint.
+type PaintResponse struct{}
+
+// Color is encoded as a hex string, but oto cannot know that.
+type Color struct {
+	r, g, b uint8
+}
+
+// MarshalJSON encodes the Color.
+func (c *Color) MarshalJSON() ([]byte, error) {
+	return []byte(`"#000000"`), nil
+}
+
+// Status is encoded as a string.
+// oto:jstype string
+type Status int
+
+// MarshalJSON encodes the Status.
+func (s Status) MarshalJSON() ([]byte, error) {
+	return []byte(strconv.Quote(strconv.Itoa(int(s)))), nil
+}
+
+// Priority is encoded as a number.
+type Priority int
+
+// MarshalJSON encodes the Priority.
+func (p Priority) MarshalJSON() ([]byte, error) {
+	return []byte(strconv.Itoa(int(p))), nil
+}
+
+// Level is encoded as text.
+type Level struct {
+	value int
+}
+
+// MarshalText encodes the Level.
+func (l Level) MarshalText() ([]byte, error) {
+	return []byte("high"), nil
+}
+
+// Version is encoded as a string, like "1.2.3".
+// oto:jstype string
+type Version struct {
+	Major, Minor, Patch int
+}
+
+// MarshalJSON encodes the Version.
+func (v Version) MarshalJSON() ([]byte, error) {
+	return []byte(`"1.2.3"`), nil
+}
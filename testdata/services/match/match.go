@@ -0,0 +1,31 @@
+package match
+
+import "context"
+
+// UserService manages users.
+type UserService interface {
+	// Get gets a user.
+	Get(GetRequest) GetResponse
+}
+
+// UserRepository stores users, and is not a service.
+type UserRepository interface {
+	Find(ctx context.Context, id string, deleted bool) (*User, error)
+	Save(context.Context, *User) error
+}
+
+// GetRequest is the request for UserService.Get.
+type GetRequest struct {
+	ID string
+}
+
+// GetResponse is the response for UserService.Get.
+type GetResponse struct {
+	Name string
+}
+
+// User is a stored user.
+type User struct {
+	ID   string
+	Name string
+}
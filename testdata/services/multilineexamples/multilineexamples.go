@@ -0,0 +1,50 @@
+package multilineexamples
+
+// OrderService takes orders.
+type OrderService interface {
+	// Place places an order.
+	Place(PlaceRequest) PlaceResponse
+}
+
+// PlaceRequest is the request for OrderService.Place.
+//
+//	example: {
+//		"customer": {"name": "Mat", "email": "mat@example.com"},
+//		"lines": [{"sku": "ABC", "quantity": 2}]
+//	}
+//
+// Orders are placed right away.
+type PlaceRequest struct {
+	// Customer is who is placing the order.
+	// example:
+	// {
+	//     "name": "Mat",
+	//     "email": "mat@example.com"
+	// }
+	Customer Customer `json:"customer"`
+	// Lines are the things being ordered.
+	// example: [
+	//   {"sku": "ABC", "quantity": 2}
+	// ]
+	// The order must have at least one line.
+	Lines []Line `json:"lines"`
+	// Note is a note for the order.
+	// example: "Leave at the door"
+	// It is optional.
+	Note string `json:"note"`
+}
+
+// Customer is a customer.
+type Customer struct {
+	Name  string `json:"name"`
+	Email string `json:"email"`
+}
+
+// Line is a line of an order.
+type Line struct {
+	SKU      string `json:"sku"`
+	Quantity int    `json:"quantity"`
+}
+
+// PlaceResponse is the response for OrderService.Place.
+type PlaceResponse struct{}
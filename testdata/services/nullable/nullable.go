@@ -0,0 +1,29 @@
+package nullable
+
+// ProfileService manages profiles.
+type ProfileService interface {
+	// Update updates a profile.
+	Update(UpdateRequest) UpdateResponse
+}
+
+// UpdateRequest is the request object for ProfileService.Update.
+type UpdateRequest struct {
+	// Nickname is an optional nickname.
+	Nickname *string
+	// Address is an optional address.
+	Address *Address
+	// Bio is the biography.
+	// oto:nullable
+	Bio string
+	// Name is the name.
+	Name string
+}
+
+// UpdateResponse is the response object for ProfileService.Update.
+type UpdateResponse struct{}
+
+// Address is a postal address.
+type Address struct {
+	// Line1 is the first line.
+	Line1 string
+}
@@ -0,0 +1,25 @@
+package objectexamples
+
+// ContactService contacts people.
+type ContactService interface {
+	// Send sends a message.
+	Send(SendRequest) SendResponse
+}
+
+// SendRequest is the request for ContactService.Send. Either the
+// Email or Phone must be set.
+// example: {"email": "mat@example.com", "message": "Hi", "subject": "Hello"}
+type SendRequest struct {
+	// Email is the email address to send to.
+	// example: "mat@example.com"
+	Email string `json:"email,omitempty"`
+	// Phone is the phone number to send to.
+	Phone string `json:"phone,omitempty"`
+	// Message is the message.
+	Message string `json:"message"`
+}
+
+// SendResponse is the response for ContactService.Send.
+type SendResponse struct {
+	Sent bool
+}
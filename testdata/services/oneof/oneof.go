@@ -0,0 +1,33 @@
+package oneof
+
+// PaymentService takes payments.
+type PaymentService interface {
+	// Pay makes a payment.
+	Pay(PayRequest) PayResponse
+}
+
+// PayRequest is the request for PaymentService.Pay.
+type PayRequest struct {
+	// Method is how to pay.
+	// oto:oneof Card, BankTransfer
+	// oto:discriminator type
+	Method interface{}
+	// Fallbacks are the methods to try if Method fails.
+	// oto:oneof Card,Voucher
+	Fallbacks []interface{}
+}
+
+// PayResponse is the response for PaymentService.Pay.
+type PayResponse struct{}
+
+// Card is a card payment.
+type Card struct {
+	Type   string
+	Number string
+}
+
+// BankTransfer is a payment by bank transfer.
+type BankTransfer struct {
+	Type string
+	IBAN string
+}
@@ -0,0 +1,37 @@
+package order
+
+// UserService is declared first.
+type UserService interface {
+	// Create creates a user.
+	Create(CreateRequest) UserResponse
+	// Update updates a user.
+	Update(UpdateRequest) UserResponse
+	// Delete deletes a user.
+	Delete(DeleteRequest) UserResponse
+}
+
+// AccountService is declared second.
+type AccountService interface {
+	// Open opens an account.
+	Open(CreateRequest) UserResponse
+}
+
+// UserResponse is declared before the requests.
+type UserResponse struct {
+	Name string
+}
+
+// UpdateRequest is the request for UserService.Update.
+type UpdateRequest struct {
+	ID string
+}
+
+// CreateRequest is the request for UserService.Create.
+type CreateRequest struct {
+	Name string
+}
+
+// DeleteRequest is the request for UserService.Delete.
+type DeleteRequest struct {
+	ID string
+}
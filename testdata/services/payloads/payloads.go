@@ -0,0 +1,25 @@
+package payloads
+
+import "context"
+
+// HealthService checks the health of the system.
+type HealthService interface {
+	// Ping takes and returns nothing.
+	Ping()
+	// Reset takes a request, but returns nothing.
+	Reset(ResetRequest)
+	// Status takes nothing, but returns a response.
+	Status() StatusResponse
+	// Clear takes a context and returns an error.
+	Clear(ctx context.Context) error
+}
+
+// ResetRequest is the request for HealthService.Reset.
+type ResetRequest struct {
+	Hard bool
+}
+
+// StatusResponse is the response for HealthService.Status.
+type StatusResponse struct {
+	OK bool
+}
@@ -0,0 +1,41 @@
+package pointers
+
+import "github.com/pacedotdev/oto/testdata/services"
+
+// TeamService manages teams.
+type TeamService interface {
+	// Update updates a team.
+	Update(UpdateRequest) UpdateResponse
+}
+
+// UpdateRequest is the request object for TeamService.Update.
+type UpdateRequest struct {
+	// Members are the members of the team.
+	Members []*User
+	// Owners are the owners of the team.
+	Owners *[]Owner
+	// Leader is the team leader.
+	Leader *Leader
+	// Pages are pages.
+	Pages []*services.Page
+	// Tags are tags.
+	Tags *[]*string
+}
+
+// UpdateResponse is the response object for TeamService.Update.
+type UpdateResponse struct{}
+
+// User is a user.
+type User struct {
+	Name string
+}
+
+// Owner is an owner.
+type Owner struct {
+	Name string
+}
+
+// Leader is a leader.
+type Leader struct {
+	Name string
+}
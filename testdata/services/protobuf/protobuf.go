@@ -0,0 +1,43 @@
+package protobuf
+
+import "time"
+
+// StoreService stores things.
+type StoreService interface {
+	// Put puts a thing in the store.
+	Put(PutRequest) PutResponse
+}
+
+// PutRequest is the request object for StoreService.Put.
+type PutRequest struct {
+	// Key is the key.
+	Key string
+	// Data is the raw data.
+	Data []byte
+	// Labels are labels.
+	Labels map[string]string
+	// Versions are the versions by number.
+	Versions map[int64]Version
+	// Expires is when the thing expires.
+	Expires time.Time
+	// Priority is the optional priority.
+	// oto:nullable
+	Priority int32
+	// Ratio is a ratio.
+	Ratio float32
+	// Value is anything.
+	Value interface{}
+	// Tags are tags.
+	Tags []string
+}
+
+// PutResponse is the response object for StoreService.Put.
+type PutResponse struct {
+	Version Version
+}
+
+// Version is a version of a thing.
+type Version struct {
+	Number  uint64
+	Created time.Time
+}
@@ -0,0 +1,60 @@
+package prune
+
+// PublicService is included.
+type PublicService interface {
+	// Get gets an item.
+	Get(GetRequest) GetResponse
+}
+
+// AdminService is excluded.
+type AdminService interface {
+	// Import imports items.
+	Import(ImportRequest) ImportResponse
+}
+
+// GetRequest is the request for PublicService.Get.
+type GetRequest struct {
+	ID string
+}
+
+// GetResponse is the response for PublicService.Get.
+type GetResponse struct {
+	Item Item
+}
+
+// Item is used by both services.
+type Item struct {
+	Name string
+}
+
+// ImportRequest is the request for AdminService.Import.
+type ImportRequest struct {
+	Batches []ImportBatch
+	Items   []Item
+}
+
+// ImportBatch is only used by ImportRequest.
+type ImportBatch struct {
+	Source ImportSource
+	Rows   map[string]ImportRow
+}
+
+// ImportSource is only used by ImportBatch.
+type ImportSource struct {
+	URL string
+}
+
+// ImportRow is only used by ImportBatch.
+type ImportRow struct {
+	Values []string
+}
+
+// ImportResponse is the response for AdminService.Import.
+type ImportResponse struct {
+	Count int
+}
+
+// Unrelated is not used by either service.
+type Unrelated struct {
+	Value string
+}
@@ -0,0 +1,27 @@
+package ratelimits
+
+// SearchService searches things.
+// oto:rate-limit 100 per minute
+type SearchService interface {
+	// Search searches.
+	Search(SearchRequest) SearchResponse
+	// Export exports the results.
+	// oto:rate-limit 5 per hour
+	Export(SearchRequest) SearchResponse
+}
+
+// PingService checks the API is up.
+type PingService interface {
+	// Ping pings the API.
+	Ping(SearchRequest) SearchResponse
+}
+
+// SearchRequest is the request for SearchService.Search.
+type SearchRequest struct {
+	Query string
+}
+
+// SearchResponse is the response for SearchService.Search.
+type SearchResponse struct {
+	Total int
+}
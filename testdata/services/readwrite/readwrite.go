@@ -0,0 +1,31 @@
+package readwrite
+
+// AccountService manages accounts.
+type AccountService interface {
+	// Create creates an account.
+	Create(CreateRequest) CreateResponse
+}
+
+// CreateRequest is the request for AccountService.Create.
+type CreateRequest struct {
+	Account Account
+}
+
+// CreateResponse is the response for AccountService.Create.
+type CreateResponse struct {
+	Account Account
+}
+
+// Account is an account.
+type Account struct {
+	// ID is set by the server.
+	// oto:readonly
+	ID string
+	// Email is the email address.
+	Email string
+	// Password is the password.
+	// oto:writeonly
+	// oto:sensitive
+	Password  string
+	CreatedAt string `oto:"readonly"`
+}
@@ -0,0 +1,31 @@
+package recovery
+
+// GreeterService says hello.
+type GreeterService interface {
+	// Greet makes a greeting.
+	Greet(GreetRequest) GreetResponse
+}
+
+// BrokenService has an invalid method.
+type BrokenService interface {
+	// Break takes two request objects.
+	Break(GreetRequest, GreetRequest) GreetResponse
+}
+
+// GreetRequest is the request for GreeterService.Greet.
+type GreetRequest struct {
+	// Name is who to greet.
+	Name string
+	// Polite is whether to be polite.
+	// required: maybe
+	Polite bool
+	// Times is how many times to greet.
+	Times int
+}
+
+// GreetResponse is the response for GreeterService.Greet.
+type GreetResponse struct {
+	// Greeting is the greeting.
+	Greeting string
+	secret   string
+}
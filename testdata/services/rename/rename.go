@@ -0,0 +1,31 @@
+package rename
+
+// ProxyService manages proxies.
+// oto:rename "Proxies"
+type ProxyService interface {
+	// GetHTTPSProxy gets the HTTPS proxy.
+	// oto:rename "GetHttpsProxy"
+	GetHTTPSProxy(GetHTTPSProxyRequest) GetHTTPSProxyResponse
+}
+
+// GetHTTPSProxyRequest is the request for Proxies.GetHttpsProxy.
+// oto:rename "GetHttpsProxyRequest"
+type GetHTTPSProxyRequest struct {
+	// Region is where the proxy is.
+	Region string
+}
+
+// GetHTTPSProxyResponse is the response for Proxies.GetHttpsProxy.
+// oto:rename "GetHttpsProxyResponse"
+type GetHTTPSProxyResponse struct {
+	Proxy HTTPSProxy
+}
+
+// HTTPSProxy is a proxy.
+// oto:rename "HttpsProxy"
+type HTTPSProxy struct {
+	// URL is the address of the proxy.
+	URL string
+	// Fallback is used when the proxy is down.
+	Fallback *HTTPSProxy
+}
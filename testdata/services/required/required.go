@@ -0,0 +1,26 @@
+package required
+
+// ProfileService manages profiles.
+type ProfileService interface {
+	// Update updates a profile.
+	Update(UpdateRequest) UpdateResponse
+}
+
+// UpdateRequest is the request for ProfileService.Update.
+type UpdateRequest struct {
+	Name     string
+	Nickname string `json:",omitempty"`
+	Email    string `json:",omitempty" validate:"required,email"`
+	Team     string `json:",omitempty" binding:"required"`
+	Bio      *string
+	// Avatar is the URL of the picture.
+	// required: true
+	Avatar *string
+	// Age is how old they are.
+	// required: false
+	Age  int
+	Tags []string `json:",omitempty" validate:"dive,required"`
+}
+
+// UpdateResponse is the response for ProfileService.Update.
+type UpdateResponse struct{}
@@ -0,0 +1,39 @@
+package routes
+
+// UserService manages users.
+type UserService interface {
+	// Get gets a user.
+	// oto:method get
+	Get(GetRequest) GetResponse
+	// Create creates a user.
+	Create(GetRequest) GetResponse
+	// Fetch fetches a user.
+	// oto:method FETCH
+	Fetch(GetRequest) GetResponse
+	// Delete deletes a user.
+	// oto:route delete /users/:id
+	Delete(GetRequest) GetResponse
+	// Update updates a user.
+	// oto:path /users/{id}/{version}
+	Update(GetRequest) GetResponse
+}
+
+// GetRequest is the request for UserService.Get.
+type GetRequest struct {
+	ID string
+}
+
+// GetResponse is the response for UserService.Get.
+type GetResponse struct {
+	Name string
+}
+
+// TeamService manages teams.
+// oto:prefix /api/v2/
+type TeamService interface {
+	// Get gets a team.
+	// oto:route GET /teams/:id
+	Get(GetRequest) GetResponse
+	// Create creates a team.
+	Create(GetRequest) GetResponse
+}
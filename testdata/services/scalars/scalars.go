@@ -0,0 +1,34 @@
+package scalars
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// EventService manages events.
+type EventService interface {
+	// Create creates an event.
+	Create(CreateRequest) CreateResponse
+}
+
+// CreateRequest is the request object for EventService.Create.
+type CreateRequest struct {
+	// StartsAt is when the event starts.
+	StartsAt time.Time
+	// Reminders are when to send reminders.
+	Reminders []time.Time
+	// Metadata is arbitrary JSON.
+	Metadata json.RawMessage
+	// Price is the price of the event.
+	Price Money
+	// Duration is how long the event lasts.
+	Duration time.Duration
+}
+
+// CreateResponse is the response object for EventService.Create.
+type CreateResponse struct{}
+
+// Money is an amount of money, it has custom JSON encoding.
+type Money struct {
+	amount int64
+}
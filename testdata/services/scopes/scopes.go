@@ -0,0 +1,32 @@
+package scopes
+
+// BillingService manages billing.
+// oto:auth oauth2 scopes:billing:read
+// oto:scopes billing:read
+type BillingService interface {
+	// Invoices gets the invoices.
+	Invoices(InvoicesRequest) InvoicesResponse
+	// Refund refunds an invoice.
+	// oto:scopes admin, billing:write
+	Refund(InvoicesRequest) InvoicesResponse
+}
+
+// ReportService makes reports.
+// oto:auth bearer
+type ReportService interface {
+	// Run runs a report.
+	// oto:scopes reports
+	Run(InvoicesRequest) InvoicesResponse
+	// List lists the reports.
+	List(InvoicesRequest) InvoicesResponse
+}
+
+// InvoicesRequest is the request for BillingService.Invoices.
+type InvoicesRequest struct {
+	AccountID string
+}
+
+// InvoicesResponse is the response for BillingService.Invoices.
+type InvoicesResponse struct {
+	Total int
+}
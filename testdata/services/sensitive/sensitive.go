@@ -0,0 +1,30 @@
+package sensitive
+
+// UserService manages users.
+type UserService interface {
+	// Create creates a user.
+	Create(CreateRequest) CreateResponse
+}
+
+// CreateRequest is the request for UserService.Create.
+type CreateRequest struct {
+	// Email is the email address.
+	Email string
+	// Password is the password.
+	// oto:sensitive
+	Password string
+	SSN      string `oto:"sensitive"`
+}
+
+// CreateResponse is the response for UserService.Create.
+type CreateResponse struct {
+	User User
+}
+
+// User is a user.
+type User struct {
+	Email string
+	// Token is the API token.
+	// oto:sensitive
+	Token string
+}
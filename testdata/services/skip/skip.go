@@ -0,0 +1,45 @@
+package skip
+
+// GreeterService makes greetings.
+type GreeterService interface {
+	// Greet makes a greeting.
+	Greet(GreetRequest) GreetResponse
+}
+
+// AdminService is only used inside the server.
+// oto:skip
+type AdminService interface {
+	// Purge deletes old greetings.
+	Purge(PurgeRequest) PurgeResponse
+}
+
+// GreetRequest is the request for GreeterService.Greet.
+type GreetRequest struct {
+	Name string
+}
+
+// GreetResponse is the response for GreeterService.Greet.
+type GreetResponse struct {
+	Greeting string
+}
+
+// PurgeRequest is the request for AdminService.Purge.
+type PurgeRequest struct {
+	Policy Policy
+}
+
+// PurgeResponse is the response for AdminService.Purge.
+type PurgeResponse struct {
+	Count int
+}
+
+// Policy is how old greetings must be to be purged.
+type Policy struct {
+	Days int
+}
+
+// Cache holds greetings in memory.
+// oto:skip
+type Cache struct {
+	Greetings map[string]string
+}
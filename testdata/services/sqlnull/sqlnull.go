@@ -0,0 +1,25 @@
+package sqlnull
+
+import "database/sql"
+
+// UserService manages users.
+type UserService interface {
+	// Find finds a user.
+	Find(FindRequest) FindResponse
+}
+
+// FindRequest is the request object for UserService.Find.
+type FindRequest struct {
+	// ID is the ID of the user.
+	ID int64
+}
+
+// FindResponse is the response object for UserService.Find.
+type FindResponse struct {
+	// Nickname is the optional nickname.
+	Nickname sql.NullString
+	// Age is the optional age.
+	Age sql.NullInt64
+	// DeletedAt is when the user was deleted.
+	DeletedAt sql.NullTime
+}
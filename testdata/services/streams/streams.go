@@ -0,0 +1,40 @@
+package streams
+
+// EventService streams events.
+type EventService interface {
+	// Watch streams events to the client.
+	// oto:stream
+	Watch(WatchRequest) Event
+	// Upload streams events from the client.
+	// oto:stream client
+	Upload(Event) UploadResponse
+	// Chat streams events both ways.
+	// oto:stream bidi
+	Chat(Event) Event
+	// Count does not stream.
+	Count(WatchRequest) UploadResponse
+	// Tail streams log lines, which can have errors.
+	// oto:streaming
+	// oto:streaming-error
+	Tail(WatchRequest) LogLine
+}
+
+// WatchRequest is the request for EventService.Watch.
+type WatchRequest struct {
+	Topic string
+}
+
+// Event is a single event.
+type Event struct {
+	Message string
+}
+
+// UploadResponse is the response for EventService.Upload.
+type UploadResponse struct {
+	Count int
+}
+
+// LogLine is a line in the log.
+type LogLine struct {
+	Text string
+}
@@ -0,0 +1,29 @@
+package synthesized
+
+import "context"
+
+// ThingService manages things.
+type ThingService interface {
+	// Get gets a thing.
+	Get(ctx context.Context, id string, includeDeleted bool, tags []string, ünits string) GetResponse
+	// Delete deletes a thing.
+	Delete(DeleteRequest) DeleteResponse
+}
+
+// GetResponse is the response for ThingService.Get.
+type GetResponse struct {
+	Thing Thing
+}
+
+// DeleteRequest is the request for ThingService.Delete.
+type DeleteRequest struct {
+	ID string
+}
+
+// DeleteResponse is the response for ThingService.Delete.
+type DeleteResponse struct{}
+
+// Thing is a thing.
+type Thing struct {
+	ID string
+}
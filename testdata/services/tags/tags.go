@@ -0,0 +1,24 @@
+package tags
+
+// UserService manages users.
+type UserService interface {
+	// Create creates a user.
+	Create(CreateRequest) CreateResponse
+}
+
+// CreateRequest is the request for UserService.Create.
+type CreateRequest struct {
+	UserID   string `json:"user_id"`
+	ID       string `json:"id,omitempty"`
+	Nickname string `json:",omitempty"`
+	Email    string
+	Password string `json:"-"`
+	Dash     string `json:"-,"`
+	// ExternalID has a name that is not a JavaScript identifier.
+	ExternalID string `json:"external-id"`
+	// secret would be an error if it was not skipped.
+	secret chan string `json:"-"`
+}
+
+// CreateResponse is the response for UserService.Create.
+type CreateResponse struct{}
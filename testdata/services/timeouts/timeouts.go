@@ -0,0 +1,27 @@
+package timeouts
+
+// ReportService makes reports.
+// oto:timeout 30s
+type ReportService interface {
+	// Run runs a report, which can take a while.
+	// oto:timeout 5m
+	Run(ReportRequest) ReportResponse
+	// Get gets a report.
+	Get(ReportRequest) ReportResponse
+}
+
+// PingService checks the API is up.
+type PingService interface {
+	// Ping pings the API.
+	Ping(ReportRequest) ReportResponse
+}
+
+// ReportRequest is the request for ReportService.Run.
+type ReportRequest struct {
+	ID string
+}
+
+// ReportResponse is the response for ReportService.Run.
+type ReportResponse struct {
+	Rows int
+}
@@ -0,0 +1,21 @@
+package unexported
+
+import "sync"
+
+// CacheService caches things.
+type CacheService interface {
+	// Get gets a cached value.
+	Get(GetRequest) GetResponse
+}
+
+// GetRequest is the request for CacheService.Get.
+type GetRequest struct {
+	Key string
+}
+
+// GetResponse is the response for CacheService.Get.
+type GetResponse struct {
+	Value string
+	mu    sync.Mutex
+	cache map[string]string
+}
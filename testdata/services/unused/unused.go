@@ -0,0 +1,32 @@
+package unused
+
+// ItemService manages items.
+type ItemService interface {
+	// List lists items.
+	List(ListRequest) ListResponse
+}
+
+// ListRequest is the request object for ItemService.List.
+type ListRequest struct{}
+
+// ListResponse is the response object for ItemService.List.
+type ListResponse struct {
+	// Items are items by ID.
+	Items map[string][]Item
+}
+
+// Item is reachable through a map of slices.
+type Item struct {
+	Name string
+}
+
+// Orphan is not used by any service.
+type Orphan struct {
+	Name string
+}
+
+// Kept is not used by any service, but is kept.
+// oto:used
+type Kept struct {
+	Name string
+}
@@ -0,0 +1,21 @@
+package validation
+
+// SignupService signs people up.
+type SignupService interface {
+	// Signup signs someone up.
+	Signup(SignupRequest) SignupResponse
+}
+
+// SignupRequest is the request for SignupService.Signup.
+type SignupRequest struct {
+	Name   string   `validate:"required,min=1,max=140"`
+	Email  string   `validate:"required,email"`
+	Age    int      `validate:"gte=18,lte=130"`
+	Color  string   `validate:"oneof=red green"`
+	Tags   []string `validate:"max=5,dive,min=2"`
+	Notes  string
+	Handle string `validate:"required"`
+}
+
+// SignupResponse is the response for SignupService.Signup.
+type SignupResponse struct{}
@@ -0,0 +1,40 @@
+package versions
+
+// PaymentService takes payments.
+// oto:version 2
+type PaymentService interface {
+	// Charge charges a card.
+	Charge(ChargeRequest) ChargeResponse
+}
+
+// InvoiceService sends invoices.
+// oto:version 1
+// oto:prefix /billing
+type InvoiceService interface {
+	// Send sends an invoice.
+	Send(SendRequest) SendResponse
+}
+
+// RefundService issues refunds.
+type RefundService interface {
+	// Issue issues a refund.
+	Issue(IssueRequest) IssueResponse
+}
+
+// ChargeRequest is the request for PaymentService.Charge.
+type ChargeRequest struct{}
+
+// ChargeResponse is the response for PaymentService.Charge.
+type ChargeResponse struct{}
+
+// SendRequest is the request for InvoiceService.Send.
+type SendRequest struct{}
+
+// SendResponse is the response for InvoiceService.Send.
+type SendResponse struct{}
+
+// IssueRequest is the request for RefundService.Issue.
+type IssueRequest struct{}
+
+// IssueResponse is the response for RefundService.Issue.
+type IssueResponse struct{}
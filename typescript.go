@@ -0,0 +1,173 @@
+package oto
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/pkg/errors"
+)
+
+// GenerateTypeScript generates TypeScript interface definitions for
+// the Objects and Services in the Definition.
+func GenerateTypeScript(def Definition) (string, error) {
+	var buf bytes.Buffer
+	buf.WriteString("// Code generated by oto; DO NOT EDIT.\n")
+	if def.Version != "" {
+		fmt.Fprintf(&buf, "// Version: %s\n", def.Version)
+	}
+	buf.WriteString("\n")
+	for _, service := range def.Services {
+		writeTypeScriptComment(&buf, "", service.Comment, service.Deprecated, service.DeprecationMessage)
+		fmt.Fprintf(&buf, "export interface %s {\n", service.Name)
+		for _, method := range service.Methods {
+			writeTypeScriptComment(&buf, "\t", method.Comment, method.Deprecated, method.DeprecationMessage)
+			var params string
+			if method.HasInput {
+				params = method.InputObject.ObjectNameLowerCamel + ": " + method.InputObject.ObjectName
+			}
+			result := "Promise<void>"
+			if method.HasOutput {
+				result = "Promise<" + method.OutputObject.ObjectName + ">"
+			}
+			if method.Streaming {
+				result = "AsyncIterable<" + method.OutputObject.ObjectName + ">"
+			}
+			fmt.Fprintf(&buf, "\t%s(%s): %s;\n", method.NameLowerCamel, params, result)
+		}
+		buf.WriteString("}\n\n")
+	}
+	for _, enum := range def.Enums {
+		writeTypeScriptComment(&buf, "", enum.Comment, false, "")
+		values := make([]string, 0, len(enum.Values))
+		for _, value := range enum.Values {
+			b, err := json.Marshal(value.Value)
+			if err != nil {
+				return "", errors.Wrapf(err, "enum %s", enum.Name)
+			}
+			values = append(values, string(b))
+		}
+		fmt.Fprintf(&buf, "export type %s = %s;\n\n", enum.Name, strings.Join(values, " | "))
+	}
+	for _, object := range def.Objects {
+		writeTypeScriptComment(&buf, "", object.Comment, object.Deprecated, object.DeprecationMessage)
+		fmt.Fprintf(&buf, "export interface %s {\n", object.Name)
+		for _, field := range object.Fields {
+			comment := field.Comment
+			if field.Type.Format != "" {
+				comment = strings.TrimSpace(comment + "\n@format " + field.Type.Format)
+			}
+			if field.HasDefault {
+				defaultValue, err := json.Marshal(field.Default)
+				if err != nil {
+					return "", errors.Wrapf(err, "%s.%s: default", object.Name, field.Name)
+				}
+				comment = strings.TrimSpace(comment + "\n@default " + string(defaultValue))
+			}
+			writeTypeScriptComment(&buf, "\t", comment, field.Deprecated, field.DeprecationMessage)
+			optional := ""
+			if field.OmitEmpty && !field.Required {
+				optional = "?"
+			}
+			typ, err := typeScriptFieldType(field)
+			if err != nil {
+				return "", errors.Wrapf(err, "%s.%s: enum", object.Name, field.Name)
+			}
+			fmt.Fprintf(&buf, "\t%s%s: %s;\n", typeScriptPropertyName(field.JSONName), optional, typ)
+		}
+		buf.WriteString("}\n\n")
+	}
+	return strings.TrimSpace(buf.String()) + "\n", nil
+}
+
+// typeScriptPropertyName gets the name as a TypeScript property name,
+// quoting it if it is not an identifier, like "user-id".
+func typeScriptPropertyName(name string) string {
+	for i, r := range name {
+		if r == '_' || r == '$' || unicode.IsLetter(r) || (i > 0 && unicode.IsDigit(r)) {
+			continue
+		}
+		return strconv.Quote(name)
+	}
+	if name == "" {
+		return `""`
+	}
+	return name
+}
+
+// typeScriptType gets the TypeScript type for the FieldType.
+func typeScriptType(ftype FieldType) string {
+	if isNestedSlice(ftype) {
+		return typeScriptModifiers(typeScriptType(*ftype.ElementType), ftype)
+	}
+	var typ string
+	switch {
+	case len(ftype.OneOf) > 0:
+		typ = strings.Join(ftype.OneOf, " | ")
+	case ftype.IsObject, ftype.IsEnum:
+		typ = ftype.ObjectName
+	case ftype.IsMap:
+		valueType := "any"
+		if ftype.MapValueType != nil {
+			valueType = typeScriptType(*ftype.MapValueType)
+		}
+		typ = "Record<string, " + valueType + ">"
+	case ftype.JSType == "string", ftype.JSType == "number", ftype.JSType == "boolean":
+		typ = ftype.JSType
+	case ftype.JSType == "object":
+		typ = "Record<string, any>"
+	default:
+		typ = "any"
+	}
+	return typeScriptModifiers(typ, ftype)
+}
+
+// typeScriptFieldType gets the TypeScript type for the field, which
+// is a union of the values of its Enum if it has one.
+func typeScriptFieldType(field Field) (string, error) {
+	if len(field.Enum) == 0 {
+		return typeScriptType(field.Type), nil
+	}
+	values := make([]string, 0, len(field.Enum))
+	for _, value := range field.Enum {
+		b, err := json.Marshal(value)
+		if err != nil {
+			return "", err
+		}
+		values = append(values, string(b))
+	}
+	return typeScriptModifiers(strings.Join(values, " | "), field.Type), nil
+}
+
+// typeScriptModifiers makes typ an array and nullable, if
+// the FieldType is.
+func typeScriptModifiers(typ string, ftype FieldType) string {
+	if ftype.Multiple {
+		if strings.Contains(typ, " ") {
+			typ = "(" + typ + ")"
+		}
+		typ += "[]"
+	}
+	if ftype.Nullable && typ != "any" {
+		typ += " | null"
+	}
+	return typ
+}
+
+func writeTypeScriptComment(buf *bytes.Buffer, indent, comment string, deprecated bool, deprecationMessage string) {
+	if deprecated {
+		comment = strings.TrimSpace(comment + "\n@deprecated " + deprecationMessage)
+	}
+	if comment == "" {
+		return
+	}
+	comment = strings.Replace(comment, "*/", "*\\/", -1)
+	buf.WriteString(indent + "/**\n")
+	for _, line := range strings.Split(comment, "\n") {
+		buf.WriteString(strings.TrimRight(indent+" * "+line, " ") + "\n")
+	}
+	buf.WriteString(indent + " */\n")
+}
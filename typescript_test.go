@@ -0,0 +1,221 @@
+package oto
+
+import (
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/matryer/is"
+)
+
+func TestGenerateTypeScript(t *testing.T) {
+	is := is.New(t)
+	parser := NewParser("./testdata/services/pleasantries")
+	parser.ExcludeInterfaces = []string{"Ignorer"}
+	def, err := parser.Parse()
+	is.NoErr(err)
+
+	s, err := GenerateTypeScript(def)
+	is.NoErr(err)
+	for _, should := range []string{
+		"export interface GreeterService {",
+		"\tgreet(greetRequest: GreetRequest): Promise<GreetResponse>;",
+		"export interface GetGreetingsResponse {",
+		"\tgreetings: Greeting[];",
+		"\terror?: string;",
+		"export interface WelcomeRequest {",
+		"\ttimes: number;",
+		"\tnewCustomer: boolean;",
+		"\t/**\n\t * Greet creates a Greeting for one or more people.\n\t */",
+	} {
+		if !strings.Contains(s, should) {
+			t.Errorf("missing: %s", should)
+		}
+	}
+	checkTypeScript(t, s)
+}
+
+func TestGenerateTypeScriptWithoutPayloads(t *testing.T) {
+	is := is.New(t)
+	def, err := NewParser("./testdata/services/payloads").Parse()
+	is.NoErr(err)
+
+	s, err := GenerateTypeScript(def)
+	is.NoErr(err)
+	for _, should := range []string{
+		"\tping(): Promise<void>;",
+		"\treset(resetRequest: ResetRequest): Promise<void>;",
+		"\tstatus(): Promise<StatusResponse>;",
+	} {
+		if !strings.Contains(s, should) {
+			t.Errorf("missing: %s", should)
+		}
+	}
+	checkTypeScript(t, s)
+}
+
+func TestGenerateTypeScriptDeprecated(t *testing.T) {
+	is := is.New(t)
+	def, err := NewParser("./testdata/services/deprecated").Parse()
+	is.NoErr(err)
+
+	s, err := GenerateTypeScript(def)
+	is.NoErr(err)
+	for _, should := range []string{
+		"\t * Deprecated: use Get, which\n\t * is faster.\n\t * @deprecated use Get, which is faster.\n\t */\n\tfind(",
+		" * @deprecated use AccountService instead.\n */\nexport interface LegacyService {",
+		"\t * @deprecated use ID.\n\t */\n\temail: string;",
+		" * @deprecated\n */\nexport interface GetResponse {",
+	} {
+		if !strings.Contains(s, should) {
+			t.Errorf("missing: %s\n%s", should, s)
+		}
+	}
+	checkTypeScript(t, s)
+}
+
+func TestGenerateTypeScriptFormats(t *testing.T) {
+	is := is.New(t)
+	parser := NewParser("./testdata/services/scalars")
+	parser.TypeOverrides["github.com/pacedotdev/oto/testdata/services/scalars.Money"] = TypeOverride{JSType: "string"}
+	def, err := parser.Parse()
+	is.NoErr(err)
+
+	s, err := GenerateTypeScript(def)
+	is.NoErr(err)
+	for _, should := range []string{
+		"\t/**\n\t * Duration is how long the event lasts.\n\t * @format duration\n\t */\n\tduration: string;",
+		"\t * @format date-time\n\t */\n\tstartsAt: string;",
+	} {
+		if !strings.Contains(s, should) {
+			t.Errorf("missing: %s", should)
+		}
+	}
+	checkTypeScript(t, s)
+}
+
+func TestGenerateTypeScriptStreaming(t *testing.T) {
+	is := is.New(t)
+	def, err := NewParser("./testdata/services/streams").Parse()
+	is.NoErr(err)
+
+	s, err := GenerateTypeScript(def)
+	is.NoErr(err)
+	for _, should := range []string{
+		"\twatch(watchRequest: WatchRequest): AsyncIterable<Event>;",
+		"\tupload(event: Event): Promise<UploadResponse>;",
+		"\tcount(watchRequest: WatchRequest): Promise<UploadResponse>;",
+	} {
+		if !strings.Contains(s, should) {
+			t.Errorf("missing: %s", should)
+		}
+	}
+	checkTypeScript(t, s)
+}
+
+func TestGenerateTypeScriptTypes(t *testing.T) {
+	is := is.New(t)
+	parser := NewParser("./testdata/services/nullable")
+	def, err := parser.Parse()
+	is.NoErr(err)
+	s, err := GenerateTypeScript(def)
+	is.NoErr(err)
+	is.True(strings.Contains(s, "\tbio: string | null;"))
+	is.True(strings.Contains(s, "\tnickname: string | null;"))
+	is.True(strings.Contains(s, "\taddress: Address | null;"))
+	checkTypeScript(t, s)
+
+	parser = NewParser("./testdata/services/jstypes")
+	def, err = parser.Parse()
+	is.NoErr(err)
+	s, err = GenerateTypeScript(def)
+	is.NoErr(err)
+	is.True(strings.Contains(s, "\tlookup: Record<string, string>;"))
+	is.True(strings.Contains(s, "\tany: any;"))
+	checkTypeScript(t, s)
+}
+
+func TestGenerateTypeScriptEnums(t *testing.T) {
+	is := is.New(t)
+	def, err := NewParser("./testdata/services/enums").Parse()
+	is.NoErr(err)
+
+	s, err := GenerateTypeScript(def)
+	is.NoErr(err)
+	for _, should := range []string{
+		"/**\n * Priority is how urgent a task is.\n */\nexport type Priority = 0 | 1 | 2;",
+		"export type Status = \"active\" | \"inactive\" | \"done\";",
+		"\tstatus: Status;",
+		"\tprevious: Status[];",
+		"\tpriority: Priority | null;",
+		"\tlabels: string[];",
+	} {
+		if !strings.Contains(s, should) {
+			t.Errorf("missing: %s", should)
+		}
+	}
+	checkTypeScript(t, s)
+}
+
+func TestGenerateTypeScriptJSONNames(t *testing.T) {
+	is := is.New(t)
+	def, err := NewParser("./testdata/services/tags").Parse()
+	is.NoErr(err)
+
+	s, err := GenerateTypeScript(def)
+	is.NoErr(err)
+	is.True(strings.Contains(s, "\tuser_id: string;"))
+	is.True(strings.Contains(s, "\tid?: string;")) // omitempty
+	is.True(strings.Contains(s, "\temail: string;"))
+	is.True(strings.Contains(s, "\t\"-\": string;"))           // json:"-,"
+	is.True(strings.Contains(s, "\t\"external-id\": string;")) // not an identifier
+	checkTypeScript(t, s)
+}
+
+func TestTypeScriptPropertyName(t *testing.T) {
+	is := is.New(t)
+	is.Equal(typeScriptPropertyName("userID"), "userID")
+	is.Equal(typeScriptPropertyName("user_id"), "user_id")
+	is.Equal(typeScriptPropertyName("$ref"), "$ref")
+	is.Equal(typeScriptPropertyName("line2"), "line2")
+	is.Equal(typeScriptPropertyName("user-id"), `"user-id"`)
+	is.Equal(typeScriptPropertyName("2fa"), `"2fa"`)
+	is.Equal(typeScriptPropertyName(""), `""`)
+}
+
+func TestGenerateTypeScriptOneOf(t *testing.T) {
+	is := is.New(t)
+	def, err := NewParser("./testdata/services/oneof").Parse()
+	is.NoErr(err)
+
+	s, err := GenerateTypeScript(def)
+	is.NoErr(err)
+	is.True(strings.Contains(s, "\tmethod: Card | BankTransfer;"))
+	is.True(strings.Contains(s, "\tfallbacks: (Card | Voucher)[];"))
+}
+
+// checkTypeScript type checks the source with tsc, if it is
+// installed.
+func checkTypeScript(t *testing.T, src string) {
+	tsc, err := exec.LookPath("tsc")
+	if err != nil {
+		t.Log("tsc not installed, skipping type check")
+		return
+	}
+	dir, err := ioutil.TempDir("", "oto-typescript")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	filename := filepath.Join(dir, "oto.gen.ts")
+	if err := ioutil.WriteFile(filename, []byte(src), 0644); err != nil {
+		t.Fatal(err)
+	}
+	out, err := exec.Command(tsc, "--noEmit", "--strict", "--target", "es2018", filename).CombinedOutput()
+	if err != nil {
+		t.Errorf("tsc: %s\n%s", err, out)
+	}
+}
@@ -0,0 +1,55 @@
+package oto
+
+import (
+	"encoding/json"
+	"sort"
+
+	"github.com/pkg/errors"
+	"gopkg.in/yaml.v3"
+)
+
+// MarshalYAML gets a YAML friendly representation of the
+// Definition.
+// Keys match the JSON field names, and services and objects are
+// sorted by name so the output is deterministic.
+func (d Definition) MarshalYAML() (interface{}, error) {
+	sorted := d
+	sorted.Services = make([]Service, len(d.Services))
+	copy(sorted.Services, d.Services)
+	sort.SliceStable(sorted.Services, func(i, j int) bool {
+		return sorted.Services[i].Name < sorted.Services[j].Name
+	})
+	sorted.Objects = make([]Object, len(d.Objects))
+	copy(sorted.Objects, d.Objects)
+	sort.SliceStable(sorted.Objects, func(i, j int) bool {
+		return sorted.Objects[i].Name < sorted.Objects[j].Name
+	})
+	// go via JSON so the YAML keys match the JSON ones
+	b, err := json.Marshal(sorted)
+	if err != nil {
+		return nil, err
+	}
+	var v interface{}
+	if err := json.Unmarshal(b, &v); err != nil {
+		return nil, err
+	}
+	return v, nil
+}
+
+// UnmarshalYAML parses a Definition from YAML produced
+// by Definition.MarshalYAML.
+func UnmarshalYAML(data []byte) (Definition, error) {
+	var def Definition
+	var v interface{}
+	if err := yaml.Unmarshal(data, &v); err != nil {
+		return def, errors.Wrap(err, "yaml")
+	}
+	b, err := json.Marshal(v)
+	if err != nil {
+		return def, err
+	}
+	if err := json.Unmarshal(b, &def); err != nil {
+		return def, err
+	}
+	return def, nil
+}
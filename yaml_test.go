@@ -0,0 +1,38 @@
+package oto
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/matryer/is"
+	"gopkg.in/yaml.v3"
+)
+
+func TestDefinitionYAML(t *testing.T) {
+	is := is.New(t)
+	parser := NewParser("./testdata/services/pleasantries")
+	parser.ExcludeInterfaces = []string{"Ignorer"}
+	def, err := parser.Parse()
+	is.NoErr(err)
+
+	b, err := yaml.Marshal(def)
+	is.NoErr(err)
+	s := string(b)
+	is.True(strings.Contains(s, "packageName: pleasantries"))
+	is.True(strings.Index(s, "name: GetGreetingsRequest") < strings.Index(s, "name: Greeting\n"))
+
+	// output is deterministic
+	b2, err := yaml.Marshal(def)
+	is.NoErr(err)
+	is.Equal(s, string(b2))
+
+	def2, err := UnmarshalYAML(b)
+	is.NoErr(err)
+	is.Equal(def2.PackageName, def.PackageName)
+	is.Equal(len(def2.Services), len(def.Services))
+	is.Equal(len(def2.Objects), len(def.Objects))
+	is.Equal(def2.Imports, def.Imports)
+	welcomeRequest, err := def2.Object("WelcomeRequest")
+	is.NoErr(err)
+	is.Equal(welcomeRequest.Fields[0].Example, "your@email.com")
+}